@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	boundarydto "casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/utils"
+)
+
+const (
+	anonymousActor                = "anonymous"
+	auditActionCancelTransaction  = "admin.transaction.cancel"
+	auditActionCreateExchangeRate = "admin.exchange_rate.create"
+	auditActionImportExchangeRate = "admin.exchange_rate.import"
+)
+
+type AdminHandler struct {
+	transactionUseCase  usecase.TransactionUseCase
+	exchangeRateUseCase usecase.ExchangeRateUseCase
+	auditUseCase        usecase.AuditUseCase
+	logger              logging.Logger
+}
+
+func NewAdminHandler(transactionUseCase usecase.TransactionUseCase, exchangeRateUseCase usecase.ExchangeRateUseCase, auditUseCase usecase.AuditUseCase, logger logging.Logger) *AdminHandler {
+	return &AdminHandler{
+		transactionUseCase:  transactionUseCase,
+		exchangeRateUseCase: exchangeRateUseCase,
+		auditUseCase:        auditUseCase,
+		logger:              logger,
+	}
+}
+
+type cancelTransactionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type exchangeRateRequest struct {
+	Currency    string      `json:"currency"`
+	Rate        json.Number `json:"rate"`
+	EffectiveAt time.Time   `json:"effective_at"`
+}
+
+// CancelTransaction godoc
+// @Summary Cancel a transaction
+// @Description Void a previous transaction by writing a compensating rollback linked to it. The original transaction is left unchanged.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Original transaction ID"
+// @Security AdminToken
+// @Param request body cancelTransactionRequest false "Cancellation reason"
+// @Success 201 {object} json.TransactionResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Already Cancelled"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/transactions/{id}/cancel [post]
+func (h *AdminHandler) CancelTransaction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var request cancelTransactionRequest
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &request); err != nil {
+			h.audit(r, auditActionCancelTransaction, body, &utils.TransactionValidationError{Reason: err.Error()})
+			h.logger.Error(r.Context(), err)
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	originalID := r.PathValue("id")
+	if originalID == "" {
+		h.logger.Error(r.Context(), fmt.Errorf("transaction id is required"))
+		http.Error(w, "transaction id is required", http.StatusBadRequest)
+		return
+	}
+
+	payload, _ := json.Marshal(map[string]string{
+		"original_transaction_id": originalID,
+		"reason":                  request.Reason,
+	})
+
+	rollback, err := h.transactionUseCase.CancelTransaction(&boundarydto.CancelTransactionDTO{
+		OriginalTransactionID: originalID,
+		Reason:                request.Reason,
+	})
+	h.audit(r, auditActionCancelTransaction, payload, err)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), cancelErrorStatus(err))
+		return
+	}
+
+	response := adapterjson.TransactionResponse{}
+	response.FromDto(rollback)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		return
+	}
+}
+
+// CreateExchangeRate godoc
+// @Summary Add an exchange rate
+// @Description Load the rate of a currency against the reporting currency (EUR) from the given time on. Loading a rate for an existing currency and time corrects it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param request body exchangeRateRequest true "Exchange rate"
+// @Success 201 {object} json.ExchangeRateResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/exchange-rates [post]
+func (h *AdminHandler) CreateExchangeRate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	var request exchangeRateRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		h.audit(r, auditActionCreateExchangeRate, body, &utils.ExchangeRateValidationError{Reason: err.Error()})
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	rate := &boundarydto.ExchangeRateDTO{
+		Currency:    request.Currency,
+		Rate:        request.Rate.String(),
+		EffectiveAt: request.EffectiveAt,
+	}
+
+	err = h.exchangeRateUseCase.AddRates([]*boundarydto.ExchangeRateDTO{rate})
+	h.audit(r, auditActionCreateExchangeRate, body, err)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), exchangeRateErrorStatus(err))
+		return
+	}
+
+	response := adapterjson.ExchangeRateResponse{}
+	response.FromDto(rate)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		return
+	}
+}
+
+// ImportExchangeRates godoc
+// @Summary Import exchange rates from CSV
+// @Description Load a batch of rates from a CSV body with the columns currency, rate and effective_at (RFC3339). A header row is optional. The batch is rejected as a whole if any row is invalid.
+// @Tags admin
+// @Accept text/csv
+// @Produce json
+// @Security AdminToken
+// @Success 201 {object} json.ExchangeRateImportResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/exchange-rates/import [post]
+func (h *AdminHandler) ImportExchangeRates(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	rates, err := parseExchangeRatesCSV(strings.NewReader(string(body)))
+	if err == nil {
+		err = h.exchangeRateUseCase.AddRates(rates)
+	}
+	h.audit(r, auditActionImportExchangeRate, body, err)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), exchangeRateErrorStatus(err))
+		return
+	}
+
+	response := adapterjson.ExchangeRateImportResponse{Imported: len(rates)}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		return
+	}
+}
+
+// GetExchangeRates godoc
+// @Summary List exchange rates
+// @Description List loaded exchange rates against the reporting currency, newest first per currency
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param currency query string false "Currency filter"
+// @Success 200 {object} json.ExchangeRatesResponse
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/exchange-rates [get]
+func (h *AdminHandler) GetExchangeRates(w http.ResponseWriter, r *http.Request) {
+	var currency *string
+	if currencyStr := r.URL.Query().Get("currency"); currencyStr != "" {
+		currency = &currencyStr
+	}
+
+	dtos, err := h.exchangeRateUseCase.GetRates(currency)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := adapterjson.ExchangeRatesResponse{}
+	response.FromDtos(dtos)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AdminHandler) audit(r *http.Request, action string, payload []byte, actionErr error) {
+	recordAdminAction(h.auditUseCase, h.logger, r, action, payload, actionErr)
+}
+
+// recordAdminAction audits an admin request as the operator its token was
+// issued to, see middleware.AuthMiddleware.
+func recordAdminAction(auditUseCase usecase.AuditUseCase, logger logging.Logger, r *http.Request, action string, payload []byte, actionErr error) {
+	actor, _ := r.Context().Value(utils.CtxKeyActor).(string)
+	if actor == "" {
+		actor = anonymousActor
+	}
+
+	requestID, _ := r.Context().Value(utils.CtxKeyRequestID).(string)
+	err := auditUseCase.Record(&boundarydto.CreateAuditRecordDTO{
+		Actor:   actor,
+		Action:  action,
+		Source:  "http:" + requestID,
+		Payload: payload,
+		Err:     actionErr,
+	})
+	if err != nil {
+		logger.Error(r.Context(), err)
+	}
+}
+
+func cancelErrorStatus(err error) int {
+	switch {
+	case utils.IsTransactionNotFound(err):
+		return http.StatusNotFound
+	case utils.IsTransactionAlreadyCancelled(err):
+		return http.StatusConflict
+	case utils.IsTransactionValidation(err):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func exchangeRateErrorStatus(err error) int {
+	if utils.IsExchangeRateValidation(err) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+func parseExchangeRatesCSV(r io.Reader) ([]*boundarydto.ExchangeRateDTO, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+
+	var rates []*boundarydto.ExchangeRateDTO
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &utils.ExchangeRateValidationError{Reason: err.Error()}
+		}
+
+		if line == 1 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		effectiveAt, err := time.Parse(time.RFC3339, record[2])
+		if err != nil {
+			return nil, &utils.ExchangeRateValidationError{
+				Currency: record[0],
+				Reason:   fmt.Sprintf("line %d: effective_at must be an RFC3339 timestamp", line),
+			}
+		}
+
+		rates = append(rates, &boundarydto.ExchangeRateDTO{
+			Currency:    record[0],
+			Rate:        record[1],
+			EffectiveAt: effectiveAt,
+		})
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+)
+
+type MockExchangeRateUseCase struct {
+	added      []*boundarydto.ExchangeRateDTO
+	addError   error
+	rates      []*boundarydto.ExchangeRateDTO
+	getError   error
+	lastFilter *string
+}
+
+func (m *MockExchangeRateUseCase) AddRates(rates []*boundarydto.ExchangeRateDTO) error {
+	if m.addError != nil {
+		return m.addError
+	}
+	m.added = append(m.added, rates...)
+	return nil
+}
+
+func (m *MockExchangeRateUseCase) GetRates(currency *string) ([]*boundarydto.ExchangeRateDTO, error) {
+	m.lastFilter = currency
+	if m.getError != nil {
+		return nil, m.getError
+	}
+	return m.rates, nil
+}
+
+func (m *MockExchangeRateUseCase) Convert(amount uint, currency string, at time.Time) (*boundarydto.ConversionDTO, error) {
+	return nil, &utils.ExchangeRateNotFoundError{Currency: currency, At: at}
+}
+
+func newCancelRequest(t *testing.T, id string, body string) *http.Request {
+	req, err := http.NewRequest("POST", "/admin/transactions/"+id+"/cancel", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", id)
+	return req
+}
+
+func TestAdminHandler_CancelTransaction(t *testing.T) {
+	originalID := utils.GenerateUUID()
+	mockUseCase := &MockTransactionUseCase{
+		cancelled: &boundarydto.TransactionDTO{
+			ID:                    utils.GenerateUUID(),
+			UserID:                "user123",
+			TransactionType:       "rollback",
+			Amount:                100,
+			OriginalTransactionID: originalID,
+			Reason:                "provider outage",
+		},
+	}
+	mockAudit := &MockAuditUseCase{}
+	handler := NewAdminHandler(mockUseCase, &MockExchangeRateUseCase{}, mockAudit, &MockLogger{})
+
+	req := newCancelRequest(t, originalID, `{"reason": "provider outage"}`)
+	req = req.WithContext(context.WithValue(req.Context(), utils.CtxKeyActor, "ops@casino"))
+	req = req.WithContext(context.WithValue(req.Context(), utils.CtxKeyRequestID, "req-1"))
+
+	rr := httptest.NewRecorder()
+	handler.CancelTransaction(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d", http.StatusCreated, status)
+	}
+
+	var response struct {
+		TransactionType       string `json:"transaction_type"`
+		OriginalTransactionID string `json:"original_transaction_id"`
+		Reason                string `json:"reason"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.TransactionType != "rollback" || response.OriginalTransactionID != originalID || response.Reason != "provider outage" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	if mockUseCase.lastCancel.OriginalTransactionID != originalID || mockUseCase.lastCancel.Reason != "provider outage" {
+		t.Errorf("Unexpected cancel request %+v", mockUseCase.lastCancel)
+	}
+
+	if len(mockAudit.records) != 1 {
+		t.Fatalf("Expected 1 audit record, got %d", len(mockAudit.records))
+	}
+	record := mockAudit.records[0]
+	if record.Actor != "ops@casino" || record.Source != "http:req-1" || record.Err != nil {
+		t.Errorf("Unexpected audit record %+v", record)
+	}
+}
+
+func TestAdminHandler_CancelTransaction_Errors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		cancelError    error
+		expectedStatus int
+	}{
+		{"Unknown Transaction", "", &utils.TransactionNotFoundError{TransactionID: "tx"}, http.StatusNotFound},
+		{"Already Cancelled", "", &utils.TransactionAlreadyCancelledError{TransactionID: "tx"}, http.StatusConflict},
+		{"Validation Error", "", &utils.TransactionValidationError{TransactionID: "tx", Reason: "bad"}, http.StatusBadRequest},
+		{"Invalid Body", "{", nil, http.StatusBadRequest},
+		{"Use Case Error", "", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAudit := &MockAuditUseCase{}
+			handler := NewAdminHandler(&MockTransactionUseCase{cancelError: tc.cancelError}, &MockExchangeRateUseCase{}, mockAudit, &MockLogger{})
+
+			req := newCancelRequest(t, utils.GenerateUUID(), tc.body)
+			req.Header.Set("X-Actor", "claimed@casino")
+
+			rr := httptest.NewRecorder()
+			handler.CancelTransaction(rr, req)
+
+			if status := rr.Code; status != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, status)
+			}
+
+			if len(mockAudit.records) != 1 {
+				t.Fatalf("Expected 1 audit record, got %d", len(mockAudit.records))
+			}
+			if mockAudit.records[0].Err == nil {
+				t.Error("Expected failure to be audited")
+			}
+			if mockAudit.records[0].Actor != "anonymous" {
+				t.Errorf("Expected the actor header to be ignored without a token, got %s", mockAudit.records[0].Actor)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_CreateExchangeRate(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		rate string
+	}{
+		{"Rate As String", `{"currency": "USD", "rate": "0.920000000000000001", "effective_at": "2026-03-01T00:00:00Z"}`, "0.920000000000000001"},
+		{"Rate As Number", `{"currency": "USD", "rate": 0.92, "effective_at": "2026-03-01T00:00:00Z"}`, "0.92"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRates := &MockExchangeRateUseCase{}
+			mockAudit := &MockAuditUseCase{}
+			handler := NewAdminHandler(&MockTransactionUseCase{}, mockRates, mockAudit, &MockLogger{})
+
+			req, err := http.NewRequest("POST", "/admin/exchange-rates", bytes.NewBufferString(tc.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req = req.WithContext(context.WithValue(req.Context(), utils.CtxKeyActor, "finance@casino"))
+
+			rr := httptest.NewRecorder()
+			handler.CreateExchangeRate(rr, req)
+
+			if status := rr.Code; status != http.StatusCreated {
+				t.Fatalf("Expected status %d, got %d", http.StatusCreated, status)
+			}
+
+			if len(mockRates.added) != 1 || mockRates.added[0].Rate != tc.rate || mockRates.added[0].Currency != "USD" {
+				t.Fatalf("Unexpected rates added %+v", mockRates.added)
+			}
+			if !mockRates.added[0].EffectiveAt.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("Unexpected effective_at %s", mockRates.added[0].EffectiveAt)
+			}
+
+			if len(mockAudit.records) != 1 || mockAudit.records[0].Action != "admin.exchange_rate.create" || mockAudit.records[0].Actor != "finance@casino" {
+				t.Errorf("Expected rate load to be audited, got %+v", mockAudit.records)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_CreateExchangeRate_Errors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		addError       error
+		expectedStatus int
+	}{
+		{"Invalid Body", `{"currency":`, nil, http.StatusBadRequest},
+		{"Validation Error", `{"currency": "XYZ", "rate": "1"}`, &utils.ExchangeRateValidationError{Currency: "XYZ", Reason: "unknown currency"}, http.StatusBadRequest},
+		{"Use Case Error", `{"currency": "USD", "rate": "1"}`, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAudit := &MockAuditUseCase{}
+			handler := NewAdminHandler(&MockTransactionUseCase{}, &MockExchangeRateUseCase{addError: tc.addError}, mockAudit, &MockLogger{})
+
+			req, _ := http.NewRequest("POST", "/admin/exchange-rates", bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+			handler.CreateExchangeRate(rr, req)
+
+			if status := rr.Code; status != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, status)
+			}
+			if len(mockAudit.records) != 1 || mockAudit.records[0].Err == nil {
+				t.Errorf("Expected failure to be audited, got %+v", mockAudit.records)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_ImportExchangeRates(t *testing.T) {
+	mockRates := &MockExchangeRateUseCase{}
+	mockAudit := &MockAuditUseCase{}
+	handler := NewAdminHandler(&MockTransactionUseCase{}, mockRates, mockAudit, &MockLogger{})
+
+	body := "currency,rate,effective_at\nUSD,0.92,2026-03-01T00:00:00Z\nBTC, 60000.5,2026-03-01T00:00:00Z\n"
+	req, _ := http.NewRequest("POST", "/admin/exchange-rates/import", bytes.NewBufferString(body))
+	rr := httptest.NewRecorder()
+	handler.ImportExchangeRates(rr, req)
+
+	if status := rr.Code; status != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, status, rr.Body.String())
+	}
+
+	var response struct {
+		Imported int `json:"imported"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Imported != 2 {
+		t.Errorf("Expected 2 imported rates, got %d", response.Imported)
+	}
+
+	if len(mockRates.added) != 2 || mockRates.added[1].Currency != "BTC" || mockRates.added[1].Rate != "60000.5" {
+		t.Errorf("Unexpected rates added %+v", mockRates.added)
+	}
+
+	if len(mockAudit.records) != 1 || string(mockAudit.records[0].Payload) != body {
+		t.Errorf("Expected the CSV upload to be audited, got %+v", mockAudit.records)
+	}
+}
+
+func TestAdminHandler_ImportExchangeRates_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{"Wrong Column Count", "USD,0.92\n"},
+		{"Bad Timestamp", "USD,0.92,yesterday\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRates := &MockExchangeRateUseCase{}
+			mockAudit := &MockAuditUseCase{}
+			handler := NewAdminHandler(&MockTransactionUseCase{}, mockRates, mockAudit, &MockLogger{})
+
+			req, _ := http.NewRequest("POST", "/admin/exchange-rates/import", bytes.NewBufferString(tc.body))
+			rr := httptest.NewRecorder()
+			handler.ImportExchangeRates(rr, req)
+
+			if status := rr.Code; status != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+			}
+			if len(mockRates.added) != 0 {
+				t.Error("Expected nothing to be added")
+			}
+			if len(mockAudit.records) != 1 || !utils.IsExchangeRateValidation(mockAudit.records[0].Err) {
+				t.Errorf("Expected validation failure to be audited, got %+v", mockAudit.records)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_GetExchangeRates(t *testing.T) {
+	mockRates := &MockExchangeRateUseCase{
+		rates: []*boundarydto.ExchangeRateDTO{
+			{Currency: "USD", Rate: "0.92", EffectiveAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	handler := NewAdminHandler(&MockTransactionUseCase{}, mockRates, &MockAuditUseCase{}, &MockLogger{})
+
+	req, _ := http.NewRequest("GET", "/admin/exchange-rates?currency=USD", nil)
+	rr := httptest.NewRecorder()
+	handler.GetExchangeRates(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if mockRates.lastFilter == nil || *mockRates.lastFilter != "USD" {
+		t.Errorf("Expected currency filter USD, got %v", mockRates.lastFilter)
+	}
+
+	var response struct {
+		Rates []struct {
+			Currency    string `json:"currency"`
+			Rate        string `json:"rate"`
+			EffectiveAt string `json:"effective_at"`
+		} `json:"rates"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Rates) != 1 || response.Rates[0].Rate != "0.92" || response.Rates[0].EffectiveAt != "2026-03-01T00:00:00Z" {
+		t.Errorf("Unexpected response %+v", response)
+	}
+}
+
+func TestAdminHandler_GetExchangeRates_Error(t *testing.T) {
+	handler := NewAdminHandler(&MockTransactionUseCase{}, &MockExchangeRateUseCase{getError: errors.New("database error")}, &MockAuditUseCase{}, &MockLogger{})
+
+	req, _ := http.NewRequest("GET", "/admin/exchange-rates", nil)
+	rr := httptest.NewRecorder()
+	handler.GetExchangeRates(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, status)
+	}
+}
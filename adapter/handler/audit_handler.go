@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+)
+
+type AuditHandler struct {
+	auditUseCase usecase.AuditUseCase
+	logger       logging.Logger
+}
+
+func NewAuditHandler(auditUseCase usecase.AuditUseCase, logger logging.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditUseCase: auditUseCase,
+		logger:       logger,
+	}
+}
+
+// VerifyChain godoc
+// @Summary Verify audit log integrity
+// @Description Recompute the audit log hash chain over a time range and report the first broken record
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param from query string false "Range start (RFC3339), defaults to the beginning of the log"
+// @Param to query string false "Range end (RFC3339), defaults to now"
+// @Success 200 {object} json.AuditVerificationResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /audit/verify [get]
+func (h *AuditHandler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	from, err := parseTimeParam(r, "from", time.Time{})
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseTimeParam(r, "to", time.Now().UTC())
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if to.Before(from) {
+		h.logger.Error(r.Context(), fmt.Errorf("to must not be before from"))
+		http.Error(w, "to must not be before from", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.auditUseCase.VerifyChain(from, to)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := adapterjson.AuditVerificationResponse{}
+	response.FromDto(result)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseTimeParam(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC3339 timestamp", name)
+	}
+	return t, nil
+}
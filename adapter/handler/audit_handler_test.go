@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+type MockAuditUseCase struct {
+	verification *boundarydto.AuditVerificationDTO
+	verifyError  error
+	records      []*boundarydto.CreateAuditRecordDTO
+	lastFrom     time.Time
+	lastTo       time.Time
+}
+
+func (m *MockAuditUseCase) Record(dto *boundarydto.CreateAuditRecordDTO) error {
+	m.records = append(m.records, dto)
+	return nil
+}
+
+func (m *MockAuditUseCase) VerifyChain(from, to time.Time) (*boundarydto.AuditVerificationDTO, error) {
+	m.lastFrom = from
+	m.lastTo = to
+	if m.verifyError != nil {
+		return nil, m.verifyError
+	}
+	return m.verification, nil
+}
+
+func TestAuditHandler_VerifyChain(t *testing.T) {
+	mockUseCase := &MockAuditUseCase{
+		verification: &boundarydto.AuditVerificationDTO{
+			From:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			To:      time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+			Checked: 5,
+			Valid:   true,
+		},
+	}
+	handler := NewAuditHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/audit/verify?from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.VerifyChain(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var response struct {
+		Checked int  `json:"checked"`
+		Valid   bool `json:"valid"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if !response.Valid || response.Checked != 5 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	if !mockUseCase.lastFrom.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected from to be parsed, got %s", mockUseCase.lastFrom)
+	}
+}
+
+func TestAuditHandler_VerifyChain_DefaultRange(t *testing.T) {
+	mockUseCase := &MockAuditUseCase{verification: &boundarydto.AuditVerificationDTO{Valid: true}}
+	handler := NewAuditHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/audit/verify", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.VerifyChain(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if !mockUseCase.lastFrom.IsZero() {
+		t.Errorf("Expected from to default to zero time, got %s", mockUseCase.lastFrom)
+	}
+
+	if mockUseCase.lastTo.IsZero() {
+		t.Error("Expected to to default to now")
+	}
+}
+
+func TestAuditHandler_VerifyChain_InvalidParams(t *testing.T) {
+	testCases := []struct {
+		name string
+		url  string
+	}{
+		{"Invalid From", "/audit/verify?from=yesterday"},
+		{"Invalid To", "/audit/verify?to=2026-13-01"},
+		{"To Before From", "/audit/verify?from=2026-01-02T00:00:00Z&to=2026-01-01T00:00:00Z"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewAuditHandler(&MockAuditUseCase{}, &MockLogger{})
+
+			req, err := http.NewRequest("GET", tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.VerifyChain(rr, req)
+
+			if status := rr.Code; status != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+			}
+		})
+	}
+}
+
+func TestAuditHandler_VerifyChain_UseCaseError(t *testing.T) {
+	handler := NewAuditHandler(&MockAuditUseCase{verifyError: errors.New("database error")}, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/audit/verify", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.VerifyChain(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, status)
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	boundarydto "casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+type BalanceSocketHandler struct {
+	transactionUseCase usecase.TransactionUseCase
+	feed               usecase.TransactionFeed
+	authUseCase        usecase.AuthUseCase
+	logger             logging.Logger
+	upgrader           *websocket.Upgrader
+	// allowedOrigins lists the browser origins, besides the service's own,
+	// that may open a socket, e.g. "https://lobby.example.com".
+	allowedOrigins map[string]bool
+
+	pingInterval time.Duration
+	// pongWait is how long a connection may stay silent, so it must be
+	// longer than pingInterval.
+	pongWait     time.Duration
+	writeTimeout time.Duration
+	// queueSize bounds the updates waiting for a slow client. Every update
+	// carries the full balance, so the oldest is dropped when it is full.
+	queueSize int
+}
+
+func NewBalanceSocketHandler(transactionUseCase usecase.TransactionUseCase, feed usecase.TransactionFeed, authUseCase usecase.AuthUseCase, allowedOrigins []string, logger logging.Logger) *BalanceSocketHandler {
+	h := &BalanceSocketHandler{
+		transactionUseCase: transactionUseCase,
+		feed:               feed,
+		authUseCase:        authUseCase,
+		logger:             logger,
+		allowedOrigins:     make(map[string]bool, len(allowedOrigins)),
+		pingInterval:       30 * time.Second,
+		pongWait:           60 * time.Second,
+		writeTimeout:       10 * time.Second,
+		queueSize:          8,
+	}
+	for _, origin := range allowedOrigins {
+		h.allowedOrigins[strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))] = true
+	}
+	h.upgrader = &websocket.Upgrader{
+		HandshakeTimeout: h.writeTimeout,
+		CheckOrigin:      h.checkOrigin,
+	}
+	return h
+}
+
+// checkOrigin accepts clients that send no Origin, which browsers always do,
+// pages served by the service itself and the configured origins. Anything
+// else could be a foreign page riding on a token it got hold of.
+func (h *BalanceSocketHandler) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(parsed.Host, r.Host) {
+		return true
+	}
+	return h.allowedOrigins[strings.ToLower(origin)]
+}
+
+// StreamBalances godoc
+// @Summary Balance updates socket
+// @Description Upgrade to a WebSocket that receives the user's balances on connect and again, together with the transaction, after every transaction processed for the user. Authenticate with a user token as a Bearer Authorization header or, for browsers, the token query parameter
+// @Tags users
+// @Param id path string true "User ID"
+// @Param token query string false "User token"
+// @Success 101 {object} json.BalanceUpdateResponse "Switching Protocols"
+// @Failure 400 {object} map[string]string "Not a WebSocket handshake"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden, or an Origin that is not allowed"
+// @Router /users/{id}/balance/ws [get]
+func (h *BalanceSocketHandler) StreamBalances(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+
+	token := r.URL.Query().Get("token")
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		token = strings.TrimPrefix(header, "Bearer ")
+	}
+	if token == "" {
+		h.logger.Error(r.Context(), fmt.Errorf("missing user token"))
+		http.Error(w, "user token is required", http.StatusUnauthorized)
+		return
+	}
+
+	authenticatedUserID, err := h.authUseCase.AuthenticateUser(token)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		if utils.IsUnauthorized(err) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	if authenticatedUserID != userID {
+		h.logger.Error(r.Context(), fmt.Errorf("token for user %s used for user %s", authenticatedUserID, userID))
+		http.Error(w, "token does not belong to this user", http.StatusForbidden)
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		return
+	}
+	defer conn.Close()
+
+	h.serve(r.Context(), conn, userID)
+}
+
+func (h *BalanceSocketHandler) serve(ctx context.Context, conn *websocket.Conn, userID string) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Clients have nothing to say, but reading is what answers their pings
+	// and notices pongs and closes.
+	conn.SetReadLimit(4096)
+	_ = conn.SetReadDeadline(time.Now().Add(h.pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(h.pongWait))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	updates := make(chan *adapterjson.BalanceUpdateResponse, h.queueSize)
+	go h.produceUpdates(ctx, userID, updates)
+
+	ping := time.NewTicker(h.pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(time.Second))
+			return
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(h.writeTimeout)); err != nil {
+				return
+			}
+		case update := <-updates:
+			data, err := json.Marshal(update)
+			if err != nil {
+				h.logger.Error(ctx, err)
+				continue
+			}
+			_ = conn.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				h.logger.Error(ctx, fmt.Errorf("balance socket for user %s: %w", userID, err))
+				return
+			}
+		}
+	}
+}
+
+// produceUpdates turns the user's transactions into balance updates until ctx
+// is done.
+func (h *BalanceSocketHandler) produceUpdates(ctx context.Context, userID string, updates chan *adapterjson.BalanceUpdateResponse) {
+	filter := &boundarydto.TransactionStreamFilterDTO{UserID: &userID}
+	events, unsubscribe := h.feed.Subscribe(filter, 0)
+	defer func() { unsubscribe() }()
+
+	h.pushUpdate(ctx, userID, nil, updates)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				// The feed dropped us for falling behind. A fresh balance
+				// makes up for whatever was missed.
+				unsubscribe()
+				events, unsubscribe = h.feed.Subscribe(filter, 0)
+				h.pushUpdate(ctx, userID, nil, updates)
+				continue
+			}
+			// Every update costs a balance query, so events of other
+			// users are dropped here even if the feed let them through.
+			if !filter.Matches(event.Transaction) {
+				continue
+			}
+			h.pushUpdate(ctx, userID, event.Transaction, updates)
+		}
+	}
+}
+
+func (h *BalanceSocketHandler) pushUpdate(ctx context.Context, userID string, transaction *boundarydto.TransactionDTO, updates chan *adapterjson.BalanceUpdateResponse) {
+	balances, err := h.transactionUseCase.GetBalances(userID)
+	if err != nil {
+		h.logger.Error(ctx, err)
+		return
+	}
+
+	update := &adapterjson.BalanceUpdateResponse{}
+	update.FromDtos(userID, balances, transaction)
+
+	for {
+		select {
+		case updates <- update:
+			return
+		default:
+		}
+		select {
+		case <-updates:
+		default:
+		}
+	}
+}
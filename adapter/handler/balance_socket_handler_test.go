@@ -0,0 +1,200 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+type MockAuthUseCase struct {
+	users map[string]string
+}
+
+func (m *MockAuthUseCase) IssueUserToken(userID string, ttl time.Duration) string {
+	return ""
+}
+
+func (m *MockAuthUseCase) AuthenticateUser(token string) (string, error) {
+	userID, ok := m.users[token]
+	if !ok {
+		return "", &utils.UnauthorizedError{Reason: "invalid token signature"}
+	}
+	return userID, nil
+}
+
+func dialBalanceSocket(t *testing.T, server *httptest.Server, path string, header http.Header) *websocket.Conn {
+	t.Helper()
+	conn, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+path, header)
+	if err != nil {
+		status := 0
+		if response != nil {
+			status = response.StatusCode
+		}
+		t.Fatalf("Failed to connect (status %d): %v", status, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func readUpdate(t *testing.T, conn *websocket.Conn) *adapterjson.BalanceUpdateResponse {
+	t.Helper()
+	messageType, payload, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("Failed to read update: %v", err)
+	}
+	if messageType != websocket.TextMessage {
+		t.Fatalf("Expected a text message, got type %d", messageType)
+	}
+
+	update := &adapterjson.BalanceUpdateResponse{}
+	if err := json.Unmarshal(payload, update); err != nil {
+		t.Fatalf("Failed to decode update: %v", err)
+	}
+	return update
+}
+
+func newBalanceSocketServer(handler *BalanceSocketHandler) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}/balance/ws", handler.StreamBalances)
+	return httptest.NewServer(mux)
+}
+
+func TestBalanceSocketHandler_StreamBalances(t *testing.T) {
+	feed := &MockTransactionFeed{events: make(chan *boundarydto.TransactionEventDTO, 1)}
+	mockUseCase := &MockTransactionUseCase{
+		balances: []*boundarydto.BalanceDTO{{Currency: "EUR", Balance: 1000, Formatted: "10.00 EUR"}},
+	}
+	handler := NewBalanceSocketHandler(mockUseCase, feed, &MockAuthUseCase{users: map[string]string{"token-1": "user-1"}}, nil, &MockLogger{})
+	server := newBalanceSocketServer(handler)
+	defer server.Close()
+
+	conn := dialBalanceSocket(t, server, "/users/user-1/balance/ws?token=token-1", nil)
+
+	initial := readUpdate(t, conn)
+	if initial.Type != "balance" || initial.UserID != "user-1" || initial.Balances[0].Balance != 1000 {
+		t.Errorf("Unexpected initial update %+v", initial)
+	}
+	if initial.LastTransaction != nil {
+		t.Error("Expected no transaction in the initial update")
+	}
+
+	feed.events <- &boundarydto.TransactionEventDTO{ID: 1, Transaction: &boundarydto.TransactionDTO{ID: "tx-0", UserID: "user-2", TransactionType: "bet", Amount: 100}}
+	feed.events <- &boundarydto.TransactionEventDTO{ID: 2, Transaction: &boundarydto.TransactionDTO{ID: "tx-1", UserID: "user-1", TransactionType: "bet", Amount: 200}}
+
+	update := readUpdate(t, conn)
+	if update.LastTransaction == nil || update.LastTransaction.ID != "tx-1" {
+		t.Errorf("Expected the update to carry tx-1, got %+v", update.LastTransaction)
+	}
+}
+
+func TestBalanceSocketHandler_Ping(t *testing.T) {
+	feed := &MockTransactionFeed{events: make(chan *boundarydto.TransactionEventDTO)}
+	handler := NewBalanceSocketHandler(&MockTransactionUseCase{}, feed, &MockAuthUseCase{users: map[string]string{"token-1": "user-1"}}, nil, &MockLogger{})
+	handler.pingInterval = 10 * time.Millisecond
+	server := newBalanceSocketServer(handler)
+	defer server.Close()
+
+	conn := dialBalanceSocket(t, server, "/users/user-1/balance/ws?token=token-1", nil)
+	pinged := make(chan struct{}, 1)
+	conn.SetPingHandler(func(string) error {
+		select {
+		case pinged <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	readUpdate(t, conn)
+
+	// Reading is what runs the ping handler; no further update arrives.
+	go conn.ReadMessage()
+	select {
+	case <-pinged:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected a ping")
+	}
+}
+
+func TestBalanceSocketHandler_Origin(t *testing.T) {
+	testCases := []struct {
+		name       string
+		origin     string
+		sameOrigin bool
+		allowed    bool
+	}{
+		{"No Origin", "", false, true},
+		{"Same Origin", "", true, true},
+		{"Allowed Origin", "https://lobby.example.com", false, true},
+		{"Foreign Origin", "https://evil.example.com", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := &MockTransactionFeed{events: make(chan *boundarydto.TransactionEventDTO)}
+			handler := NewBalanceSocketHandler(&MockTransactionUseCase{}, feed, &MockAuthUseCase{users: map[string]string{"token-1": "user-1"}}, []string{"https://lobby.example.com/"}, &MockLogger{})
+			server := newBalanceSocketServer(handler)
+			defer server.Close()
+
+			header := http.Header{}
+			if tc.sameOrigin {
+				header.Set("Origin", server.URL)
+			} else if tc.origin != "" {
+				header.Set("Origin", tc.origin)
+			}
+
+			url := "ws" + strings.TrimPrefix(server.URL, "http") + "/users/user-1/balance/ws?token=token-1"
+			conn, response, err := websocket.DefaultDialer.Dial(url, header)
+			if conn != nil {
+				conn.Close()
+			}
+			if tc.allowed && err != nil {
+				t.Errorf("Expected the socket to open, got %v", err)
+			}
+			if !tc.allowed && (err == nil || response == nil || response.StatusCode != http.StatusForbidden) {
+				t.Errorf("Expected the origin to be rejected with 403, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBalanceSocketHandler_Rejected(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		authorization  string
+		expectedStatus int
+	}{
+		{"Missing Token", "/users/user-1/balance/ws", "", http.StatusUnauthorized},
+		{"Invalid Token", "/users/user-1/balance/ws?token=forged", "", http.StatusUnauthorized},
+		{"Other User", "/users/user-2/balance/ws?token=token-1", "", http.StatusForbidden},
+		{"Not An Upgrade", "/users/user-1/balance/ws", "Bearer token-1", http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewBalanceSocketHandler(&MockTransactionUseCase{}, &MockTransactionFeed{}, &MockAuthUseCase{users: map[string]string{"token-1": "user-1"}}, nil, &MockLogger{})
+
+			req := httptest.NewRequest("GET", tc.path, nil)
+			req.SetPathValue("id", strings.Split(tc.path, "/")[2])
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+			handler.StreamBalances(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
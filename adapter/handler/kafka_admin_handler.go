@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	boundarydto "casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+)
+
+const (
+	auditActionPauseConsumer  = "admin.kafka.pause"
+	auditActionResumeConsumer = "admin.kafka.resume"
+)
+
+type KafkaAdminHandler struct {
+	consumerAdmin usecase.ConsumerAdmin
+	auditUseCase  usecase.AuditUseCase
+	logger        logging.Logger
+	drainTimeout  time.Duration
+}
+
+func NewKafkaAdminHandler(consumerAdmin usecase.ConsumerAdmin, auditUseCase usecase.AuditUseCase, logger logging.Logger) *KafkaAdminHandler {
+	return &KafkaAdminHandler{
+		consumerAdmin: consumerAdmin,
+		auditUseCase:  auditUseCase,
+		logger:        logger,
+		drainTimeout:  30 * time.Second,
+	}
+}
+
+// GetStatus godoc
+// @Summary Kafka consumer status
+// @Description Report the consumer group, each topic's state and last message time, and per partition the assigned member, committed offset, high-water mark and lag
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} json.ConsumerStatusResponse
+// @Failure 502 {object} map[string]string "Kafka Unavailable"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/kafka [get]
+func (h *KafkaAdminHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := h.consumerAdmin.Status(r.Context())
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	response := adapterjson.ConsumerStatusResponse{}
+	response.FromDto(status)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// Pause godoc
+// @Summary Pause the Kafka consumer
+// @Description Stop processing messages, for example during database maintenance, and wait for the messages in flight. Answers 202 if they are not done within 30 seconds; the consumer stays paused either way.
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} json.ConsumerPauseResponse
+// @Success 202 {object} json.ConsumerPauseResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/kafka/pause [post]
+func (h *KafkaAdminHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.drainTimeout)
+	defer cancel()
+
+	err := h.consumerAdmin.Pause(ctx)
+	recordAdminAction(h.auditUseCase, h.logger, r, auditActionPauseConsumer, nil, err)
+
+	response := adapterjson.ConsumerPauseResponse{State: boundarydto.ConsumerStatePaused, Drained: err == nil}
+	statusCode := http.StatusOK
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		statusCode = http.StatusAccepted
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		return
+	}
+}
+
+// Resume godoc
+// @Summary Resume the Kafka consumer
+// @Description Continue processing messages after a pause
+// @Tags admin
+// @Security AdminToken
+// @Success 204 "No Content"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Router /admin/kafka/resume [post]
+func (h *KafkaAdminHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.consumerAdmin.Resume()
+	recordAdminAction(h.auditUseCase, h.logger, r, auditActionResumeConsumer, nil, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
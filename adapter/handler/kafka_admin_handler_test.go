@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+)
+
+type MockConsumerAdmin struct {
+	status    *boundarydto.ConsumerStatusDTO
+	statusErr error
+	pauseErr  error
+	paused    bool
+	resumed   bool
+}
+
+func (m *MockConsumerAdmin) Status(ctx context.Context) (*boundarydto.ConsumerStatusDTO, error) {
+	return m.status, m.statusErr
+}
+
+func (m *MockConsumerAdmin) Pause(ctx context.Context) error {
+	m.paused = true
+	return m.pauseErr
+}
+
+func (m *MockConsumerAdmin) Resume() {
+	m.resumed = true
+}
+
+func TestKafkaAdminHandler_GetStatus(t *testing.T) {
+	lag := int64(3)
+	admin := &MockConsumerAdmin{status: &boundarydto.ConsumerStatusDTO{
+		GroupID: "casino-transaction-consumer",
+		Topics: []*boundarydto.TopicStatusDTO{{
+			Topic:      "casino-transactions-stream",
+			State:      boundarydto.ConsumerStateRetrying,
+			Partitions: []*boundarydto.PartitionStatusDTO{{Partition: 0, HighWaterMark: 10, Lag: &lag}},
+		}},
+	}}
+	handler := NewKafkaAdminHandler(admin, &MockAuditUseCase{}, &MockLogger{})
+
+	rr := httptest.NewRecorder()
+	handler.GetStatus(rr, httptest.NewRequest("GET", "/admin/kafka", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var response struct {
+		GroupID string `json:"group_id"`
+		Topics  []struct {
+			State      string `json:"state"`
+			Partitions []struct {
+				Lag *int64 `json:"lag"`
+			} `json:"partitions"`
+		} `json:"topics"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.GroupID != "casino-transaction-consumer" || response.Topics[0].State != "retrying" || *response.Topics[0].Partitions[0].Lag != 3 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+}
+
+func TestKafkaAdminHandler_GetStatus_KafkaUnavailable(t *testing.T) {
+	handler := NewKafkaAdminHandler(&MockConsumerAdmin{statusErr: errors.New("dial tcp: connection refused")}, &MockAuditUseCase{}, &MockLogger{})
+
+	rr := httptest.NewRecorder()
+	handler.GetStatus(rr, httptest.NewRequest("GET", "/admin/kafka", nil))
+
+	if rr.Code != http.StatusBadGateway {
+		t.Errorf("Expected status %d, got %d", http.StatusBadGateway, rr.Code)
+	}
+}
+
+func TestKafkaAdminHandler_Pause(t *testing.T) {
+	testCases := []struct {
+		name           string
+		pauseErr       error
+		expectedStatus int
+		expectDrained  bool
+	}{
+		{"Drained", nil, http.StatusOK, true},
+		{"Still Draining", context.DeadlineExceeded, http.StatusAccepted, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			admin := &MockConsumerAdmin{pauseErr: tc.pauseErr}
+			audit := &MockAuditUseCase{}
+			handler := NewKafkaAdminHandler(admin, audit, &MockLogger{})
+
+			req := httptest.NewRequest("POST", "/admin/kafka/pause", nil)
+			req = req.WithContext(context.WithValue(req.Context(), utils.CtxKeyActor, "dba"))
+			rr := httptest.NewRecorder()
+			handler.Pause(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+
+			var response struct {
+				State   string `json:"state"`
+				Drained bool   `json:"drained"`
+			}
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if !admin.paused || response.State != "paused" || response.Drained != tc.expectDrained {
+				t.Errorf("Unexpected pause response %+v", response)
+			}
+			if len(audit.records) != 1 || audit.records[0].Action != auditActionPauseConsumer || audit.records[0].Actor != "dba" {
+				t.Errorf("Expected the pause to be audited, got %+v", audit.records)
+			}
+		})
+	}
+}
+
+func TestKafkaAdminHandler_Resume(t *testing.T) {
+	admin := &MockConsumerAdmin{}
+	audit := &MockAuditUseCase{}
+	handler := NewKafkaAdminHandler(admin, audit, &MockLogger{})
+
+	rr := httptest.NewRecorder()
+	handler.Resume(rr, httptest.NewRequest("POST", "/admin/kafka/resume", nil))
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status %d, got %d", http.StatusNoContent, rr.Code)
+	}
+	if !admin.resumed {
+		t.Error("Expected the consumer to be resumed")
+	}
+	if len(audit.records) != 1 || audit.records[0].Action != auditActionResumeConsumer || audit.records[0].Actor != anonymousActor {
+		t.Errorf("Expected the resume to be audited, got %+v", audit.records)
+	}
+}
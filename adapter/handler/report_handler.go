@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	"casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/utils"
+)
+
+type ReportHandler struct {
+	reportUseCase usecase.ReportUseCase
+	logger        logging.Logger
+}
+
+func NewReportHandler(reportUseCase usecase.ReportUseCase, logger logging.Logger) *ReportHandler {
+	return &ReportHandler{
+		reportUseCase: reportUseCase,
+		logger:        logger,
+	}
+}
+
+// GetGGR godoc
+// @Summary Gross gaming revenue report
+// @Description Aggregate bets, wins and GGR (bets - wins) in the reporting currency over a time range. Rolled back bets and wins and refunded bets are netted out; transactions without a reporting amount are only counted in unconverted_count
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param from query string false "Range start (RFC3339, inclusive), defaults to the beginning of the log"
+// @Param to query string false "Range end (RFC3339, exclusive), defaults to now"
+// @Param group_by query string false "Grouping: day, user or game (default day)"
+// @Success 200 {object} json.GGRReportResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/ggr [get]
+func (h *ReportHandler) GetGGR(w http.ResponseWriter, r *http.Request) {
+	from, err := parseTimeParam(r, "from", time.Time{})
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	to, err := parseTimeParam(r, "to", time.Now().UTC())
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.reportUseCase.GetGGR(&dto.GGRFilterDTO{
+		From:    from,
+		To:      to,
+		GroupBy: r.URL.Query().Get("group_by"),
+	})
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		if utils.IsReportValidation(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := adapterjson.GGRReportResponse{}
+	response.FromDto(report)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetUserStats godoc
+// @Summary Get user statistics
+// @Description Get lifetime, 24h, 7d and 30d totals for a user in the reporting currency: bets, wins, net result (wins - bets), transaction counts, biggest win, first and last activity and RTP percentage. RTP is null when there are no bets
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} json.UserStatsResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /users/{id}/stats [get]
+func (h *ReportHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+	if userID == "" {
+		h.logger.Error(r.Context(), fmt.Errorf("user id is required"))
+		http.Error(w, "user id is required", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := h.reportUseCase.GetUserStats(userID)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := adapterjson.UserStatsResponse{}
+	response.FromDto(stats)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+)
+
+type MockReportUseCase struct {
+	report     *boundarydto.GGRReportDTO
+	stats      *boundarydto.UserStatsDTO
+	err        error
+	lastFilter *boundarydto.GGRFilterDTO
+	lastUserID string
+}
+
+func (m *MockReportUseCase) GetGGR(filter *boundarydto.GGRFilterDTO) (*boundarydto.GGRReportDTO, error) {
+	m.lastFilter = filter
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.report, nil
+}
+
+func (m *MockReportUseCase) GetUserStats(userID string) (*boundarydto.UserStatsDTO, error) {
+	m.lastUserID = userID
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.stats, nil
+}
+
+func (m *MockReportUseCase) RebuildDailyAggregates(from, to time.Time) (int64, error) {
+	return 0, m.err
+}
+
+func TestReportHandler_GetGGR(t *testing.T) {
+	mockUseCase := &MockReportUseCase{
+		report: &boundarydto.GGRReportDTO{
+			From:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+			To:       time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+			GroupBy:  "game",
+			Currency: "EUR",
+			Rows: []*boundarydto.GGRRowDTO{
+				{Key: "slots", TotalBets: 1000, TotalWins: 400, GGR: 600, BetCount: 2, UniquePlayers: 1},
+			},
+			Total: &boundarydto.GGRRowDTO{TotalBets: 1000, TotalWins: 400, GGR: 600, BetCount: 2, UniquePlayers: 1},
+		},
+	}
+	handler := NewReportHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/reports/ggr?from=2026-03-01T00:00:00Z&to=2026-03-02T00:00:00Z&group_by=game", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.GetGGR(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var response struct {
+		GroupBy string `json:"group_by"`
+		Rows    []struct {
+			Key string `json:"key"`
+			GGR int64  `json:"ggr"`
+		} `json:"rows"`
+		Total struct {
+			GGR int64 `json:"ggr"`
+		} `json:"total"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.GroupBy != "game" || len(response.Rows) != 1 || response.Rows[0].Key != "slots" || response.Total.GGR != 600 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	if mockUseCase.lastFilter.GroupBy != "game" || !mockUseCase.lastFilter.From.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected filter %+v", mockUseCase.lastFilter)
+	}
+}
+
+func TestReportHandler_GetGGR_Errors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		err            error
+		expectedStatus int
+	}{
+		{"Bad From", "/reports/ggr?from=yesterday", nil, http.StatusBadRequest},
+		{"Bad To", "/reports/ggr?to=2026-13-01", nil, http.StatusBadRequest},
+		{"Validation Error", "/reports/ggr?group_by=month", &utils.ReportValidationError{Reason: "unsupported group_by"}, http.StatusBadRequest},
+		{"Use Case Error", "/reports/ggr", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewReportHandler(&MockReportUseCase{err: tc.err}, &MockLogger{})
+
+			req, err := http.NewRequest("GET", tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.GetGGR(rr, req)
+
+			if status := rr.Code; status != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, status)
+			}
+		})
+	}
+}
+
+func TestReportHandler_GetUserStats(t *testing.T) {
+	rtp := 95.0
+	mockUseCase := &MockReportUseCase{
+		stats: &boundarydto.UserStatsDTO{
+			UserID:   "user123",
+			Currency: "EUR",
+			Lifetime: &boundarydto.UserStatsPeriodDTO{TotalBets: 1000, TotalWins: 950, NetResult: -50, BetCount: 4, RTP: &rtp},
+			Last24h:  &boundarydto.UserStatsPeriodDTO{},
+			Last7d:   &boundarydto.UserStatsPeriodDTO{},
+			Last30d:  &boundarydto.UserStatsPeriodDTO{},
+		},
+	}
+	handler := NewReportHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/users/user123/stats", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", "user123")
+
+	rr := httptest.NewRecorder()
+	handler.GetUserStats(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var response struct {
+		UserID   string `json:"user_id"`
+		Lifetime struct {
+			NetResult int64    `json:"net_result"`
+			RTP       *float64 `json:"rtp"`
+		} `json:"lifetime"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.UserID != "user123" || response.Lifetime.NetResult != -50 || response.Lifetime.RTP == nil || *response.Lifetime.RTP != 95 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+
+	if mockUseCase.lastUserID != "user123" {
+		t.Errorf("Expected user123, got %s", mockUseCase.lastUserID)
+	}
+}
+
+func TestReportHandler_GetUserStats_Errors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userID         string
+		err            error
+		expectedStatus int
+	}{
+		{"Missing User ID", "", nil, http.StatusBadRequest},
+		{"Use Case Error", "user123", errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewReportHandler(&MockReportUseCase{err: tc.err}, &MockLogger{})
+
+			req, err := http.NewRequest("GET", "/users/"+tc.userID+"/stats", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.SetPathValue("id", tc.userID)
+
+			rr := httptest.NewRecorder()
+			handler.GetUserStats(rr, req)
+
+			if status := rr.Code; status != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, status)
+			}
+		})
+	}
+}
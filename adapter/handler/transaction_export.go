@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	adapterjson "casino/adapter/json"
+	boundarydto "casino/boundary/dto"
+)
+
+// exportFlushEvery is the number of rows after which buffered output is
+// pushed to the client.
+const exportFlushEvery = 1000
+
+var transactionCSVHeader = []string{
+	"id", "user_id", "transaction_type", "amount", "currency", "timestamp",
+	"round_id", "game_id", "provider_id", "original_transaction_id", "reason",
+	"reporting_amount", "exchange_rate",
+}
+
+// sentWriter records whether anything has been written through it, which for
+// a response means the status line has gone out.
+type sentWriter struct {
+	writer io.Writer
+	sent   bool
+}
+
+func (sw *sentWriter) Write(p []byte) (int, error) {
+	sw.sent = true
+	return sw.writer.Write(p)
+}
+
+type transactionWriter interface {
+	Write(transaction *adapterjson.TransactionResponse) error
+	Flush() error
+}
+
+type csvTransactionWriter struct {
+	writer *csv.Writer
+}
+
+func (cw *csvTransactionWriter) Write(transaction *adapterjson.TransactionResponse) error {
+	reportingAmount := ""
+	if transaction.ReportingAmount != nil {
+		reportingAmount = strconv.FormatInt(*transaction.ReportingAmount, 10)
+	}
+
+	return cw.writer.Write([]string{
+		transaction.ID,
+		transaction.UserID,
+		transaction.TransactionType,
+		strconv.FormatUint(uint64(transaction.Amount), 10),
+		transaction.Currency,
+		transaction.Timestamp,
+		transaction.RoundID,
+		transaction.GameID,
+		transaction.ProviderID,
+		transaction.OriginalTransactionID,
+		transaction.Reason,
+		reportingAmount,
+		transaction.ExchangeRate,
+	})
+}
+
+func (cw *csvTransactionWriter) Flush() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+type ndjsonTransactionWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+func (nw *ndjsonTransactionWriter) Write(transaction *adapterjson.TransactionResponse) error {
+	return nw.encoder.Encode(transaction)
+}
+
+func (nw *ndjsonTransactionWriter) Flush() error {
+	return nw.buffer.Flush()
+}
+
+// ExportTransactions godoc
+// @Summary Export transactions
+// @Description Stream all transactions matching the filters, oldest first, as CSV or newline delimited JSON. The export is not subject to the regular request timeout
+// @Tags transactions
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Export format: csv (default) or ndjson"
+// @Param user_id query string false "User ID filter"
+// @Param transaction_type query string false "Transaction type filter (bet, win, deposit, withdrawal, refund, rollback or bonus_credit)"
+// @Param currency query string false "Currency filter (e.g. EUR, USD, BTC)"
+// @Success 200 {string} string "Transactions"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /transactions/export [get]
+func (h *TransactionHandler) ExportTransactions(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	output := &sentWriter{writer: w}
+	var writer transactionWriter
+	switch format {
+	case "csv":
+		csvWriter := csv.NewWriter(output)
+		// The header only reaches the buffer here, so a failing query can
+		// still be answered with an error status.
+		if err := csvWriter.Write(transactionCSVHeader); err != nil {
+			h.logger.Error(r.Context(), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer = &csvTransactionWriter{writer: csvWriter}
+		w.Header().Set("Content-Type", "text/csv")
+	case "ndjson":
+		buffer := bufio.NewWriter(output)
+		writer = &ndjsonTransactionWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	default:
+		h.logger.Error(r.Context(), fmt.Errorf("unsupported export format %q", format))
+		http.Error(w, "format must be csv or ndjson", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions.%s"`, format))
+
+	var userID *string
+	if value := r.URL.Query().Get("user_id"); value != "" {
+		userID = &value
+	}
+
+	flusher, _ := w.(http.Flusher)
+	rows := 0
+
+	err := h.transactionUseCase.ExportTransactions(r.Context(), userID, transactionFilter(r), func(transaction *boundarydto.TransactionDTO) error {
+		response := &adapterjson.TransactionResponse{}
+		response.FromDto(transaction)
+		if err := writer.Write(response); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+
+	if err != nil && !output.sent {
+		h.logger.Error(r.Context(), err)
+		w.Header().Del("Content-Disposition")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Once rows have reached the client the status can no longer change.
+	// Aborting the connection keeps a truncated export from looking complete.
+	if err != nil {
+		h.logger.Error(r.Context(), fmt.Errorf("export aborted after %d rows: %w", rows, err))
+		panic(http.ErrAbortHandler)
+	}
+
+	if err := writer.Flush(); err != nil {
+		h.logger.Error(r.Context(), err)
+	}
+}
@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+func exportTransactions() []*boundarydto.TransactionDTO {
+	reportingAmount := int64(920)
+	return []*boundarydto.TransactionDTO{
+		{
+			ID: "tx-1", UserID: "user123", TransactionType: "bet", Amount: 1000, Currency: "USD",
+			Timestamp: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), RoundID: "round-1",
+			ReportingAmount: &reportingAmount, ExchangeRate: "0.92",
+		},
+		{
+			ID: "tx-2", UserID: "user123", TransactionType: "rollback", Amount: 1000, Currency: "USD",
+			Timestamp: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC), OriginalTransactionID: "tx-1",
+			Reason: "game crashed, \"voided\"",
+		},
+	}
+}
+
+func TestTransactionHandler_ExportTransactions_CSV(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{exported: exportTransactions()}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/transactions/export?user_id=user123&currency=USD", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ExportTransactions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	if rr.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("Expected text/csv, got %s", rr.Header().Get("Content-Type"))
+	}
+
+	if !strings.Contains(rr.Header().Get("Content-Disposition"), "transactions.csv") {
+		t.Errorf("Unexpected Content-Disposition %s", rr.Header().Get("Content-Disposition"))
+	}
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+
+	if records[0][0] != "id" || records[0][11] != "reporting_amount" {
+		t.Errorf("Unexpected header %v", records[0])
+	}
+
+	if records[1][0] != "tx-1" || records[1][3] != "1000" || records[1][5] != "2026-03-01T10:00:00Z" || records[1][11] != "920" || records[1][12] != "0.92" {
+		t.Errorf("Unexpected first row %v", records[1])
+	}
+
+	if records[2][9] != "tx-1" || records[2][10] != "game crashed, \"voided\"" || records[2][11] != "" {
+		t.Errorf("Unexpected second row %v", records[2])
+	}
+
+	if mockUseCase.lastExportUserID == nil || *mockUseCase.lastExportUserID != "user123" {
+		t.Errorf("Expected user filter, got %v", mockUseCase.lastExportUserID)
+	}
+
+	if mockUseCase.lastFilter == nil || *mockUseCase.lastFilter.Currency != "USD" {
+		t.Errorf("Expected currency filter, got %+v", mockUseCase.lastFilter)
+	}
+}
+
+func TestTransactionHandler_ExportTransactions_NDJSON(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{exported: exportTransactions()}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/transactions/export?format=ndjson", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ExportTransactions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	if rr.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("Expected application/x-ndjson, got %s", rr.Header().Get("Content-Type"))
+	}
+
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	var second struct {
+		ID                    string `json:"id"`
+		OriginalTransactionID string `json:"original_transaction_id"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+
+	if second.ID != "tx-2" || second.OriginalTransactionID != "tx-1" {
+		t.Errorf("Unexpected second line %+v", second)
+	}
+
+	if mockUseCase.lastExportUserID != nil || mockUseCase.lastFilter != nil {
+		t.Error("Expected no filters")
+	}
+}
+
+func TestTransactionHandler_ExportTransactions_EmptyCSVHasHeader(t *testing.T) {
+	handler := NewTransactionHandler(&MockTransactionUseCase{}, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/transactions/export?format=csv", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.ExportTransactions(rr, req)
+
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Body.String(), "id,user_id,") {
+		t.Errorf("Expected header only, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+func TestTransactionHandler_ExportTransactions_Errors(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		exported       []*boundarydto.TransactionDTO
+		exportError    error
+		expectedStatus int
+	}{
+		{"Unknown Format", "/transactions/export?format=xlsx", nil, nil, http.StatusBadRequest},
+		{"Error Before First Row", "/transactions/export", nil, errors.New("database error"), http.StatusInternalServerError},
+		{"Error Before Anything Was Sent", "/transactions/export", exportTransactions(), errors.New("connection reset"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			logger := &MockLogger{}
+			handler := NewTransactionHandler(&MockTransactionUseCase{exported: tc.exported, exportError: tc.exportError}, logger)
+
+			req, err := http.NewRequest("GET", tc.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ExportTransactions(rr, req)
+
+			if rr.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rr.Code)
+			}
+
+			if !logger.errorCalled {
+				t.Error("Expected the error to be logged")
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_ExportTransactions_AbortsAfterRowsWereSent(t *testing.T) {
+	exported := make([]*boundarydto.TransactionDTO, exportFlushEvery)
+	for i := range exported {
+		exported[i] = &boundarydto.TransactionDTO{ID: "tx", TransactionType: "bet", Amount: 1, Currency: "EUR"}
+	}
+	logger := &MockLogger{}
+	handler := NewTransactionHandler(&MockTransactionUseCase{exported: exported, exportError: errors.New("connection reset")}, logger)
+
+	req, err := http.NewRequest("GET", "/transactions/export?format=ndjson", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("Expected the handler to abort, got %v", recovered)
+		}
+		if rr.Code != http.StatusOK || !rr.Flushed {
+			t.Errorf("Expected rows to have been flushed, got %d", rr.Code)
+		}
+		if !logger.errorCalled {
+			t.Error("Expected the error to be logged")
+		}
+	}()
+
+	handler.ExportTransactions(rr, req)
+}
@@ -9,6 +9,7 @@ import (
 	boundarydto "casino/boundary/dto"
 	"casino/boundary/logging"
 	"casino/boundary/usecase"
+	"casino/utils"
 )
 
 type TransactionHandler struct {
@@ -30,7 +31,8 @@ func NewTransactionHandler(transactionUseCase usecase.TransactionUseCase, logger
 // @Accept json
 // @Produce json
 // @Param user_id query string true "User ID"
-// @Param transaction_type query string false "Transaction type filter (bet or win)"
+// @Param transaction_type query string false "Transaction type filter (bet, win, deposit, withdrawal, refund, rollback or bonus_credit)"
+// @Param currency query string false "Currency filter (e.g. EUR, USD, BTC)"
 // @Success 200 {object} json.TransactionsResponse
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 500 {object} map[string]string "Internal Server Error"
@@ -43,13 +45,7 @@ func (h *TransactionHandler) GetUserTransactions(w http.ResponseWriter, r *http.
 		return
 	}
 
-	transactionTypeStr := r.URL.Query().Get("transaction_type")
-	var filter *boundarydto.TransactionFilterDTO
-	if transactionTypeStr != "" {
-		filter = &boundarydto.TransactionFilterDTO{
-			TransactionType: &transactionTypeStr,
-		}
-	}
+	filter := transactionFilter(r)
 
 	dtos, err := h.transactionUseCase.GetUserTransactions(userID, filter)
 	if err != nil {
@@ -75,18 +71,13 @@ func (h *TransactionHandler) GetUserTransactions(w http.ResponseWriter, r *http.
 // @Tags transactions
 // @Accept json
 // @Produce json
-// @Param transaction_type query string false "Transaction type filter (bet or win)"
+// @Param transaction_type query string false "Transaction type filter (bet, win, deposit, withdrawal, refund, rollback or bonus_credit)"
+// @Param currency query string false "Currency filter (e.g. EUR, USD, BTC)"
 // @Success 200 {object} json.TransactionsResponse
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /transactions [get]
 func (h *TransactionHandler) GetAllTransactions(w http.ResponseWriter, r *http.Request) {
-	transactionTypeStr := r.URL.Query().Get("transaction_type")
-	var filter *boundarydto.TransactionFilterDTO
-	if transactionTypeStr != "" {
-		filter = &boundarydto.TransactionFilterDTO{
-			TransactionType: &transactionTypeStr,
-		}
-	}
+	filter := transactionFilter(r)
 
 	dtos, err := h.transactionUseCase.GetAllTransactions(filter)
 	if err != nil {
@@ -105,3 +96,101 @@ func (h *TransactionHandler) GetAllTransactions(w http.ResponseWriter, r *http.R
 		return
 	}
 }
+
+// GetRound godoc
+// @Summary Get game round
+// @Description Get the bets and wins of a game round together with its net result
+// @Tags rounds
+// @Accept json
+// @Produce json
+// @Param id path string true "Round ID"
+// @Success 200 {object} json.RoundResponse
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /rounds/{id} [get]
+func (h *TransactionHandler) GetRound(w http.ResponseWriter, r *http.Request) {
+	roundID := r.PathValue("id")
+	if roundID == "" {
+		h.logger.Error(r.Context(), fmt.Errorf("round id is required"))
+		http.Error(w, "round id is required", http.StatusBadRequest)
+		return
+	}
+
+	round, err := h.transactionUseCase.GetRound(roundID)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		if utils.IsRoundNotFound(err) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := adapterjson.RoundResponse{}
+	response.FromDto(round)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetUserBalances godoc
+// @Summary Get user balances
+// @Description Get the balance of a user in every currency they have transacted in. Balances are in minor units.
+// @Tags transactions
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Security UserToken
+// @Success 200 {object} json.BalancesResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Token of another user"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /users/{id}/balances [get]
+func (h *TransactionHandler) GetUserBalances(w http.ResponseWriter, r *http.Request) {
+	userID := r.PathValue("id")
+	if userID == "" {
+		h.logger.Error(r.Context(), fmt.Errorf("user id is required"))
+		http.Error(w, "user id is required", http.StatusBadRequest)
+		return
+	}
+
+	balances, err := h.transactionUseCase.GetBalances(userID)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := adapterjson.BalancesResponse{}
+	response.FromDtos(userID, balances)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+func transactionFilter(r *http.Request) *boundarydto.TransactionFilterDTO {
+	transactionTypeStr := r.URL.Query().Get("transaction_type")
+	currencyStr := r.URL.Query().Get("currency")
+	if transactionTypeStr == "" && currencyStr == "" {
+		return nil
+	}
+
+	filter := &boundarydto.TransactionFilterDTO{}
+	if transactionTypeStr != "" {
+		filter.TransactionType = &transactionTypeStr
+	}
+	if currencyStr != "" {
+		filter.Currency = &currencyStr
+	}
+	return filter
+}
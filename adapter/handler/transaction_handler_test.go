@@ -19,26 +19,80 @@ type MockTransactionUseCase struct {
 	allTransactions  []*boundarydto.TransactionDTO
 	getUserError     error
 	getAllError      error
+	round            *boundarydto.RoundDTO
+	getRoundError    error
+	cancelled        *boundarydto.TransactionDTO
+	cancelError      error
+	lastCancel       *boundarydto.CancelTransactionDTO
+	balances         []*boundarydto.BalanceDTO
+	getBalancesError error
+	lastFilter       *boundarydto.TransactionFilterDTO
+	exported         []*boundarydto.TransactionDTO
+	exportError      error
+	lastExportUserID *string
 }
 
 func (m *MockTransactionUseCase) ProcessTransaction(dto *boundarydto.CreateTransactionDTO) error {
 	return m.processError
 }
 
+func (m *MockTransactionUseCase) GetTransaction(id string) (*boundarydto.TransactionDTO, error) {
+	return nil, nil
+}
+
 func (m *MockTransactionUseCase) GetUserTransactions(userID string, filter *boundarydto.TransactionFilterDTO) ([]*boundarydto.TransactionDTO, error) {
+	m.lastFilter = filter
 	if m.getUserError != nil {
 		return nil, m.getUserError
 	}
 	return m.userTransactions, nil
 }
 
+func (m *MockTransactionUseCase) GetReversals(originalID string) ([]*boundarydto.TransactionDTO, error) {
+	return nil, nil
+}
+
 func (m *MockTransactionUseCase) GetAllTransactions(filter *boundarydto.TransactionFilterDTO) ([]*boundarydto.TransactionDTO, error) {
+	m.lastFilter = filter
 	if m.getAllError != nil {
 		return nil, m.getAllError
 	}
 	return m.allTransactions, nil
 }
 
+func (m *MockTransactionUseCase) GetRound(roundID string) (*boundarydto.RoundDTO, error) {
+	if m.getRoundError != nil {
+		return nil, m.getRoundError
+	}
+	return m.round, nil
+}
+
+func (m *MockTransactionUseCase) CancelTransaction(dto *boundarydto.CancelTransactionDTO) (*boundarydto.TransactionDTO, error) {
+	m.lastCancel = dto
+	if m.cancelError != nil {
+		return nil, m.cancelError
+	}
+	return m.cancelled, nil
+}
+
+func (m *MockTransactionUseCase) GetBalances(userID string) ([]*boundarydto.BalanceDTO, error) {
+	if m.getBalancesError != nil {
+		return nil, m.getBalancesError
+	}
+	return m.balances, nil
+}
+
+func (m *MockTransactionUseCase) ExportTransactions(ctx context.Context, userID *string, filter *boundarydto.TransactionFilterDTO, fn func(*boundarydto.TransactionDTO) error) error {
+	m.lastExportUserID = userID
+	m.lastFilter = filter
+	for _, transaction := range m.exported {
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return m.exportError
+}
+
 type MockLogger struct {
 	errorCalled bool
 	infoCalled  bool
@@ -1262,3 +1316,176 @@ func (fw *failingResponseWriter) Write(p []byte) (n int, err error) {
 func (fw *failingResponseWriter) WriteHeader(statusCode int) {
 	fw.statusCode = statusCode
 }
+
+func TestTransactionHandler_GetRound(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{
+		round: &boundarydto.RoundDTO{
+			RoundID:   "round-1",
+			Bets:      []*boundarydto.TransactionDTO{{ID: utils.GenerateUUID(), TransactionType: "bet", Amount: 100}},
+			Wins:      []*boundarydto.TransactionDTO{{ID: utils.GenerateUUID(), TransactionType: "win", Amount: 300}},
+			TotalBet:  100,
+			TotalWin:  300,
+			NetResult: 200,
+		},
+	}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/rounds/round-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", "round-1")
+
+	rr := httptest.NewRecorder()
+	handler.GetRound(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var response struct {
+		RoundID   string `json:"round_id"`
+		NetResult int64  `json:"net_result"`
+		Bets      []struct {
+			ID string `json:"id"`
+		} `json:"bets"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.RoundID != "round-1" || response.NetResult != 200 || len(response.Bets) != 1 {
+		t.Errorf("Unexpected response %+v", response)
+	}
+}
+
+func TestTransactionHandler_GetRound_NotFound(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{
+		getRoundError: &utils.RoundNotFoundError{RoundID: "missing"},
+	}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/rounds/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", "missing")
+
+	rr := httptest.NewRecorder()
+	handler.GetRound(rr, req)
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, status)
+	}
+}
+
+func TestTransactionHandler_GetRound_UseCaseError(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{
+		getRoundError: errors.New("database error"),
+	}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/rounds/round-1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", "round-1")
+
+	rr := httptest.NewRecorder()
+	handler.GetRound(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, status)
+	}
+}
+
+func TestTransactionHandler_GetUserTransactions_WithCurrencyFilter(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/transactions/user?user_id=user123&currency=BTC", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	handler.GetUserTransactions(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	if mockUseCase.lastFilter == nil || mockUseCase.lastFilter.Currency == nil || *mockUseCase.lastFilter.Currency != "BTC" {
+		t.Fatalf("Expected currency filter BTC, got %+v", mockUseCase.lastFilter)
+	}
+
+	if mockUseCase.lastFilter.TransactionType != nil {
+		t.Error("Expected no transaction type filter")
+	}
+}
+
+func TestTransactionHandler_GetUserBalances(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{
+		balances: []*boundarydto.BalanceDTO{
+			{Currency: "BTC", Balance: 150000000, Formatted: "1.50000000"},
+			{Currency: "EUR", Balance: 2550, Formatted: "25.50"},
+		},
+	}
+	handler := NewTransactionHandler(mockUseCase, &MockLogger{})
+
+	req, err := http.NewRequest("GET", "/users/user123/balances", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetPathValue("id", "user123")
+
+	rr := httptest.NewRecorder()
+	handler.GetUserBalances(rr, req)
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, status)
+	}
+
+	var response struct {
+		UserID   string `json:"user_id"`
+		Balances []struct {
+			Currency  string `json:"currency"`
+			Balance   int64  `json:"balance"`
+			Formatted string `json:"formatted"`
+		} `json:"balances"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if response.UserID != "user123" || len(response.Balances) != 2 {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+
+	if response.Balances[1].Currency != "EUR" || response.Balances[1].Formatted != "25.50" {
+		t.Errorf("Unexpected EUR balance %+v", response.Balances[1])
+	}
+}
+
+func TestTransactionHandler_GetUserBalances_Errors(t *testing.T) {
+	handler := NewTransactionHandler(&MockTransactionUseCase{}, &MockLogger{})
+
+	req, _ := http.NewRequest("GET", "/users//balances", nil)
+	rr := httptest.NewRecorder()
+	handler.GetUserBalances(rr, req)
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+	}
+
+	handler = NewTransactionHandler(&MockTransactionUseCase{getBalancesError: errors.New("database error")}, &MockLogger{})
+
+	req, _ = http.NewRequest("GET", "/users/user123/balances", nil)
+	req.SetPathValue("id", "user123")
+	rr = httptest.NewRecorder()
+	handler.GetUserBalances(rr, req)
+
+	if status := rr.Code; status != http.StatusInternalServerError {
+		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, status)
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	adapterjson "casino/adapter/json"
+	boundarydto "casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/domain/entity"
+)
+
+type TransactionStreamHandler struct {
+	feed   usecase.TransactionFeed
+	logger logging.Logger
+	// heartbeat is how often a comment is sent on an idle stream so that
+	// proxies keep the connection open.
+	heartbeat time.Duration
+}
+
+func NewTransactionStreamHandler(feed usecase.TransactionFeed, logger logging.Logger) *TransactionStreamHandler {
+	return &TransactionStreamHandler{
+		feed:      feed,
+		logger:    logger,
+		heartbeat: 15 * time.Second,
+	}
+}
+
+// StreamTransactions godoc
+// @Summary Stream transactions
+// @Description Push every newly processed transaction as a Server-Sent Event. Clients that reconnect with the Last-Event-ID header receive the events they missed, as long as the server still holds them. An idle stream receives a heartbeat comment every 15 seconds
+// @Tags transactions
+// @Produce text/event-stream
+// @Param user_id query string false "User ID filter"
+// @Param transaction_type query string false "Transaction type filter (bet, win, deposit, withdrawal, refund, rollback or bonus_credit)"
+// @Param min_amount query int false "Only transactions with at least this amount"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /transactions/stream [get]
+func (h *TransactionStreamHandler) StreamTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := transactionStreamFilter(r)
+	if err != nil {
+		h.logger.Error(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var lastEventID uint64
+	if value := r.Header.Get("Last-Event-ID"); value != "" {
+		if lastEventID, err = strconv.ParseUint(value, 10, 64); err != nil {
+			h.logger.Error(r.Context(), fmt.Errorf("invalid Last-Event-ID %q", value))
+			http.Error(w, "Last-Event-ID must be an event ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.logger.Error(r.Context(), fmt.Errorf("response writer does not support streaming"))
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := h.feed.Subscribe(filter, lastEventID)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			// A closed channel means the client fell behind. Ending the
+			// response makes it reconnect and catch up via Last-Event-ID.
+			if !ok {
+				return
+			}
+			if err := writeTransactionEvent(w, event); err != nil {
+				h.logger.Error(r.Context(), err)
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeTransactionEvent(w http.ResponseWriter, event *boundarydto.TransactionEventDTO) error {
+	response := &adapterjson.TransactionResponse{}
+	response.FromDto(event.Transaction)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: transaction\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+func transactionStreamFilter(r *http.Request) (*boundarydto.TransactionStreamFilterDTO, error) {
+	query := r.URL.Query()
+	filter := &boundarydto.TransactionStreamFilterDTO{}
+
+	if value := query.Get("user_id"); value != "" {
+		filter.UserID = &value
+	}
+
+	if value := query.Get("transaction_type"); value != "" {
+		if !entity.TransactionType(value).IsValid() {
+			return nil, fmt.Errorf("invalid transaction_type %q", value)
+		}
+		filter.TransactionType = &value
+	}
+
+	if value := query.Get("min_amount"); value != "" {
+		amount, err := strconv.ParseUint(value, 10, 0)
+		if err != nil {
+			return nil, fmt.Errorf("min_amount must be a non-negative integer")
+		}
+		minAmount := uint(amount)
+		filter.MinAmount = &minAmount
+	}
+
+	return filter, nil
+}
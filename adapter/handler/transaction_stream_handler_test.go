@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+type MockTransactionFeed struct {
+	events       chan *boundarydto.TransactionEventDTO
+	lastFilter   *boundarydto.TransactionStreamFilterDTO
+	lastEventID  uint64
+	unsubscribed bool
+}
+
+func (m *MockTransactionFeed) Subscribe(filter *boundarydto.TransactionStreamFilterDTO, lastEventID uint64) (<-chan *boundarydto.TransactionEventDTO, func()) {
+	m.lastFilter = filter
+	m.lastEventID = lastEventID
+	return m.events, func() { m.unsubscribed = true }
+}
+
+func TestTransactionStreamHandler_StreamTransactions(t *testing.T) {
+	feed := &MockTransactionFeed{events: make(chan *boundarydto.TransactionEventDTO, 2)}
+	feed.events <- &boundarydto.TransactionEventDTO{
+		ID: 42,
+		Transaction: &boundarydto.TransactionDTO{
+			ID:              "tx-1",
+			UserID:          "user-1",
+			TransactionType: "bet",
+			Amount:          500,
+			Currency:        "EUR",
+			Timestamp:       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	close(feed.events)
+
+	handler := NewTransactionStreamHandler(feed, &MockLogger{})
+
+	req := httptest.NewRequest("GET", "/transactions/stream?user_id=user-1&transaction_type=bet&min_amount=100", nil)
+	req.Header.Set("Last-Event-ID", "41")
+	rr := httptest.NewRecorder()
+	handler.StreamTransactions(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	if contentType := rr.Header().Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("Expected Content-Type text/event-stream, got %s", contentType)
+	}
+
+	expected := "id: 42\nevent: transaction\ndata: {\"id\":\"tx-1\",\"user_id\":\"user-1\",\"transaction_type\":\"bet\",\"amount\":500,\"currency\":\"EUR\",\"timestamp\":\"2026-03-01T10:00:00Z\"}\n\n"
+	if rr.Body.String() != expected {
+		t.Errorf("Unexpected body %q", rr.Body.String())
+	}
+
+	if feed.lastEventID != 41 {
+		t.Errorf("Expected Last-Event-ID 41, got %d", feed.lastEventID)
+	}
+
+	filter := feed.lastFilter
+	if *filter.UserID != "user-1" || *filter.TransactionType != "bet" || *filter.MinAmount != 100 {
+		t.Errorf("Unexpected filter %+v", filter)
+	}
+
+	if !feed.unsubscribed {
+		t.Error("Expected the subscription to be ended")
+	}
+}
+
+func TestTransactionStreamHandler_Heartbeat(t *testing.T) {
+	feed := &MockTransactionFeed{events: make(chan *boundarydto.TransactionEventDTO)}
+	handler := NewTransactionStreamHandler(feed, &MockLogger{})
+	handler.heartbeat = 5 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+
+	req := httptest.NewRequest("GET", "/transactions/stream", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	handler.StreamTransactions(rr, req)
+
+	if !strings.HasPrefix(rr.Body.String(), ": heartbeat\n\n") {
+		t.Errorf("Expected heartbeats, got %q", rr.Body.String())
+	}
+
+	if !feed.unsubscribed {
+		t.Error("Expected the subscription to be ended when the client goes away")
+	}
+}
+
+func TestTransactionStreamHandler_BadRequest(t *testing.T) {
+	testCases := []struct {
+		name        string
+		query       string
+		lastEventID string
+	}{
+		{"Invalid Type", "?transaction_type=jackpot", ""},
+		{"Invalid Min Amount", "?min_amount=-5", ""},
+		{"Invalid Last Event ID", "", "abc"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			feed := &MockTransactionFeed{}
+			mockLogger := &MockLogger{}
+			handler := NewTransactionStreamHandler(feed, mockLogger)
+
+			req := httptest.NewRequest("GET", "/transactions/stream"+tc.query, nil)
+			if tc.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tc.lastEventID)
+			}
+			rr := httptest.NewRecorder()
+			handler.StreamTransactions(rr, req)
+
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+			}
+
+			if !mockLogger.errorCalled {
+				t.Error("Expected logger.Error to be called")
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package json
+
+import (
+	"casino/boundary/dto"
+	"time"
+)
+
+type AuditVerificationResponse struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Checked      int    `json:"checked"`
+	Valid        bool   `json:"valid"`
+	BrokenAtID   string `json:"broken_at_id,omitempty"`
+	BrokenReason string `json:"broken_reason,omitempty"`
+}
+
+func (r *AuditVerificationResponse) FromDto(dto *dto.AuditVerificationDTO) {
+	r.From = dto.From.Format(time.RFC3339)
+	r.To = dto.To.Format(time.RFC3339)
+	r.Checked = dto.Checked
+	r.Valid = dto.Valid
+	r.BrokenAtID = dto.BrokenAtID
+	r.BrokenReason = dto.BrokenReason
+}
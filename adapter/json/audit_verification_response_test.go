@@ -0,0 +1,45 @@
+package json
+
+import (
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+)
+
+func TestAuditVerificationResponse_FromDto(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	boundaryDto := &boundarydto.AuditVerificationDTO{
+		From:         from,
+		To:           to,
+		Checked:      3,
+		Valid:        false,
+		BrokenAtID:   utils.GenerateUUID(),
+		BrokenReason: "record hash does not match its contents",
+	}
+
+	response := &AuditVerificationResponse{}
+	response.FromDto(boundaryDto)
+
+	if response.From != "2026-01-01T00:00:00Z" {
+		t.Errorf("Expected From to be formatted, got %s", response.From)
+	}
+
+	if response.To != "2026-01-02T00:00:00Z" {
+		t.Errorf("Expected To to be formatted, got %s", response.To)
+	}
+
+	if response.Checked != 3 {
+		t.Errorf("Expected Checked 3, got %d", response.Checked)
+	}
+
+	if response.Valid {
+		t.Error("Expected Valid to be false")
+	}
+
+	if response.BrokenAtID != boundaryDto.BrokenAtID {
+		t.Errorf("Expected BrokenAtID %s, got %s", boundaryDto.BrokenAtID, response.BrokenAtID)
+	}
+}
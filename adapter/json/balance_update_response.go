@@ -0,0 +1,22 @@
+package json
+
+import (
+	"casino/boundary/dto"
+)
+
+// BalanceUpdateResponse is pushed to player clients over the balance socket.
+// The first message after connecting has no last transaction.
+type BalanceUpdateResponse struct {
+	Type string `json:"type"`
+	BalancesResponse
+	LastTransaction *TransactionResponse `json:"last_transaction,omitempty"`
+}
+
+func (r *BalanceUpdateResponse) FromDtos(userID string, balances []*dto.BalanceDTO, transaction *dto.TransactionDTO) {
+	r.Type = "balance"
+	r.BalancesResponse.FromDtos(userID, balances)
+	if transaction != nil {
+		r.LastTransaction = &TransactionResponse{}
+		r.LastTransaction.FromDto(transaction)
+	}
+}
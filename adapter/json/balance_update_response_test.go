@@ -0,0 +1,49 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+)
+
+func TestBalanceUpdateResponse_FromDtos(t *testing.T) {
+	balances := []*dto.BalanceDTO{{Currency: "EUR", Balance: 1500, Formatted: "15.00 EUR"}}
+	transaction := &dto.TransactionDTO{
+		ID:              "tx-1",
+		UserID:          "user-1",
+		TransactionType: "win",
+		Amount:          500,
+		Currency:        "EUR",
+		Timestamp:       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+
+	response := BalanceUpdateResponse{}
+	response.FromDtos("user-1", balances, transaction)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := `{"type":"balance","user_id":"user-1","balances":[{"currency":"EUR","balance":1500,"formatted":"15.00 EUR"}],` +
+		`"last_transaction":{"id":"tx-1","user_id":"user-1","transaction_type":"win","amount":500,"currency":"EUR","timestamp":"2026-03-01T10:00:00Z"}}`
+	if string(data) != expected {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
+
+func TestBalanceUpdateResponse_FromDtos_WithoutTransaction(t *testing.T) {
+	response := BalanceUpdateResponse{}
+	response.FromDtos("user-1", []*dto.BalanceDTO{}, nil)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if string(data) != `{"type":"balance","user_id":"user-1","balances":[]}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
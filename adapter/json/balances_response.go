@@ -0,0 +1,28 @@
+package json
+
+import (
+	"casino/boundary/dto"
+)
+
+type BalanceResponse struct {
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+	Formatted string `json:"formatted"`
+}
+
+type BalancesResponse struct {
+	UserID   string            `json:"user_id"`
+	Balances []BalanceResponse `json:"balances"`
+}
+
+func (r *BalancesResponse) FromDtos(userID string, dtos []*dto.BalanceDTO) {
+	r.UserID = userID
+	r.Balances = make([]BalanceResponse, len(dtos))
+	for i, dto := range dtos {
+		r.Balances[i] = BalanceResponse{
+			Currency:  dto.Currency,
+			Balance:   dto.Balance,
+			Formatted: dto.Formatted,
+		}
+	}
+}
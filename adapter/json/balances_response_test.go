@@ -0,0 +1,48 @@
+package json
+
+import (
+	"encoding/json"
+	"testing"
+
+	boundarydto "casino/boundary/dto"
+)
+
+func TestBalancesResponse_FromDtos(t *testing.T) {
+	boundaryDtos := []*boundarydto.BalanceDTO{
+		{Currency: "BTC", Balance: 150000000, Formatted: "1.50000000"},
+		{Currency: "EUR", Balance: -250, Formatted: "-2.50"},
+	}
+
+	response := &BalancesResponse{}
+	response.FromDtos("user123", boundaryDtos)
+
+	if response.UserID != "user123" {
+		t.Errorf("Expected UserID 'user123', got %s", response.UserID)
+	}
+
+	if len(response.Balances) != 2 {
+		t.Fatalf("Expected 2 balances, got %d", len(response.Balances))
+	}
+
+	if response.Balances[0].Currency != "BTC" || response.Balances[0].Balance != 150000000 || response.Balances[0].Formatted != "1.50000000" {
+		t.Errorf("Unexpected BTC balance %+v", response.Balances[0])
+	}
+
+	if response.Balances[1].Balance != -250 {
+		t.Errorf("Expected negative EUR balance, got %d", response.Balances[1].Balance)
+	}
+}
+
+func TestBalancesResponse_EmptyIsArray(t *testing.T) {
+	response := &BalancesResponse{}
+	response.FromDtos("user123", nil)
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"user_id":"user123","balances":[]}` {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
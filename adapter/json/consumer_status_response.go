@@ -0,0 +1,57 @@
+package json
+
+import (
+	"casino/boundary/dto"
+	"time"
+)
+
+type ConsumerStatusResponse struct {
+	GroupID string                `json:"group_id"`
+	Topics  []TopicStatusResponse `json:"topics"`
+}
+
+type TopicStatusResponse struct {
+	Topic         string                    `json:"topic"`
+	State         string                    `json:"state"`
+	LastMessageAt string                    `json:"last_message_at,omitempty"`
+	Partitions    []PartitionStatusResponse `json:"partitions"`
+}
+
+type PartitionStatusResponse struct {
+	Partition       int    `json:"partition"`
+	AssignedTo      string `json:"assigned_to,omitempty"`
+	CommittedOffset *int64 `json:"committed_offset"`
+	HighWaterMark   int64  `json:"high_water_mark"`
+	Lag             *int64 `json:"lag"`
+}
+
+func (r *ConsumerStatusResponse) FromDto(dto *dto.ConsumerStatusDTO) {
+	r.GroupID = dto.GroupID
+	r.Topics = make([]TopicStatusResponse, len(dto.Topics))
+	for i, topic := range dto.Topics {
+		r.Topics[i].FromDto(topic)
+	}
+}
+
+func (r *TopicStatusResponse) FromDto(dto *dto.TopicStatusDTO) {
+	r.Topic = dto.Topic
+	r.State = dto.State
+	if dto.LastMessageAt != nil {
+		r.LastMessageAt = dto.LastMessageAt.UTC().Format(time.RFC3339)
+	}
+	r.Partitions = make([]PartitionStatusResponse, len(dto.Partitions))
+	for i, partition := range dto.Partitions {
+		r.Partitions[i] = PartitionStatusResponse{
+			Partition:       partition.Partition,
+			AssignedTo:      partition.AssignedTo,
+			CommittedOffset: partition.CommittedOffset,
+			HighWaterMark:   partition.HighWaterMark,
+			Lag:             partition.Lag,
+		}
+	}
+}
+
+type ConsumerPauseResponse struct {
+	State   string `json:"state"`
+	Drained bool   `json:"drained"`
+}
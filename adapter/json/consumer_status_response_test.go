@@ -0,0 +1,54 @@
+package json
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+func TestConsumerStatusResponse_FromDto(t *testing.T) {
+	committed, lag := int64(90), int64(10)
+	lastMessageAt := time.Date(2026, 10, 1, 14, 0, 0, 0, time.FixedZone("CEST", 7200))
+
+	response := &ConsumerStatusResponse{}
+	response.FromDto(&boundarydto.ConsumerStatusDTO{
+		GroupID: "casino-transaction-consumer",
+		Topics: []*boundarydto.TopicStatusDTO{{
+			Topic:         "casino-transactions-stream",
+			State:         boundarydto.ConsumerStatePaused,
+			LastMessageAt: &lastMessageAt,
+			Partitions: []*boundarydto.PartitionStatusDTO{
+				{Partition: 0, AssignedTo: "casino@host-1", CommittedOffset: &committed, HighWaterMark: 100, Lag: &lag},
+				{Partition: 1, HighWaterMark: 5},
+			},
+		}},
+	})
+
+	if response.GroupID != "casino-transaction-consumer" || len(response.Topics) != 1 {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+
+	topic := response.Topics[0]
+	if topic.State != "paused" || topic.LastMessageAt != "2026-10-01T12:00:00Z" {
+		t.Errorf("Expected paused state and UTC timestamp, got %+v", topic)
+	}
+
+	body, _ := json.Marshal(topic.Partitions)
+	expected := `[{"partition":0,"assigned_to":"casino@host-1","committed_offset":90,"high_water_mark":100,"lag":10},{"partition":1,"committed_offset":null,"high_water_mark":5,"lag":null}]`
+	if string(body) != expected {
+		t.Errorf("Unexpected partitions %s", body)
+	}
+}
+
+func TestTopicStatusResponse_FromDto_NoMessages(t *testing.T) {
+	response := &TopicStatusResponse{}
+	response.FromDto(&boundarydto.TopicStatusDTO{Topic: "casino-rollbacks", State: boundarydto.ConsumerStateRunning})
+
+	body, _ := json.Marshal(response)
+	if strings.Contains(string(body), "last_message_at") {
+		t.Errorf("Expected last_message_at to be omitted, got %s", body)
+	}
+}
@@ -0,0 +1,33 @@
+package json
+
+import (
+	"casino/boundary/dto"
+	"time"
+)
+
+type ExchangeRateResponse struct {
+	Currency    string `json:"currency"`
+	Rate        string `json:"rate"`
+	EffectiveAt string `json:"effective_at"`
+}
+
+func (r *ExchangeRateResponse) FromDto(dto *dto.ExchangeRateDTO) {
+	r.Currency = dto.Currency
+	r.Rate = dto.Rate
+	r.EffectiveAt = dto.EffectiveAt.UTC().Format(time.RFC3339)
+}
+
+type ExchangeRatesResponse struct {
+	Rates []ExchangeRateResponse `json:"rates"`
+}
+
+func (r *ExchangeRatesResponse) FromDtos(dtos []*dto.ExchangeRateDTO) {
+	r.Rates = make([]ExchangeRateResponse, len(dtos))
+	for i, dto := range dtos {
+		r.Rates[i].FromDto(dto)
+	}
+}
+
+type ExchangeRateImportResponse struct {
+	Imported int `json:"imported"`
+}
@@ -0,0 +1,31 @@
+package json
+
+import (
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+func TestExchangeRatesResponse_FromDtos(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	boundaryDtos := []*boundarydto.ExchangeRateDTO{
+		{Currency: "USD", Rate: "0.92", EffectiveAt: time.Date(2026, 3, 1, 1, 0, 0, 0, cet)},
+		{Currency: "BTC", Rate: "60000.5", EffectiveAt: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	response := &ExchangeRatesResponse{}
+	response.FromDtos(boundaryDtos)
+
+	if len(response.Rates) != 2 {
+		t.Fatalf("Expected 2 rates, got %d", len(response.Rates))
+	}
+
+	if response.Rates[0].EffectiveAt != "2026-03-01T00:00:00Z" {
+		t.Errorf("Expected effective_at in UTC, got %s", response.Rates[0].EffectiveAt)
+	}
+
+	if response.Rates[1].Currency != "BTC" || response.Rates[1].Rate != "60000.5" {
+		t.Errorf("Unexpected rate %+v", response.Rates[1])
+	}
+}
@@ -0,0 +1,49 @@
+package json
+
+import (
+	"casino/boundary/dto"
+	"time"
+)
+
+type GGRRowResponse struct {
+	Key              string `json:"key,omitempty"`
+	TotalBets        int64  `json:"total_bets"`
+	TotalWins        int64  `json:"total_wins"`
+	GGR              int64  `json:"ggr"`
+	BetCount         int64  `json:"bet_count"`
+	UniquePlayers    int64  `json:"unique_players"`
+	UnconvertedCount int64  `json:"unconverted_count"`
+}
+
+type GGRReportResponse struct {
+	From     string           `json:"from"`
+	To       string           `json:"to"`
+	GroupBy  string           `json:"group_by"`
+	Currency string           `json:"currency"`
+	Rows     []GGRRowResponse `json:"rows"`
+	Total    GGRRowResponse   `json:"total"`
+}
+
+func (r *GGRReportResponse) FromDto(dto *dto.GGRReportDTO) {
+	r.From = dto.From.Format(time.RFC3339)
+	r.To = dto.To.Format(time.RFC3339)
+	r.GroupBy = dto.GroupBy
+	r.Currency = dto.Currency
+	r.Rows = make([]GGRRowResponse, len(dto.Rows))
+	for i, row := range dto.Rows {
+		r.Rows[i].FromDto(row)
+	}
+	if dto.Total != nil {
+		r.Total.FromDto(dto.Total)
+	}
+}
+
+func (r *GGRRowResponse) FromDto(dto *dto.GGRRowDTO) {
+	r.Key = dto.Key
+	r.TotalBets = dto.TotalBets
+	r.TotalWins = dto.TotalWins
+	r.GGR = dto.GGR
+	r.BetCount = dto.BetCount
+	r.UniquePlayers = dto.UniquePlayers
+	r.UnconvertedCount = dto.UnconvertedCount
+}
@@ -0,0 +1,61 @@
+package json
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+func TestGGRReportResponse_FromDto(t *testing.T) {
+	report := &boundarydto.GGRReportDTO{
+		From:     time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		GroupBy:  "day",
+		Currency: "EUR",
+		Rows: []*boundarydto.GGRRowDTO{
+			{Key: "2026-03-01", TotalBets: 1000, TotalWins: 400, GGR: 600, BetCount: 2, UniquePlayers: 1},
+			{Key: "2026-03-02", TotalBets: 300, GGR: 300, BetCount: 1, UniquePlayers: 1, UnconvertedCount: 1},
+		},
+		Total: &boundarydto.GGRRowDTO{TotalBets: 1300, TotalWins: 400, GGR: 900, BetCount: 3, UniquePlayers: 2, UnconvertedCount: 1},
+	}
+
+	response := &GGRReportResponse{}
+	response.FromDto(report)
+
+	if response.From != "2026-03-01T00:00:00Z" || response.To != "2026-03-03T00:00:00Z" {
+		t.Errorf("Unexpected range %s - %s", response.From, response.To)
+	}
+
+	if response.GroupBy != "day" || response.Currency != "EUR" {
+		t.Errorf("Unexpected grouping %s or currency %s", response.GroupBy, response.Currency)
+	}
+
+	if len(response.Rows) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(response.Rows))
+	}
+
+	if response.Rows[0].Key != "2026-03-01" || response.Rows[0].GGR != 600 {
+		t.Errorf("Unexpected first row %+v", response.Rows[0])
+	}
+
+	if response.Total.GGR != 900 || response.Total.UniquePlayers != 2 || response.Total.UnconvertedCount != 1 {
+		t.Errorf("Unexpected total %+v", response.Total)
+	}
+}
+
+func TestGGRReportResponse_EmptyRowsIsArray(t *testing.T) {
+	response := &GGRReportResponse{}
+	response.FromDto(&boundarydto.GGRReportDTO{GroupBy: "user", Currency: "EUR", Total: &boundarydto.GGRRowDTO{}})
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"rows":[]`) {
+		t.Errorf("Expected empty rows array, got %s", data)
+	}
+}
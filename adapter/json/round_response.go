@@ -0,0 +1,37 @@
+package json
+
+import (
+	"casino/boundary/dto"
+)
+
+type RoundResponse struct {
+	RoundID    string                `json:"round_id"`
+	GameID     string                `json:"game_id"`
+	ProviderID string                `json:"provider_id"`
+	UserID     string                `json:"user_id"`
+	Currency   string                `json:"currency"`
+	Bets       []TransactionResponse `json:"bets"`
+	Wins       []TransactionResponse `json:"wins"`
+	TotalBet   uint                  `json:"total_bet"`
+	TotalWin   uint                  `json:"total_win"`
+	NetResult  int64                 `json:"net_result"`
+}
+
+func (r *RoundResponse) FromDto(dto *dto.RoundDTO) {
+	r.RoundID = dto.RoundID
+	r.GameID = dto.GameID
+	r.ProviderID = dto.ProviderID
+	r.UserID = dto.UserID
+	r.Currency = dto.Currency
+	r.Bets = make([]TransactionResponse, len(dto.Bets))
+	for i, bet := range dto.Bets {
+		r.Bets[i].FromDto(bet)
+	}
+	r.Wins = make([]TransactionResponse, len(dto.Wins))
+	for i, win := range dto.Wins {
+		r.Wins[i].FromDto(win)
+	}
+	r.TotalBet = dto.TotalBet
+	r.TotalWin = dto.TotalWin
+	r.NetResult = dto.NetResult
+}
@@ -0,0 +1,51 @@
+package json
+
+import (
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+)
+
+func TestRoundResponse_FromDto(t *testing.T) {
+	userID := utils.GenerateUUID()
+	boundaryDto := &boundarydto.RoundDTO{
+		RoundID:    "round-1",
+		GameID:     "starburst",
+		ProviderID: "netent",
+		UserID:     userID,
+		Bets: []*boundarydto.TransactionDTO{
+			{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 100, Timestamp: time.Now(), RoundID: "round-1"},
+		},
+		Wins: []*boundarydto.TransactionDTO{
+			{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "win", Amount: 250, Timestamp: time.Now(), RoundID: "round-1"},
+		},
+		TotalBet:  100,
+		TotalWin:  250,
+		NetResult: 150,
+	}
+
+	response := &RoundResponse{}
+	response.FromDto(boundaryDto)
+
+	if response.RoundID != "round-1" {
+		t.Errorf("Expected RoundID 'round-1', got %s", response.RoundID)
+	}
+
+	if response.GameID != "starburst" || response.ProviderID != "netent" {
+		t.Errorf("Expected game and provider to be set, got %s/%s", response.GameID, response.ProviderID)
+	}
+
+	if len(response.Bets) != 1 || response.Bets[0].ID != boundaryDto.Bets[0].ID {
+		t.Error("Expected bet to be converted")
+	}
+
+	if len(response.Wins) != 1 || response.Wins[0].Amount != 250 {
+		t.Error("Expected win to be converted")
+	}
+
+	if response.NetResult != 150 {
+		t.Errorf("Expected NetResult 150, got %d", response.NetResult)
+	}
+}
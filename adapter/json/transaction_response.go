@@ -6,11 +6,19 @@ import (
 )
 
 type TransactionResponse struct {
-	ID              string `json:"id"`
-	UserID          string `json:"user_id"`
-	TransactionType string `json:"transaction_type"`
-	Amount          uint   `json:"amount"`
-	Timestamp       string `json:"timestamp"`
+	ID                    string `json:"id"`
+	UserID                string `json:"user_id"`
+	TransactionType       string `json:"transaction_type"`
+	Amount                uint   `json:"amount"`
+	Currency              string `json:"currency"`
+	Timestamp             string `json:"timestamp"`
+	RoundID               string `json:"round_id,omitempty"`
+	GameID                string `json:"game_id,omitempty"`
+	ProviderID            string `json:"provider_id,omitempty"`
+	OriginalTransactionID string `json:"original_transaction_id,omitempty"`
+	Reason                string `json:"reason,omitempty"`
+	ReportingAmount       *int64 `json:"reporting_amount,omitempty"`
+	ExchangeRate          string `json:"exchange_rate,omitempty"`
 }
 
 func (r *TransactionResponse) FromDto(dto *dto.TransactionDTO) {
@@ -18,6 +26,13 @@ func (r *TransactionResponse) FromDto(dto *dto.TransactionDTO) {
 	r.UserID = dto.UserID
 	r.TransactionType = dto.TransactionType
 	r.Amount = dto.Amount
+	r.Currency = dto.Currency
 	r.Timestamp = dto.Timestamp.Format(time.RFC3339)
+	r.RoundID = dto.RoundID
+	r.GameID = dto.GameID
+	r.ProviderID = dto.ProviderID
+	r.OriginalTransactionID = dto.OriginalTransactionID
+	r.Reason = dto.Reason
+	r.ReportingAmount = dto.ReportingAmount
+	r.ExchangeRate = dto.ExchangeRate
 }
-
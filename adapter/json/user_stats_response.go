@@ -0,0 +1,65 @@
+package json
+
+import (
+	"casino/boundary/dto"
+	"math"
+	"time"
+)
+
+type UserStatsPeriodResponse struct {
+	TotalBets        int64    `json:"total_bets"`
+	TotalWins        int64    `json:"total_wins"`
+	NetResult        int64    `json:"net_result"`
+	TransactionCount int64    `json:"transaction_count"`
+	BetCount         int64    `json:"bet_count"`
+	WinCount         int64    `json:"win_count"`
+	BiggestWin       int64    `json:"biggest_win"`
+	UnconvertedCount int64    `json:"unconverted_count"`
+	FirstActivity    string   `json:"first_activity,omitempty"`
+	LastActivity     string   `json:"last_activity,omitempty"`
+	RTP              *float64 `json:"rtp"`
+}
+
+type UserStatsResponse struct {
+	UserID   string                  `json:"user_id"`
+	Currency string                  `json:"currency"`
+	Lifetime UserStatsPeriodResponse `json:"lifetime"`
+	Last24h  UserStatsPeriodResponse `json:"last_24h"`
+	Last7d   UserStatsPeriodResponse `json:"last_7d"`
+	Last30d  UserStatsPeriodResponse `json:"last_30d"`
+}
+
+func (r *UserStatsResponse) FromDto(dto *dto.UserStatsDTO) {
+	r.UserID = dto.UserID
+	r.Currency = dto.Currency
+	r.Lifetime.FromDto(dto.Lifetime)
+	r.Last24h.FromDto(dto.Last24h)
+	r.Last7d.FromDto(dto.Last7d)
+	r.Last30d.FromDto(dto.Last30d)
+}
+
+func (r *UserStatsPeriodResponse) FromDto(dto *dto.UserStatsPeriodDTO) {
+	if dto == nil {
+		return
+	}
+
+	r.TotalBets = dto.TotalBets
+	r.TotalWins = dto.TotalWins
+	r.NetResult = dto.NetResult
+	r.TransactionCount = dto.TransactionCount
+	r.BetCount = dto.BetCount
+	r.WinCount = dto.WinCount
+	r.BiggestWin = dto.BiggestWin
+	r.UnconvertedCount = dto.UnconvertedCount
+
+	if dto.FirstActivity != nil {
+		r.FirstActivity = dto.FirstActivity.Format(time.RFC3339)
+	}
+	if dto.LastActivity != nil {
+		r.LastActivity = dto.LastActivity.Format(time.RFC3339)
+	}
+	if dto.RTP != nil {
+		rtp := math.Round(*dto.RTP*100) / 100
+		r.RTP = &rtp
+	}
+}
@@ -0,0 +1,65 @@
+package json
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+)
+
+func TestUserStatsResponse_FromDto(t *testing.T) {
+	first := time.Date(2025, 1, 1, 9, 30, 0, 0, time.UTC)
+	last := time.Date(2026, 3, 31, 11, 0, 0, 0, time.UTC)
+	rtp := 95.12345
+
+	stats := &boundarydto.UserStatsDTO{
+		UserID:   "user123",
+		Currency: "EUR",
+		Lifetime: &boundarydto.UserStatsPeriodDTO{
+			TotalBets: 10000, TotalWins: 9512, NetResult: -488, TransactionCount: 55,
+			BetCount: 40, WinCount: 12, BiggestWin: 3000, FirstActivity: &first, LastActivity: &last, RTP: &rtp,
+		},
+		Last24h: &boundarydto.UserStatsPeriodDTO{},
+		Last7d:  &boundarydto.UserStatsPeriodDTO{},
+		Last30d: &boundarydto.UserStatsPeriodDTO{TotalBets: 100, NetResult: -100, BetCount: 1},
+	}
+
+	response := &UserStatsResponse{}
+	response.FromDto(stats)
+
+	if response.UserID != "user123" || response.Currency != "EUR" {
+		t.Errorf("Unexpected user %s or currency %s", response.UserID, response.Currency)
+	}
+
+	if response.Lifetime.NetResult != -488 || response.Lifetime.BiggestWin != 3000 {
+		t.Errorf("Unexpected lifetime stats %+v", response.Lifetime)
+	}
+
+	if response.Lifetime.FirstActivity != "2025-01-01T09:30:00Z" || response.Lifetime.LastActivity != "2026-03-31T11:00:00Z" {
+		t.Errorf("Unexpected activity range %s - %s", response.Lifetime.FirstActivity, response.Lifetime.LastActivity)
+	}
+
+	if response.Lifetime.RTP == nil || *response.Lifetime.RTP != 95.12 {
+		t.Errorf("Expected RTP rounded to 95.12, got %v", response.Lifetime.RTP)
+	}
+
+	if response.Last30d.BetCount != 1 || response.Last30d.RTP != nil {
+		t.Errorf("Unexpected 30 day stats %+v", response.Last30d)
+	}
+}
+
+func TestUserStatsResponse_NoBetsHasNullRTP(t *testing.T) {
+	response := &UserStatsResponse{}
+	response.FromDto(&boundarydto.UserStatsDTO{UserID: "user123", Currency: "EUR", Lifetime: &boundarydto.UserStatsPeriodDTO{}})
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"rtp":null`) || strings.Contains(string(data), "first_activity") {
+		t.Errorf("Unexpected JSON %s", data)
+	}
+}
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+)
+
+type CreateAuditRecordDTO struct {
+	Actor   string
+	Action  string
+	Source  string
+	Payload []byte
+	Err     error
+}
+
+type AuditVerificationDTO struct {
+	From         time.Time
+	To           time.Time
+	Checked      int
+	Valid        bool
+	BrokenAtID   string
+	BrokenReason string
+}
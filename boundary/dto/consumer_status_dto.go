@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+)
+
+const (
+	ConsumerStateRunning  = "running"
+	ConsumerStatePaused   = "paused"
+	ConsumerStateRetrying = "retrying"
+	ConsumerStateStopped  = "stopped"
+)
+
+type ConsumerStatusDTO struct {
+	GroupID string
+	Topics  []*TopicStatusDTO
+}
+
+type TopicStatusDTO struct {
+	Topic         string
+	State         string
+	LastMessageAt *time.Time
+	Partitions    []*PartitionStatusDTO
+}
+
+// PartitionStatusDTO leaves CommittedOffset and Lag nil while the group has
+// not committed anything for the partition.
+type PartitionStatusDTO struct {
+	Partition       int
+	AssignedTo      string
+	CommittedOffset *int64
+	HighWaterMark   int64
+	Lag             *int64
+}
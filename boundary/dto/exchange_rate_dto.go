@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"casino/domain/entity"
+)
+
+type ExchangeRateDTO struct {
+	Currency    string
+	Rate        string
+	EffectiveAt time.Time
+}
+
+func (d *ExchangeRateDTO) FromEntity(entity *entity.ExchangeRate) {
+	d.Currency = string(entity.Currency)
+	d.Rate = entity.Rate
+	d.EffectiveAt = entity.EffectiveAt
+}
+
+func (d *ExchangeRateDTO) ToEntity() *entity.ExchangeRate {
+	return &entity.ExchangeRate{
+		Currency:    entity.Currency(d.Currency),
+		Rate:        d.Rate,
+		EffectiveAt: d.EffectiveAt,
+	}
+}
+
+type ConversionDTO struct {
+	Amount int64
+	Rate   string
+}
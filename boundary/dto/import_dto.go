@@ -0,0 +1,24 @@
+package dto
+
+type ImportRowDTO struct {
+	Line int
+	// Source names the row in the audit trail, e.g. "history.csv:12".
+	Source string
+	// Payload is the row as it was read, recorded with its audit record.
+	Payload     []byte
+	Transaction *CreateTransactionDTO
+	// Err is set instead of Transaction when the row could not be read.
+	Err error
+}
+
+type ImportRowErrorDTO struct {
+	Line          int
+	TransactionID string
+	Reason        string
+}
+
+type ImportBatchResultDTO struct {
+	Inserted   int
+	Duplicates []*ImportRowErrorDTO
+	Invalid    []*ImportRowErrorDTO
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+)
+
+type GGRFilterDTO struct {
+	From    time.Time
+	To      time.Time
+	GroupBy string
+}
+
+type GGRRowDTO struct {
+	Key              string
+	TotalBets        int64
+	TotalWins        int64
+	GGR              int64
+	BetCount         int64
+	UniquePlayers    int64
+	UnconvertedCount int64
+}
+
+type GGRReportDTO struct {
+	From     time.Time
+	To       time.Time
+	GroupBy  string
+	Currency string
+	Rows     []*GGRRowDTO
+	Total    *GGRRowDTO
+}
+
+type UserStatsPeriodDTO struct {
+	TotalBets        int64
+	TotalWins        int64
+	NetResult        int64
+	TransactionCount int64
+	BetCount         int64
+	WinCount         int64
+	BiggestWin       int64
+	UnconvertedCount int64
+	FirstActivity    *time.Time
+	LastActivity     *time.Time
+	RTP              *float64
+}
+
+type UserStatsDTO struct {
+	UserID   string
+	Currency string
+	Lifetime *UserStatsPeriodDTO
+	Last24h  *UserStatsPeriodDTO
+	Last7d   *UserStatsPeriodDTO
+	Last30d  *UserStatsPeriodDTO
+}
@@ -0,0 +1,14 @@
+package dto
+
+type RoundDTO struct {
+	RoundID    string
+	GameID     string
+	ProviderID string
+	UserID     string
+	Currency   string
+	Bets       []*TransactionDTO
+	Wins       []*TransactionDTO
+	TotalBet   uint
+	TotalWin   uint
+	NetResult  int64
+}
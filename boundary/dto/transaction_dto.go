@@ -6,11 +6,19 @@ import (
 )
 
 type TransactionDTO struct {
-	ID              string   
-	UserID          string    
-	TransactionType string    
-	Amount          uint      
-	Timestamp       time.Time 
+	ID                    string
+	UserID                string
+	TransactionType       string
+	Amount                uint
+	Currency              string
+	Timestamp             time.Time
+	RoundID               string
+	GameID                string
+	ProviderID            string
+	OriginalTransactionID string
+	Reason                string
+	ReportingAmount       *int64
+	ExchangeRate          string
 }
 
 func (d *TransactionDTO) FromEntity(entity *entity.Transaction) {
@@ -18,45 +26,90 @@ func (d *TransactionDTO) FromEntity(entity *entity.Transaction) {
 	d.UserID = entity.UserID
 	d.TransactionType = string(entity.TransactionType)
 	d.Amount = entity.Amount
+	d.Currency = string(entity.Currency)
 	d.Timestamp = entity.Timestamp
+	d.RoundID = entity.RoundID
+	d.GameID = entity.GameID
+	d.ProviderID = entity.ProviderID
+	d.OriginalTransactionID = entity.OriginalTransactionID
+	d.Reason = entity.Reason
+	d.ReportingAmount = entity.ReportingAmount
+	d.ExchangeRate = entity.ExchangeRate
 }
 
 func (d *TransactionDTO) ToEntity() *entity.Transaction {
 	return &entity.Transaction{
-		ID:              d.ID,
-		UserID:          d.UserID,
-		TransactionType: entity.TransactionType(d.TransactionType),
-		Amount:          d.Amount,
-		Timestamp:       d.Timestamp,
+		ID:                    d.ID,
+		UserID:                d.UserID,
+		TransactionType:       entity.TransactionType(d.TransactionType),
+		Amount:                d.Amount,
+		Currency:              entity.Currency(d.Currency),
+		Timestamp:             d.Timestamp,
+		RoundID:               d.RoundID,
+		GameID:                d.GameID,
+		ProviderID:            d.ProviderID,
+		OriginalTransactionID: d.OriginalTransactionID,
+		Reason:                d.Reason,
+		ReportingAmount:       d.ReportingAmount,
+		ExchangeRate:          d.ExchangeRate,
 	}
 }
 
 type CreateTransactionDTO struct {
-	ID              string
-	UserID          string 
-	TransactionType string 
-	Amount          uint   
+	ID                    string
+	UserID                string
+	TransactionType       string
+	Amount                uint
+	Currency              string
+	RoundID               string
+	GameID                string
+	ProviderID            string
+	OriginalTransactionID string
+	Reason                string
+	// Timestamp is only set for imported historical transactions. Anything
+	// else is stamped with the time it is processed.
+	Timestamp time.Time
 }
 
 func (d *CreateTransactionDTO) FromEntity(entity *entity.Transaction) {
 	d.UserID = entity.UserID
 	d.TransactionType = string(entity.TransactionType)
 	d.Amount = entity.Amount
+	d.Currency = string(entity.Currency)
+	d.RoundID = entity.RoundID
+	d.GameID = entity.GameID
+	d.ProviderID = entity.ProviderID
+	d.OriginalTransactionID = entity.OriginalTransactionID
+	d.Reason = entity.Reason
 }
 
 func (d *CreateTransactionDTO) ToEntity() *entity.Transaction {
+	// Timestamps are kept in UTC: the column has no zone, and transactions are
+	// aggregated under their UTC day.
+	timestamp := d.Timestamp.UTC()
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+
 	return &entity.Transaction{
-		ID:              d.ID,
-		UserID:          d.UserID,
-		TransactionType: entity.TransactionType(d.TransactionType),
-		Amount:          d.Amount,
-		Timestamp:       time.Now(),
+		ID:                    d.ID,
+		UserID:                d.UserID,
+		TransactionType:       entity.TransactionType(d.TransactionType),
+		Amount:                d.Amount,
+		Currency:              entity.Currency(d.Currency),
+		Timestamp:             timestamp,
+		RoundID:               d.RoundID,
+		GameID:                d.GameID,
+		ProviderID:            d.ProviderID,
+		OriginalTransactionID: d.OriginalTransactionID,
+		Reason:                d.Reason,
 	}
 }
 
 type TransactionFilterDTO struct {
-	UserID          *string 
-	TransactionType *string 
+	UserID          *string
+	TransactionType *string
+	Currency        *string
 }
 
 func (d *TransactionFilterDTO) ToEntity() *entity.TransactionType {
@@ -66,3 +119,15 @@ func (d *TransactionFilterDTO) ToEntity() *entity.TransactionType {
 	tt := entity.TransactionType(*d.TransactionType)
 	return &tt
 }
+
+type CancelTransactionDTO struct {
+	ID                    string
+	OriginalTransactionID string
+	Reason                string
+}
+
+type BalanceDTO struct {
+	Currency  string
+	Balance   int64
+	Formatted string
+}
@@ -0,0 +1,30 @@
+package dto
+
+// TransactionEventDTO is a transaction as delivered by the live feed. IDs
+// increase with every published transaction.
+type TransactionEventDTO struct {
+	ID          uint64
+	Transaction *TransactionDTO
+}
+
+type TransactionStreamFilterDTO struct {
+	UserID          *string
+	TransactionType *string
+	MinAmount       *uint
+}
+
+func (f *TransactionStreamFilterDTO) Matches(transaction *TransactionDTO) bool {
+	if f == nil {
+		return true
+	}
+	if f.UserID != nil && transaction.UserID != *f.UserID {
+		return false
+	}
+	if f.TransactionType != nil && transaction.TransactionType != *f.TransactionType {
+		return false
+	}
+	if f.MinAmount != nil && transaction.Amount < *f.MinAmount {
+		return false
+	}
+	return true
+}
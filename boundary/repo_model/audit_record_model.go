@@ -0,0 +1,50 @@
+package repo_model
+
+import (
+	"time"
+
+	"casino/domain/entity"
+)
+
+type AuditRecordModel struct {
+	Sequence    uint64    `gorm:"primaryKey;autoIncrement"`
+	ID          string    `gorm:"type:uuid;not null;uniqueIndex"`
+	Timestamp   time.Time `gorm:"type:timestamp;not null;index"`
+	Actor       string    `gorm:"type:varchar(100);not null"`
+	Action      string    `gorm:"type:varchar(50);not null"`
+	Source      string    `gorm:"type:varchar(255);not null"`
+	PayloadHash string    `gorm:"type:varchar(64);not null"`
+	Outcome     string    `gorm:"type:varchar(20);not null"`
+	PrevHash    string    `gorm:"type:varchar(64);not null"`
+	Hash        string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+}
+
+func (AuditRecordModel) TableName() string {
+	return "audit_log"
+}
+
+func (m *AuditRecordModel) ToEntity() *entity.AuditRecord {
+	return &entity.AuditRecord{
+		ID:          m.ID,
+		Timestamp:   m.Timestamp,
+		Actor:       m.Actor,
+		Action:      m.Action,
+		Source:      m.Source,
+		PayloadHash: m.PayloadHash,
+		Outcome:     entity.AuditOutcome(m.Outcome),
+		PrevHash:    m.PrevHash,
+		Hash:        m.Hash,
+	}
+}
+
+func (m *AuditRecordModel) FromEntity(entity *entity.AuditRecord) {
+	m.ID = entity.ID
+	m.Timestamp = entity.Timestamp
+	m.Actor = entity.Actor
+	m.Action = entity.Action
+	m.Source = entity.Source
+	m.PayloadHash = entity.PayloadHash
+	m.Outcome = string(entity.Outcome)
+	m.PrevHash = entity.PrevHash
+	m.Hash = entity.Hash
+}
@@ -0,0 +1,30 @@
+package repo_model
+
+import (
+	"time"
+)
+
+// DailyUserAggregateModel holds the per day totals of one user for one
+// transaction type. Rollbacks and refunds are keyed by the type of the
+// transaction they reverse so that reports can net them out.
+type DailyUserAggregateModel struct {
+	Day                     time.Time `gorm:"primaryKey;type:date"`
+	UserID                  string    `gorm:"primaryKey;type:uuid"`
+	Currency                string    `gorm:"primaryKey;type:varchar(10)"`
+	TransactionType         string    `gorm:"primaryKey;type:varchar(20)"`
+	OriginalTransactionType string    `gorm:"primaryKey;type:varchar(20)"`
+	AmountSum               int64     `gorm:"type:bigint;not null;default:0"`
+	ReportingAmountSum      int64     `gorm:"type:bigint;not null;default:0"`
+	Count                   int64     `gorm:"type:bigint;not null;default:0"`
+	UnconvertedCount        int64     `gorm:"type:bigint;not null;default:0"`
+}
+
+func (DailyUserAggregateModel) TableName() string {
+	return "daily_user_aggregates"
+}
+
+// AggregateDay returns the UTC day a transaction is aggregated under.
+func AggregateDay(timestamp time.Time) time.Time {
+	utc := timestamp.UTC()
+	return time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+}
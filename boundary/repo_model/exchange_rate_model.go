@@ -0,0 +1,33 @@
+package repo_model
+
+import (
+	"time"
+
+	"casino/domain/entity"
+)
+
+type ExchangeRateModel struct {
+	ID          uint64    `gorm:"primaryKey;autoIncrement"`
+	Currency    string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_exchange_rates_currency_effective_at"`
+	Rate        string    `gorm:"type:numeric(36,18);not null"`
+	EffectiveAt time.Time `gorm:"type:timestamp;not null;uniqueIndex:idx_exchange_rates_currency_effective_at"`
+	CreatedAt   time.Time `gorm:"type:timestamp;not null"`
+}
+
+func (ExchangeRateModel) TableName() string {
+	return "exchange_rates"
+}
+
+func (m *ExchangeRateModel) ToEntity() *entity.ExchangeRate {
+	return &entity.ExchangeRate{
+		Currency:    entity.Currency(m.Currency),
+		Rate:        m.Rate,
+		EffectiveAt: m.EffectiveAt,
+	}
+}
+
+func (m *ExchangeRateModel) FromEntity(entity *entity.ExchangeRate) {
+	m.Currency = string(entity.Currency)
+	m.Rate = entity.Rate
+	m.EffectiveAt = entity.EffectiveAt
+}
@@ -0,0 +1,10 @@
+package repo_model
+
+type GGRRowModel struct {
+	GroupKey         string
+	TotalBets        int64
+	TotalWins        int64
+	BetCount         int64
+	UniquePlayers    int64
+	UnconvertedCount int64
+}
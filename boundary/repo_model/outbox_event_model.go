@@ -0,0 +1,23 @@
+package repo_model
+
+import (
+	"time"
+)
+
+const TransactionProcessedEventType = "transaction.processed"
+
+// OutboxEventModel is an event waiting to be published. It is written in the
+// same database transaction as the change it describes and marked sent once
+// the relay has handed it to Kafka.
+type OutboxEventModel struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement"`
+	EventType string     `gorm:"type:varchar(50);not null"`
+	Key       string     `gorm:"type:varchar(100);not null"`
+	Payload   []byte     `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time  `gorm:"type:timestamp;not null"`
+	SentAt    *time.Time `gorm:"type:timestamp"`
+}
+
+func (OutboxEventModel) TableName() string {
+	return "outbox_events"
+}
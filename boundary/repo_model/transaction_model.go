@@ -7,11 +7,19 @@ import (
 )
 
 type TransactionModel struct {
-	ID              string    `gorm:"primaryKey;type:uuid"`
-	UserID          string    `gorm:"type:uuid;not null"`
-	TransactionType string    `gorm:"type:varchar(10);not null;check:transaction_type IN ('bet', 'win')"`
-	Amount          uint      `gorm:"type:integer;not null;check:amount > 0"`
-	Timestamp       time.Time `gorm:"type:timestamp;not null"`
+	ID                    string    `gorm:"primaryKey;type:uuid"`
+	UserID                string    `gorm:"type:uuid;not null"`
+	TransactionType       string    `gorm:"type:varchar(20);not null;check:transaction_type IN ('bet', 'win', 'deposit', 'withdrawal', 'refund', 'rollback', 'bonus_credit')"`
+	Amount                uint      `gorm:"type:bigint;not null;check:amount > 0"`
+	Currency              string    `gorm:"type:varchar(10);not null;default:'EUR';index"`
+	Timestamp             time.Time `gorm:"type:timestamp;not null;index"`
+	RoundID               string    `gorm:"type:varchar(100);not null;default:'';index"`
+	GameID                string    `gorm:"type:varchar(100);not null;default:''"`
+	ProviderID            string    `gorm:"type:varchar(100);not null;default:''"`
+	OriginalTransactionID *string   `gorm:"type:uuid;index;uniqueIndex:idx_transactions_single_rollback,where:transaction_type = 'rollback'"`
+	Reason                string    `gorm:"type:varchar(255);not null;default:''"`
+	ReportingAmount       *int64    `gorm:"type:bigint"`
+	ExchangeRate          *string   `gorm:"type:numeric(36,18)"`
 }
 
 func (TransactionModel) TableName() string {
@@ -19,13 +27,26 @@ func (TransactionModel) TableName() string {
 }
 
 func (m *TransactionModel) ToEntity() *entity.Transaction {
-	return &entity.Transaction{
+	transaction := &entity.Transaction{
 		ID:              m.ID,
 		UserID:          m.UserID,
 		TransactionType: entity.TransactionType(m.TransactionType),
 		Amount:          m.Amount,
+		Currency:        entity.Currency(m.Currency),
 		Timestamp:       m.Timestamp,
+		RoundID:         m.RoundID,
+		GameID:          m.GameID,
+		ProviderID:      m.ProviderID,
+		Reason:          m.Reason,
+		ReportingAmount: m.ReportingAmount,
 	}
+	if m.OriginalTransactionID != nil {
+		transaction.OriginalTransactionID = *m.OriginalTransactionID
+	}
+	if m.ExchangeRate != nil {
+		transaction.ExchangeRate = *m.ExchangeRate
+	}
+	return transaction
 }
 
 func (m *TransactionModel) FromEntity(entity *entity.Transaction) {
@@ -33,5 +54,21 @@ func (m *TransactionModel) FromEntity(entity *entity.Transaction) {
 	m.UserID = entity.UserID
 	m.TransactionType = string(entity.TransactionType)
 	m.Amount = entity.Amount
+	m.Currency = string(entity.Currency)
 	m.Timestamp = entity.Timestamp
+	m.RoundID = entity.RoundID
+	m.GameID = entity.GameID
+	m.ProviderID = entity.ProviderID
+	m.Reason = entity.Reason
+	m.ReportingAmount = entity.ReportingAmount
+	m.OriginalTransactionID = nil
+	if entity.OriginalTransactionID != "" {
+		originalID := entity.OriginalTransactionID
+		m.OriginalTransactionID = &originalID
+	}
+	m.ExchangeRate = nil
+	if entity.ExchangeRate != "" {
+		rate := entity.ExchangeRate
+		m.ExchangeRate = &rate
+	}
 }
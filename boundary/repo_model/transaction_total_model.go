@@ -0,0 +1,9 @@
+package repo_model
+
+type TransactionTotalModel struct {
+	Currency                string
+	TransactionType         string
+	OriginalTransactionType string
+	Total                   int64
+	Count                   int64
+}
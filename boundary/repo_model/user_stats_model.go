@@ -0,0 +1,17 @@
+package repo_model
+
+import (
+	"time"
+)
+
+type UserStatsModel struct {
+	TotalBets        int64
+	TotalWins        int64
+	BetCount         int64
+	WinCount         int64
+	TransactionCount int64
+	BiggestWin       int64
+	UnconvertedCount int64
+	FirstActivity    *time.Time `gorm:"-"`
+	LastActivity     *time.Time `gorm:"-"`
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"time"
+
+	"casino/boundary/repo_model"
+)
+
+type AuditRepository interface {
+	// Append builds the next record from the last stored one and inserts it
+	// while holding a lock, so concurrent writers cannot fork the chain.
+	Append(build func(last *repo_model.AuditRecordModel) (*repo_model.AuditRecordModel, error)) error
+	GetRange(from, to time.Time) ([]*repo_model.AuditRecordModel, error)
+	GetBefore(sequence uint64) (*repo_model.AuditRecordModel, error)
+}
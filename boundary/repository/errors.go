@@ -0,0 +1,17 @@
+package repository
+
+import "errors"
+
+// Repository implementations wrap storage errors so that callers can tell
+// the common failures apart with errors.Is, whatever the database.
+var (
+	// ErrNotFound is returned when a lookup by key finds no record.
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is a unique key violation, typically a row saved twice.
+	ErrDuplicate = errors.New("duplicate record")
+	// ErrUnavailable means the database could not be reached or dropped the
+	// connection. Retrying later may succeed.
+	ErrUnavailable = errors.New("database unavailable")
+	// ErrConstraint is any other integrity constraint violation.
+	ErrConstraint = errors.New("constraint violation")
+)
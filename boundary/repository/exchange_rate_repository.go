@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+
+	"casino/boundary/repo_model"
+)
+
+type ExchangeRateRepository interface {
+	SaveAll(rates []*repo_model.ExchangeRateModel) error
+	GetEffective(currency string, at time.Time) (*repo_model.ExchangeRateModel, error)
+	GetAll(currency *string) ([]*repo_model.ExchangeRateModel, error)
+}
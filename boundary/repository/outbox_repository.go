@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"casino/boundary/repo_model"
+)
+
+type OutboxRepository interface {
+	// GetUnsent returns up to limit unsent events in the order they were written.
+	GetUnsent(limit int) ([]*repo_model.OutboxEventModel, error)
+	MarkSent(ids []uint64, sentAt time.Time) error
+	// DeleteSentBefore removes events sent before the given time and returns
+	// how many there were. Unsent events are never deleted.
+	DeleteSentBefore(before time.Time) (int64, error)
+	// TryLockRelay takes the lock that lets a single relay publish events at
+	// a time. It returns nil and no error while another relay holds it.
+	TryLockRelay(ctx context.Context) (RelayLock, error)
+}
+
+// RelayLock is held until Release. Check fails once the lock has been lost,
+// for example because the database connection holding it broke.
+type RelayLock interface {
+	Check(ctx context.Context) error
+	Release()
+}
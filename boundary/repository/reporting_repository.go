@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"casino/boundary/repo_model"
+)
+
+type ReportingRepository interface {
+	// GetGGR aggregates bets and wins in [from, to). An empty groupBy returns a
+	// single row covering the whole range.
+	GetGGR(from, to time.Time, groupBy string) ([]*repo_model.GGRRowModel, error)
+	GetDailyGGR(from, to time.Time, groupBy string) ([]*repo_model.GGRRowModel, error)
+	RebuildDailyAggregates(from, to time.Time) (int64, error)
+	GetUserStats(userID string, since *time.Time) (*repo_model.UserStatsModel, error)
+}
@@ -1,12 +1,29 @@
 package repository
 
 import (
+	"context"
+
 	"casino/boundary/repo_model"
 )
 
 type TransactionRepository interface {
 	Save(transaction *repo_model.TransactionModel) error
+	// SaveChecked saves like Save once check accepts the user's current
+	// totals and the transactions already linked to the same original, if
+	// the transaction has one. Both are read under a lock on the user, so two
+	// checked saves for the same user never see the same state. An error from
+	// check aborts the save and is returned unchanged.
+	SaveChecked(transaction *repo_model.TransactionModel, check func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error) error
+	SaveBatch(transactions []*repo_model.TransactionModel) error
+	// GetByID returns ErrNotFound when no transaction has the id.
 	GetByID(id string) (*repo_model.TransactionModel, error)
-	GetByUserID(userID string, transactionType *string) ([]*repo_model.TransactionModel, error)
-	GetAll(transactionType *string) ([]*repo_model.TransactionModel, error)
+	GetByUserID(userID string, transactionType, currency *string) ([]*repo_model.TransactionModel, error)
+	GetAll(transactionType, currency *string) ([]*repo_model.TransactionModel, error)
+	GetByRoundID(roundID string) ([]*repo_model.TransactionModel, error)
+	GetByOriginalID(originalID string) ([]*repo_model.TransactionModel, error)
+	GetTotals(userID string) ([]*repo_model.TransactionTotalModel, error)
+	// Stream calls fn for every matching transaction, oldest first, reading
+	// from a cursor instead of loading the result set. Returning an error from
+	// fn stops the iteration.
+	Stream(ctx context.Context, userID, transactionType, currency *string, fn func(*repo_model.TransactionModel) error) error
 }
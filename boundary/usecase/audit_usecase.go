@@ -0,0 +1,12 @@
+package usecase
+
+import (
+	"time"
+
+	"casino/boundary/dto"
+)
+
+type AuditUseCase interface {
+	Record(dto *dto.CreateAuditRecordDTO) error
+	VerifyChain(from, to time.Time) (*dto.AuditVerificationDTO, error)
+}
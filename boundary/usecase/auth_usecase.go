@@ -0,0 +1,10 @@
+package usecase
+
+import "time"
+
+type AuthUseCase interface {
+	// IssueUserToken returns a token that identifies userID until ttl has passed.
+	IssueUserToken(userID string, ttl time.Duration) string
+	// AuthenticateUser returns the user a token was issued for.
+	AuthenticateUser(token string) (string, error)
+}
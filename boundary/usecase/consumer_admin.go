@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+
+	"casino/boundary/dto"
+)
+
+// ConsumerAdmin lets operators inspect the message consumer and stop it from
+// taking new messages, for example during database maintenance.
+type ConsumerAdmin interface {
+	Status(ctx context.Context) (*dto.ConsumerStatusDTO, error)
+	// Pause returns once the messages being processed are done, or with the
+	// context's error if that takes too long. The consumer stays paused
+	// either way.
+	Pause(ctx context.Context) error
+	Resume()
+}
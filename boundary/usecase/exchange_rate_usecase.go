@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"time"
+
+	"casino/boundary/dto"
+)
+
+type ExchangeRateUseCase interface {
+	AddRates(rates []*dto.ExchangeRateDTO) error
+	GetRates(currency *string) ([]*dto.ExchangeRateDTO, error)
+	CurrencyConverter
+}
+
+// CurrencyConverter converts minor-unit amounts into the reporting currency
+// using the rate that was effective at the given time.
+type CurrencyConverter interface {
+	Convert(amount uint, currency string, at time.Time) (*dto.ConversionDTO, error)
+}
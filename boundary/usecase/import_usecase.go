@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"casino/boundary/dto"
+)
+
+type ImportUseCase interface {
+	// ImportBatch validates rows in order with the rules of ProcessTransaction
+	// and stores the valid ones together. Rows that break a rule are reported
+	// instead of failing the batch; any other error stores nothing. Once the
+	// batch is stored, every row gets an audit record with its outcome.
+	ImportBatch(rows []*dto.ImportRowDTO) (*dto.ImportBatchResultDTO, error)
+}
@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"time"
+
+	"casino/boundary/dto"
+)
+
+type ReportUseCase interface {
+	GetGGR(filter *dto.GGRFilterDTO) (*dto.GGRReportDTO, error)
+	GetUserStats(userID string) (*dto.UserStatsDTO, error)
+	RebuildDailyAggregates(from, to time.Time) (int64, error)
+}
@@ -0,0 +1,15 @@
+package usecase
+
+import "casino/boundary/dto"
+
+// TransactionPublisher is told about every transaction once it has been saved.
+type TransactionPublisher interface {
+	Publish(transaction *dto.TransactionDTO)
+}
+
+type TransactionFeed interface {
+	// Subscribe returns the matching transactions published after lastEventID
+	// followed by live ones. The channel is closed when the subscriber falls
+	// too far behind, and the returned function ends the subscription.
+	Subscribe(filter *dto.TransactionStreamFilterDTO, lastEventID uint64) (<-chan *dto.TransactionEventDTO, func())
+}
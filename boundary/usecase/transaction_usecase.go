@@ -1,11 +1,19 @@
 package usecase
 
 import (
+	"context"
+
 	"casino/boundary/dto"
 )
 
 type TransactionUseCase interface {
 	ProcessTransaction(dto *dto.CreateTransactionDTO) error
+	GetTransaction(id string) (*dto.TransactionDTO, error)
 	GetUserTransactions(userID string, filter *dto.TransactionFilterDTO) ([]*dto.TransactionDTO, error)
+	GetReversals(originalID string) ([]*dto.TransactionDTO, error)
 	GetAllTransactions(filter *dto.TransactionFilterDTO) ([]*dto.TransactionDTO, error)
+	GetRound(roundID string) (*dto.RoundDTO, error)
+	CancelTransaction(dto *dto.CancelTransactionDTO) (*dto.TransactionDTO, error)
+	GetBalances(userID string) ([]*dto.BalanceDTO, error)
+	ExportTransactions(ctx context.Context, userID *string, filter *dto.TransactionFilterDTO, fn func(*dto.TransactionDTO) error) error
 }
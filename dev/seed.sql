@@ -1,17 +1,15 @@
-CREATE TABLE IF NOT EXISTS transactions (
-    id UUID PRIMARY KEY,
-    user_id UUID NOT NULL,
-    transaction_type VARCHAR(10) NOT NULL CHECK (transaction_type IN ('bet', 'win')),
-    amount INTEGER NOT NULL CHECK (amount > 0),
-    timestamp TIMESTAMP NOT NULL
-);
-
-INSERT INTO transactions (id, user_id, transaction_type, amount, timestamp)
+-- Demo transactions for local development. Apply after `migrate up`, never
+-- against a real database, then run `backfill-aggregates` so the reports
+-- include them.
+INSERT INTO transactions (id, user_id, transaction_type, amount, currency, reporting_amount, exchange_rate, timestamp)
 VALUES (
         '550e8400-e29b-41d4-a716-446655440001',
         '550e8400-e29b-41d4-a716-446655440010',
         'bet',
         1000,
+        'EUR',
+        1000,
+        1,
         '2024-01-15 14:30:00'
     ),
     (
@@ -19,6 +17,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440010',
         'win',
         2500,
+        'EUR',
+        2500,
+        1,
         '2024-01-15 14:35:00'
     ),
     (
@@ -26,6 +27,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440011',
         'bet',
         500,
+        'EUR',
+        500,
+        1,
         '2024-01-15 15:00:00'
     ),
     (
@@ -33,6 +37,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440011',
         'win',
         1200,
+        'EUR',
+        1200,
+        1,
         '2024-01-15 15:05:00'
     ),
     (
@@ -40,6 +47,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440012',
         'bet',
         2000,
+        'EUR',
+        2000,
+        1,
         '2024-01-15 16:00:00'
     ),
     (
@@ -47,6 +57,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440012',
         'win',
         5000,
+        'EUR',
+        5000,
+        1,
         '2024-01-15 16:10:00'
     ),
     (
@@ -54,6 +67,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440013',
         'bet',
         750,
+        'EUR',
+        750,
+        1,
         '2024-01-15 17:00:00'
     ),
     (
@@ -61,6 +77,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440013',
         'win',
         1800,
+        'EUR',
+        1800,
+        1,
         '2024-01-15 17:15:00'
     ),
     (
@@ -68,6 +87,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440014',
         'bet',
         1500,
+        'EUR',
+        1500,
+        1,
         '2024-01-15 18:00:00'
     ),
     (
@@ -75,5 +97,9 @@ VALUES (
         '550e8400-e29b-41d4-a716-446655440014',
         'win',
         3000,
+        'EUR',
+        3000,
+        1,
         '2024-01-15 18:30:00'
-    );
\ No newline at end of file
+    )
+ON CONFLICT (id) DO NOTHING;
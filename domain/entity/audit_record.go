@@ -0,0 +1,59 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
+
+type AuditOutcome string
+
+const (
+	AuditOutcomeAccepted  AuditOutcome = "accepted"
+	AuditOutcomeDuplicate AuditOutcome = "duplicate"
+	AuditOutcomeInvalid   AuditOutcome = "invalid"
+	AuditOutcomeFailed    AuditOutcome = "failed"
+)
+
+type AuditRecord struct {
+	ID          string
+	Timestamp   time.Time
+	Actor       string
+	Action      string
+	Source      string
+	PayloadHash string
+	Outcome     AuditOutcome
+	PrevHash    string
+	Hash        string
+}
+
+// ComputeHash returns the chain hash of the record. It covers every field
+// except Hash itself, so changing any stored value or the link to the previous
+// row breaks the chain. Each field is prefixed with its length, so text cannot
+// be moved from one field into the next without changing the hash.
+func (r *AuditRecord) ComputeHash() string {
+	fields := []string{
+		r.ID,
+		r.Timestamp.UTC().Format(time.RFC3339Nano),
+		r.Actor,
+		r.Action,
+		r.Source,
+		r.PayloadHash,
+		string(r.Outcome),
+		r.PrevHash,
+	}
+	hash := sha256.New()
+	for _, field := range fields {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(field)))
+		hash.Write(length[:])
+		hash.Write([]byte(field))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func HashPayload(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuditRecord_ComputeHash(t *testing.T) {
+	record := &AuditRecord{
+		ID:          "550e8400-e29b-41d4-a716-446655440000",
+		Timestamp:   time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+		Actor:       "dba",
+		Action:      "admin.transaction.cancel",
+		Source:      "http:req-1",
+		PayloadHash: HashPayload([]byte(`{"id":"1"}`)),
+		Outcome:     AuditOutcomeAccepted,
+	}
+
+	hash := record.ComputeHash()
+	if len(hash) != 64 {
+		t.Fatalf("Expected a hex SHA-256, got %q", hash)
+	}
+	if record.ComputeHash() != hash {
+		t.Error("Expected the hash to be deterministic")
+	}
+
+	testCases := []struct {
+		name   string
+		change func(r *AuditRecord)
+	}{
+		{"Actor", func(r *AuditRecord) { r.Actor = "admin" }},
+		{"Timestamp", func(r *AuditRecord) { r.Timestamp = r.Timestamp.Add(time.Microsecond) }},
+		{"Outcome", func(r *AuditRecord) { r.Outcome = AuditOutcomeFailed }},
+		{"Previous Hash", func(r *AuditRecord) { r.PrevHash = hash }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changed := *record
+			tc.change(&changed)
+			if changed.ComputeHash() == hash {
+				t.Error("Expected the hash to change")
+			}
+		})
+	}
+}
+
+func TestAuditRecord_ComputeHash_FieldBoundaries(t *testing.T) {
+	first := &AuditRecord{Actor: "a|b", Action: "c"}
+	second := &AuditRecord{Actor: "a", Action: "b|c"}
+
+	if first.ComputeHash() == second.ComputeHash() {
+		t.Error("Expected records that only differ in field boundaries to hash differently")
+	}
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Currency string
+
+const (
+	CurrencyEUR  Currency = "EUR"
+	CurrencyUSD  Currency = "USD"
+	CurrencyGBP  Currency = "GBP"
+	CurrencyJPY  Currency = "JPY"
+	CurrencyBTC  Currency = "BTC"
+	CurrencyETH  Currency = "ETH"
+	CurrencyUSDT Currency = "USDT"
+)
+
+// DefaultCurrency is assumed for transactions that carry no currency, which
+// covers everything recorded before multi-currency support.
+const DefaultCurrency = CurrencyEUR
+
+// currencyExponents holds the number of minor-unit digits per currency.
+// Amounts are always stored in minor units, so 1234 EUR means 12.34 EUR and
+// 1 BTC means one satoshi. ETH is counted in gwei rather than wei: amounts
+// and their sums are int64, which in wei would end at about 9.22 ETH. Kafka
+// messages before envelope version 3 carry wei and are converted on reading.
+var currencyExponents = map[Currency]int{
+	CurrencyEUR:  2,
+	CurrencyUSD:  2,
+	CurrencyGBP:  2,
+	CurrencyJPY:  0,
+	CurrencyBTC:  8,
+	CurrencyETH:  9,
+	CurrencyUSDT: 6,
+}
+
+func (c Currency) IsValid() bool {
+	_, ok := currencyExponents[c]
+	return ok
+}
+
+func (c Currency) Exponent() int {
+	return currencyExponents[c]
+}
+
+// FormatAmount renders a minor-unit amount as a decimal string in the
+// currency's major unit, e.g. -1234 EUR becomes "-12.34".
+func (c Currency) FormatAmount(amount int64) string {
+	exponent := c.Exponent()
+	sign := ""
+	magnitude := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = uint64(-amount)
+	}
+
+	digits := fmt.Sprintf("%d", magnitude)
+	if exponent == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
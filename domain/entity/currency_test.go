@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCurrency_IsValid(t *testing.T) {
+	for _, currency := range []Currency{CurrencyEUR, CurrencyUSD, CurrencyBTC, CurrencyETH} {
+		if !currency.IsValid() {
+			t.Errorf("Expected %s to be valid", currency)
+		}
+	}
+
+	for _, currency := range []Currency{"", "eur", "XYZ"} {
+		if currency.IsValid() {
+			t.Errorf("Expected %q to be invalid", currency)
+		}
+	}
+}
+
+func TestCurrency_FormatAmount(t *testing.T) {
+	testCases := []struct {
+		currency Currency
+		amount   int64
+		expected string
+	}{
+		{CurrencyEUR, 1234, "12.34"},
+		{CurrencyEUR, 5, "0.05"},
+		{CurrencyEUR, 0, "0.00"},
+		{CurrencyEUR, -1234, "-12.34"},
+		{CurrencyJPY, 1500, "1500"},
+		{CurrencyBTC, 1, "0.00000001"},
+		{CurrencyBTC, 150000000, "1.50000000"},
+		{CurrencyETH, 1, "0.000000001"},
+		{CurrencyETH, 1_250_500_000_000, "1250.500000000"},
+		{CurrencyETH, math.MaxInt64, "9223372036.854775807"},
+		{CurrencyUSD, math.MinInt64, "-92233720368547758.08"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.currency.FormatAmount(tc.amount); got != tc.expected {
+			t.Errorf("FormatAmount(%s, %d) = %s, expected %s", tc.currency, tc.amount, got, tc.expected)
+		}
+	}
+}
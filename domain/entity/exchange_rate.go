@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// ReportingCurrency is the currency all financial reports are expressed in.
+const ReportingCurrency = CurrencyEUR
+
+// ExchangeRate says how many major units of the reporting currency one major
+// unit of Currency was worth from EffectiveAt on. Rate is kept as a decimal
+// string so that it round-trips without floating point error.
+type ExchangeRate struct {
+	Currency    Currency
+	Rate        string
+	EffectiveAt time.Time
+}
+
+func ParseRate(rate string) (*big.Rat, error) {
+	value, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return nil, fmt.Errorf("rate %q is not a decimal number", rate)
+	}
+	if value.Sign() <= 0 {
+		return nil, fmt.Errorf("rate must be positive")
+	}
+	return value, nil
+}
+
+// ConvertAmount converts a minor-unit amount of currency into minor units of
+// the reporting currency, rounding half away from zero.
+func ConvertAmount(amount uint, currency Currency, rate string) (int64, error) {
+	value, err := ParseRate(rate)
+	if err != nil {
+		return 0, err
+	}
+
+	result := new(big.Rat).SetInt(new(big.Int).SetUint64(uint64(amount)))
+	result.Mul(result, value)
+
+	shift := ReportingCurrency.Exponent() - currency.Exponent()
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift >= 0 {
+		result.Mul(result, scale)
+	} else {
+		result.Quo(result, scale)
+	}
+
+	// Adding one half before truncating rounds half up for non-negative values.
+	result.Add(result, big.NewRat(1, 2))
+	rounded := new(big.Int).Quo(result.Num(), result.Denom())
+	if !rounded.IsInt64() {
+		return 0, fmt.Errorf("converted amount is out of range")
+	}
+	return rounded.Int64(), nil
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseRate(t *testing.T) {
+	for _, rate := range []string{"1", "0.92", "64250.123456789012345678"} {
+		if _, err := ParseRate(rate); err != nil {
+			t.Errorf("Expected %s to parse, got %v", rate, err)
+		}
+	}
+
+	for _, rate := range []string{"", "abc", "0", "-1.5", "1,5"} {
+		if _, err := ParseRate(rate); err == nil {
+			t.Errorf("Expected %q to be rejected", rate)
+		}
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	testCases := []struct {
+		name     string
+		amount   uint
+		currency Currency
+		rate     string
+		expected int64
+	}{
+		{"Identity", 1234, CurrencyEUR, "1", 1234},
+		{"Same Exponent", 10000, CurrencyUSD, "0.92", 9200},
+		{"Round Half Up", 1, CurrencyUSD, "0.5", 1},
+		{"Round Down", 1, CurrencyUSD, "0.49", 0},
+		{"Zero Exponent", 1000, CurrencyJPY, "0.0062", 620},
+		{"Satoshis", 150000000, CurrencyBTC, "60000.50", 9000075},
+		{"Gwei", 1_000_000_000, CurrencyETH, "3000", 300000},
+		{"Large ETH Amount", 1_250_500_000_000, CurrencyETH, "3000.25", 375181263},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ConvertAmount(tc.amount, tc.currency, tc.rate)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestConvertAmount_Errors(t *testing.T) {
+	if _, err := ConvertAmount(100, CurrencyUSD, "bad"); err == nil {
+		t.Error("Expected invalid rate to be rejected")
+	}
+
+	if _, err := ConvertAmount(math.MaxInt64, CurrencyJPY, "1000"); err == nil {
+		t.Error("Expected overflow to be rejected")
+	}
+}
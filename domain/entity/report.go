@@ -0,0 +1,17 @@
+package entity
+
+type ReportGroupBy string
+
+const (
+	ReportGroupByDay  ReportGroupBy = "day"
+	ReportGroupByUser ReportGroupBy = "user"
+	ReportGroupByGame ReportGroupBy = "game"
+)
+
+func (g ReportGroupBy) IsValid() bool {
+	switch g {
+	case ReportGroupByDay, ReportGroupByUser, ReportGroupByGame:
+		return true
+	}
+	return false
+}
@@ -7,16 +7,55 @@ import (
 type TransactionType string
 
 const (
-	TransactionTypeBet TransactionType = "bet"
-	TransactionTypeWin TransactionType = "win"
+	TransactionTypeBet         TransactionType = "bet"
+	TransactionTypeWin         TransactionType = "win"
+	TransactionTypeDeposit     TransactionType = "deposit"
+	TransactionTypeWithdrawal  TransactionType = "withdrawal"
+	TransactionTypeRefund      TransactionType = "refund"
+	TransactionTypeRollback    TransactionType = "rollback"
+	TransactionTypeBonusCredit TransactionType = "bonus_credit"
 )
 
-type Transaction struct {
-	ID              string
-	UserID          string
-	TransactionType TransactionType
-	Amount          uint
-	Timestamp       time.Time
+// balanceSigns tells whether a transaction type credits (+1) or debits (-1)
+// the player balance. Rollbacks are absent because they reverse whatever
+// their original transaction did.
+var balanceSigns = map[TransactionType]int64{
+	TransactionTypeBet:         -1,
+	TransactionTypeWin:         1,
+	TransactionTypeDeposit:     1,
+	TransactionTypeWithdrawal:  -1,
+	TransactionTypeRefund:      1,
+	TransactionTypeBonusCredit: 1,
+}
+
+func (t TransactionType) IsValid() bool {
+	_, ok := balanceSigns[t]
+	return ok || t == TransactionTypeRollback
+}
+
+func (t TransactionType) RequiresOriginal() bool {
+	return t == TransactionTypeRollback || t == TransactionTypeRefund
 }
 
+func BalanceEffect(transactionType, originalType TransactionType, amount int64) int64 {
+	if transactionType == TransactionTypeRollback {
+		return -balanceSigns[originalType] * amount
+	}
+	return balanceSigns[transactionType] * amount
+}
 
+type Transaction struct {
+	ID                    string
+	UserID                string
+	TransactionType       TransactionType
+	Amount                uint
+	Currency              Currency
+	Timestamp             time.Time
+	RoundID               string
+	GameID                string
+	ProviderID            string
+	OriginalTransactionID string
+	Reason                string
+	ReportingAmount       *int64
+	ExchangeRate          string
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/domain/entity"
+	"casino/utils"
+)
+
+type AuditUseCaseImpl struct {
+	auditRepo repository.AuditRepository
+	now       func() time.Time
+}
+
+func NewAuditUseCaseImpl(auditRepo repository.AuditRepository) *AuditUseCaseImpl {
+	return &AuditUseCaseImpl{
+		auditRepo: auditRepo,
+		now:       time.Now,
+	}
+}
+
+// Record appends a record to the chain. The record is stamped inside Append,
+// under its lock, and never earlier than the record before it, so that
+// timestamps follow the sequence and VerifyChain's time range selects a
+// contiguous stretch of the chain.
+func (uc *AuditUseCaseImpl) Record(dto *dto.CreateAuditRecordDTO) error {
+	record := &entity.AuditRecord{
+		ID:          utils.GenerateUUID(),
+		Actor:       dto.Actor,
+		Action:      dto.Action,
+		Source:      dto.Source,
+		PayloadHash: entity.HashPayload(dto.Payload),
+		Outcome:     auditOutcome(dto.Err),
+	}
+
+	err := uc.auditRepo.Append(func(last *repo_model.AuditRecordModel) (*repo_model.AuditRecordModel, error) {
+		record.Timestamp = uc.now().UTC().Truncate(time.Microsecond)
+		record.PrevHash = ""
+		if last != nil {
+			if record.Timestamp.Before(last.Timestamp) {
+				record.Timestamp = last.Timestamp.UTC()
+			}
+			record.PrevHash = last.Hash
+		}
+		record.Hash = record.ComputeHash()
+
+		model := &repo_model.AuditRecordModel{}
+		model.FromEntity(record)
+		return model, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to append audit record: %w", err)
+	}
+	return nil
+}
+
+func (uc *AuditUseCaseImpl) VerifyChain(from, to time.Time) (*dto.AuditVerificationDTO, error) {
+	result := &dto.AuditVerificationDTO{From: from, To: to, Valid: true}
+
+	models, err := uc.auditRepo.GetRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(models) == 0 {
+		return result, nil
+	}
+
+	previous, err := uc.auditRepo.GetBefore(models[0].Sequence)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedPrevHash := ""
+	if previous != nil {
+		expectedPrevHash = previous.Hash
+	}
+
+	for _, model := range models {
+		record := model.ToEntity()
+		result.Checked++
+
+		if record.PrevHash != expectedPrevHash {
+			result.Valid = false
+			result.BrokenAtID = record.ID
+			result.BrokenReason = "previous hash does not match the preceding record"
+			return result, nil
+		}
+		if record.ComputeHash() != record.Hash {
+			result.Valid = false
+			result.BrokenAtID = record.ID
+			result.BrokenReason = "record hash does not match its contents"
+			return result, nil
+		}
+		expectedPrevHash = record.Hash
+	}
+
+	return result, nil
+}
+
+func auditOutcome(err error) entity.AuditOutcome {
+	switch {
+	case err == nil:
+		return entity.AuditOutcomeAccepted
+	case utils.IsTransactionAlreadyExists(err), utils.IsTransactionAlreadyCancelled(err):
+		return entity.AuditOutcomeDuplicate
+	case utils.IsTransactionValidation(err), utils.IsTransactionNotFound(err), utils.IsExchangeRateValidation(err):
+		return entity.AuditOutcomeInvalid
+	default:
+		return entity.AuditOutcomeFailed
+	}
+}
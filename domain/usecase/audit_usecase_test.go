@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/domain/entity"
+	"casino/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+	stored []*repo_model.AuditRecordModel
+}
+
+func (m *MockAuditRepository) Append(build func(last *repo_model.AuditRecordModel) (*repo_model.AuditRecordModel, error)) error {
+	args := m.Called()
+	if args.Error(0) != nil {
+		return args.Error(0)
+	}
+
+	var last *repo_model.AuditRecordModel
+	if len(m.stored) > 0 {
+		last = m.stored[len(m.stored)-1]
+	}
+	record, err := build(last)
+	if err != nil {
+		return err
+	}
+	record.Sequence = uint64(len(m.stored) + 1)
+	m.stored = append(m.stored, record)
+	return nil
+}
+
+func (m *MockAuditRepository) GetRange(from, to time.Time) ([]*repo_model.AuditRecordModel, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.AuditRecordModel), args.Error(1)
+}
+
+func (m *MockAuditRepository) GetBefore(sequence uint64) (*repo_model.AuditRecordModel, error) {
+	args := m.Called(sequence)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repo_model.AuditRecordModel), args.Error(1)
+}
+
+func recordAuditEntries(t *testing.T, useCase *AuditUseCaseImpl, count int) {
+	for i := 0; i < count; i++ {
+		err := useCase.Record(&dto.CreateAuditRecordDTO{
+			Actor:   "kafka-consumer",
+			Action:  "transaction.process",
+			Source:  "kafka:casino-transactions-stream/0/1",
+			Payload: []byte(`{"id":"1"}`),
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func TestAuditUseCase_Record_ChainsHashes(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	mockRepo.On("Append").Return(nil).Times(3)
+	recordAuditEntries(t, useCase, 3)
+
+	assert.Len(t, mockRepo.stored, 3)
+	assert.Equal(t, "", mockRepo.stored[0].PrevHash)
+	assert.Equal(t, mockRepo.stored[0].Hash, mockRepo.stored[1].PrevHash)
+	assert.Equal(t, mockRepo.stored[1].Hash, mockRepo.stored[2].PrevHash)
+
+	for _, model := range mockRepo.stored {
+		assert.Len(t, model.PayloadHash, 64)
+		assert.Equal(t, model.ToEntity().ComputeHash(), model.Hash)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditUseCase_Record_StampsUnderLock(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	later := time.Date(2026, 5, 1, 12, 0, 1, 0, time.UTC)
+	earlier := later.Add(-time.Second)
+	times := []time.Time{later, earlier}
+	stamped := 0
+	useCase.now = func() time.Time {
+		stamped++
+		return times[stamped-1]
+	}
+
+	mockRepo.On("Append").Return(nil).Twice()
+	recordAuditEntries(t, useCase, 2)
+
+	assert.Equal(t, 2, stamped)
+	assert.True(t, mockRepo.stored[0].Timestamp.Equal(later))
+	assert.True(t, mockRepo.stored[1].Timestamp.Equal(later), "a record must not be stamped before the one it follows")
+	assert.Equal(t, mockRepo.stored[1].ToEntity().ComputeHash(), mockRepo.stored[1].Hash)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditUseCase_Record_RepositoryError(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	mockRepo.On("Append").Return(assert.AnError).Once()
+
+	err := useCase.Record(&dto.CreateAuditRecordDTO{Actor: "admin", Action: "test"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to append audit record")
+}
+
+func TestAuditUseCase_VerifyChain_Valid(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	mockRepo.On("Append").Return(nil).Times(4)
+	recordAuditEntries(t, useCase, 4)
+
+	from, to := time.Time{}, time.Now()
+	mockRepo.On("GetRange", from, to).Return(mockRepo.stored[1:], nil).Once()
+	mockRepo.On("GetBefore", uint64(2)).Return(mockRepo.stored[0], nil).Once()
+
+	result, err := useCase.VerifyChain(from, to)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 3, result.Checked)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditUseCase_VerifyChain_TamperedRecord(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	mockRepo.On("Append").Return(nil).Times(3)
+	recordAuditEntries(t, useCase, 3)
+
+	mockRepo.stored[1].Outcome = "duplicate"
+
+	from, to := time.Time{}, time.Now()
+	mockRepo.On("GetRange", from, to).Return(mockRepo.stored, nil).Once()
+	mockRepo.On("GetBefore", uint64(1)).Return(nil, nil).Once()
+
+	result, err := useCase.VerifyChain(from, to)
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, mockRepo.stored[1].ID, result.BrokenAtID)
+	assert.Equal(t, 2, result.Checked)
+}
+
+func TestAuditUseCase_VerifyChain_DeletedRecord(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	mockRepo.On("Append").Return(nil).Times(3)
+	recordAuditEntries(t, useCase, 3)
+
+	remaining := []*repo_model.AuditRecordModel{mockRepo.stored[0], mockRepo.stored[2]}
+
+	from, to := time.Time{}, time.Now()
+	mockRepo.On("GetRange", from, to).Return(remaining, nil).Once()
+	mockRepo.On("GetBefore", uint64(1)).Return(nil, nil).Once()
+
+	result, err := useCase.VerifyChain(from, to)
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, mockRepo.stored[2].ID, result.BrokenAtID)
+}
+
+func TestAuditUseCase_VerifyChain_Empty(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	from, to := time.Time{}, time.Now()
+	mockRepo.On("GetRange", from, to).Return([]*repo_model.AuditRecordModel{}, nil).Once()
+
+	result, err := useCase.VerifyChain(from, to)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, 0, result.Checked)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditUseCase_VerifyChain_RepositoryError(t *testing.T) {
+	mockRepo := &MockAuditRepository{}
+	useCase := NewAuditUseCaseImpl(mockRepo)
+
+	from, to := time.Time{}, time.Now()
+	mockRepo.On("GetRange", from, to).Return(nil, assert.AnError).Once()
+
+	result, err := useCase.VerifyChain(from, to)
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestAuditUseCase_Record_Outcomes(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected entity.AuditOutcome
+	}{
+		{"Accepted", nil, entity.AuditOutcomeAccepted},
+		{"Duplicate", &utils.TransactionAlreadyExistsError{TransactionID: "tx"}, entity.AuditOutcomeDuplicate},
+		{"Already Cancelled", &utils.TransactionAlreadyCancelledError{TransactionID: "tx"}, entity.AuditOutcomeDuplicate},
+		{"Validation Failure", &utils.TransactionValidationError{TransactionID: "tx", Reason: "bad"}, entity.AuditOutcomeInvalid},
+		{"Unknown Original", &utils.TransactionNotFoundError{TransactionID: "tx"}, entity.AuditOutcomeInvalid},
+		{"Invalid Exchange Rate", &utils.ExchangeRateValidationError{Currency: "XYZ", Reason: "unknown currency"}, entity.AuditOutcomeInvalid},
+		{"Other Error", assert.AnError, entity.AuditOutcomeFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockAuditRepository{}
+			useCase := NewAuditUseCaseImpl(mockRepo)
+			mockRepo.On("Append").Return(nil).Once()
+
+			err := useCase.Record(&dto.CreateAuditRecordDTO{Actor: "admin", Action: "test", Err: tc.err})
+			assert.NoError(t, err)
+			assert.Equal(t, string(tc.expected), mockRepo.stored[0].Outcome)
+		})
+	}
+}
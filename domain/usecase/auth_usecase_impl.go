@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"casino/utils"
+)
+
+// AuthUseCaseImpl issues and checks user tokens of the form
+// <user id>.<expiry unix seconds>.<signature>, where the signature is an
+// HMAC-SHA256 of the first two parts. Services that share the secret can
+// issue tokens for player clients without a call to this one.
+type AuthUseCaseImpl struct {
+	secret []byte
+	now    func() time.Time
+}
+
+func NewAuthUseCaseImpl(secret []byte) *AuthUseCaseImpl {
+	return &AuthUseCaseImpl{
+		secret: secret,
+		now:    time.Now,
+	}
+}
+
+func (uc *AuthUseCaseImpl) IssueUserToken(userID string, ttl time.Duration) string {
+	payload := userID + "." + strconv.FormatInt(uc.now().Add(ttl).Unix(), 10)
+	return payload + "." + uc.sign(payload)
+}
+
+func (uc *AuthUseCaseImpl) AuthenticateUser(token string) (string, error) {
+	separator := strings.LastIndex(token, ".")
+	if separator < 0 {
+		return "", &utils.UnauthorizedError{Reason: "malformed token"}
+	}
+	payload, signature := token[:separator], token[separator+1:]
+
+	if !hmac.Equal([]byte(signature), []byte(uc.sign(payload))) {
+		return "", &utils.UnauthorizedError{Reason: "invalid token signature"}
+	}
+
+	separator = strings.LastIndex(payload, ".")
+	if separator <= 0 {
+		return "", &utils.UnauthorizedError{Reason: "malformed token"}
+	}
+	userID := payload[:separator]
+
+	expiresAt, err := strconv.ParseInt(payload[separator+1:], 10, 64)
+	if err != nil {
+		return "", &utils.UnauthorizedError{Reason: "malformed token"}
+	}
+
+	if !uc.now().Before(time.Unix(expiresAt, 0)) {
+		return "", &utils.UnauthorizedError{Reason: "token expired"}
+	}
+
+	return userID, nil
+}
+
+func (uc *AuthUseCaseImpl) sign(payload string) string {
+	mac := hmac.New(sha256.New, uc.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
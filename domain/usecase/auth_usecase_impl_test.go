@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"casino/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthUseCase_IssueAndAuthenticate(t *testing.T) {
+	useCase := NewAuthUseCaseImpl([]byte("secret"))
+
+	token := useCase.IssueUserToken("550e8400-e29b-41d4-a716-446655440010", time.Hour)
+
+	userID, err := useCase.AuthenticateUser(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440010", userID)
+}
+
+func TestAuthUseCase_AuthenticateUser_Rejected(t *testing.T) {
+	useCase := NewAuthUseCaseImpl([]byte("secret"))
+	token := useCase.IssueUserToken("user-1", time.Hour)
+
+	expired := NewAuthUseCaseImpl([]byte("secret"))
+	expired.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	parts := strings.Split(token, ".")
+	forged := "user-2." + parts[1] + "." + parts[2]
+
+	testCases := []struct {
+		name    string
+		useCase *AuthUseCaseImpl
+		token   string
+	}{
+		{"Other Secret", NewAuthUseCaseImpl([]byte("other")), token},
+		{"Forged User", useCase, forged},
+		{"Expired", expired, token},
+		{"Malformed", useCase, "not-a-token"},
+		{"Empty", useCase, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.useCase.AuthenticateUser(tc.token)
+			assert.True(t, utils.IsUnauthorized(err), "expected an unauthorized error, got %v", err)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/domain/entity"
+	"casino/utils"
+)
+
+type ExchangeRateUseCaseImpl struct {
+	rateRepo repository.ExchangeRateRepository
+}
+
+func NewExchangeRateUseCaseImpl(rateRepo repository.ExchangeRateRepository) *ExchangeRateUseCaseImpl {
+	return &ExchangeRateUseCaseImpl{
+		rateRepo: rateRepo,
+	}
+}
+
+// AddRates validates and stores a batch of rates. Nothing is stored unless
+// every rate in the batch is valid.
+func (uc *ExchangeRateUseCaseImpl) AddRates(rates []*dto.ExchangeRateDTO) error {
+	if len(rates) == 0 {
+		return &utils.ExchangeRateValidationError{Reason: "no rates given"}
+	}
+
+	models := make([]*repo_model.ExchangeRateModel, len(rates))
+	for i, rate := range rates {
+		rate.Rate = strings.TrimSpace(rate.Rate)
+		if err := validateExchangeRate(rate); err != nil {
+			return err
+		}
+
+		models[i] = &repo_model.ExchangeRateModel{}
+		models[i].FromEntity(rate.ToEntity())
+		models[i].EffectiveAt = rate.EffectiveAt.UTC()
+	}
+
+	return uc.rateRepo.SaveAll(models)
+}
+
+func (uc *ExchangeRateUseCaseImpl) GetRates(currency *string) ([]*dto.ExchangeRateDTO, error) {
+	models, err := uc.rateRepo.GetAll(currency)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.ExchangeRateDTO, len(models))
+	for i, model := range models {
+		dtos[i] = &dto.ExchangeRateDTO{}
+		dtos[i].FromEntity(model.ToEntity())
+	}
+
+	return dtos, nil
+}
+
+func (uc *ExchangeRateUseCaseImpl) Convert(amount uint, currency string, at time.Time) (*dto.ConversionDTO, error) {
+	if entity.Currency(currency) == entity.ReportingCurrency {
+		return &dto.ConversionDTO{Amount: int64(amount), Rate: "1"}, nil
+	}
+
+	model, err := uc.rateRepo.GetEffective(currency, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if model == nil {
+		return nil, &utils.ExchangeRateNotFoundError{Currency: currency, At: at}
+	}
+
+	converted, err := entity.ConvertAmount(amount, entity.Currency(currency), model.Rate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %d %s: %w", amount, currency, err)
+	}
+
+	return &dto.ConversionDTO{Amount: converted, Rate: model.Rate}, nil
+}
+
+func validateExchangeRate(rate *dto.ExchangeRateDTO) error {
+	currency := entity.Currency(rate.Currency)
+	if !currency.IsValid() {
+		return &utils.ExchangeRateValidationError{Currency: rate.Currency, Reason: "unknown currency"}
+	}
+	if currency == entity.ReportingCurrency {
+		return &utils.ExchangeRateValidationError{Currency: rate.Currency, Reason: "the reporting currency always converts at 1"}
+	}
+	if _, err := entity.ParseRate(rate.Rate); err != nil {
+		return &utils.ExchangeRateValidationError{Currency: rate.Currency, Reason: err.Error()}
+	}
+	if rate.EffectiveAt.IsZero() {
+		return &utils.ExchangeRateValidationError{Currency: rate.Currency, Reason: "effective_at is required"}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) SaveAll(rates []*repo_model.ExchangeRateModel) error {
+	args := m.Called(rates)
+	return args.Error(0)
+}
+
+func (m *MockExchangeRateRepository) GetEffective(currency string, at time.Time) (*repo_model.ExchangeRateModel, error) {
+	args := m.Called(currency, at)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repo_model.ExchangeRateModel), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetAll(currency *string) ([]*repo_model.ExchangeRateModel, error) {
+	args := m.Called(currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.ExchangeRateModel), args.Error(1)
+}
+
+func TestExchangeRateUseCase_AddRates(t *testing.T) {
+	mockRepo := &MockExchangeRateRepository{}
+	useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+	effectiveAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+	mockRepo.On("SaveAll", mock.MatchedBy(func(models []*repo_model.ExchangeRateModel) bool {
+		return len(models) == 2 &&
+			models[0].Currency == "USD" && models[0].Rate == "0.92" &&
+			models[0].EffectiveAt.Equal(effectiveAt) && models[0].EffectiveAt.Location() == time.UTC &&
+			models[1].Currency == "BTC" && models[1].Rate == "60000.5"
+	})).Return(nil).Once()
+
+	err := useCase.AddRates([]*dto.ExchangeRateDTO{
+		{Currency: "USD", Rate: " 0.92 ", EffectiveAt: effectiveAt},
+		{Currency: "BTC", Rate: "60000.5", EffectiveAt: effectiveAt},
+	})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExchangeRateUseCase_AddRates_Invalid(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name string
+		rate *dto.ExchangeRateDTO
+	}{
+		{"Unknown Currency", &dto.ExchangeRateDTO{Currency: "XYZ", Rate: "1.5", EffectiveAt: now}},
+		{"Reporting Currency", &dto.ExchangeRateDTO{Currency: "EUR", Rate: "1", EffectiveAt: now}},
+		{"Bad Rate", &dto.ExchangeRateDTO{Currency: "USD", Rate: "abc", EffectiveAt: now}},
+		{"Zero Rate", &dto.ExchangeRateDTO{Currency: "USD", Rate: "0", EffectiveAt: now}},
+		{"Missing Effective At", &dto.ExchangeRateDTO{Currency: "USD", Rate: "0.92"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockExchangeRateRepository{}
+			useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+			valid := &dto.ExchangeRateDTO{Currency: "GBP", Rate: "1.17", EffectiveAt: now}
+			err := useCase.AddRates([]*dto.ExchangeRateDTO{valid, tc.rate})
+			assert.True(t, utils.IsExchangeRateValidation(err))
+			mockRepo.AssertNotCalled(t, "SaveAll", mock.Anything)
+		})
+	}
+}
+
+func TestExchangeRateUseCase_AddRates_Empty(t *testing.T) {
+	useCase := NewExchangeRateUseCaseImpl(&MockExchangeRateRepository{})
+
+	err := useCase.AddRates(nil)
+	assert.True(t, utils.IsExchangeRateValidation(err))
+}
+
+func TestExchangeRateUseCase_GetRates(t *testing.T) {
+	mockRepo := &MockExchangeRateRepository{}
+	useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+	currency := "USD"
+	effectiveAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetAll", &currency).Return([]*repo_model.ExchangeRateModel{
+		{ID: 1, Currency: "USD", Rate: "0.92", EffectiveAt: effectiveAt},
+	}, nil).Once()
+
+	rates, err := useCase.GetRates(&currency)
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.ExchangeRateDTO{{Currency: "USD", Rate: "0.92", EffectiveAt: effectiveAt}}, rates)
+}
+
+func TestExchangeRateUseCase_Convert(t *testing.T) {
+	mockRepo := &MockExchangeRateRepository{}
+	useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+	at := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	mockRepo.On("GetEffective", "USD", at).Return(&repo_model.ExchangeRateModel{Currency: "USD", Rate: "0.920000000000000000"}, nil).Once()
+
+	conversion, err := useCase.Convert(10050, "USD", at)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9246), conversion.Amount)
+	assert.Equal(t, "0.920000000000000000", conversion.Rate)
+}
+
+func TestExchangeRateUseCase_Convert_ReportingCurrency(t *testing.T) {
+	mockRepo := &MockExchangeRateRepository{}
+	useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+	conversion, err := useCase.Convert(1234, "EUR", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, &dto.ConversionDTO{Amount: 1234, Rate: "1"}, conversion)
+	mockRepo.AssertNotCalled(t, "GetEffective", mock.Anything, mock.Anything)
+}
+
+func TestExchangeRateUseCase_Convert_NoRate(t *testing.T) {
+	mockRepo := &MockExchangeRateRepository{}
+	useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+	mockRepo.On("GetEffective", "BTC", mock.Anything).Return(nil, nil).Once()
+
+	_, err := useCase.Convert(100, "BTC", time.Now())
+	assert.True(t, utils.IsExchangeRateNotFound(err))
+}
+
+func TestExchangeRateUseCase_Convert_RepositoryError(t *testing.T) {
+	mockRepo := &MockExchangeRateRepository{}
+	useCase := NewExchangeRateUseCaseImpl(mockRepo)
+
+	mockRepo.On("GetEffective", "BTC", mock.Anything).Return(nil, assert.AnError).Once()
+
+	_, err := useCase.Convert(100, "BTC", time.Now())
+	assert.Error(t, err)
+	assert.False(t, utils.IsExchangeRateNotFound(err))
+}
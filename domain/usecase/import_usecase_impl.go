@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/boundary/usecase"
+	"casino/utils"
+)
+
+const (
+	importAuditActor  = "import"
+	auditActionImport = "transaction.import"
+)
+
+type ImportUseCaseImpl struct {
+	transactionRepo repository.TransactionRepository
+	converter       usecase.CurrencyConverter
+	auditUseCase    usecase.AuditUseCase
+}
+
+func NewImportUseCaseImpl(transactionRepo repository.TransactionRepository, converter usecase.CurrencyConverter, auditUseCase usecase.AuditUseCase) *ImportUseCaseImpl {
+	return &ImportUseCaseImpl{
+		transactionRepo: transactionRepo,
+		converter:       converter,
+		auditUseCase:    auditUseCase,
+	}
+}
+
+// ImportBatch runs every row through the same processing as
+// ProcessTransaction, but against a view of the repository that also contains
+// the earlier rows of the batch. That way a win can settle a bet from the same
+// batch, and the batch is only written once all rows have been checked.
+func (uc *ImportUseCaseImpl) ImportBatch(rows []*dto.ImportRowDTO) (*dto.ImportBatchResultDTO, error) {
+	pending := newPendingTransactionRepository(uc.transactionRepo)
+	processor := NewTransactionUseCaseImpl(pending, uc.converter, nil)
+	result := &dto.ImportBatchResultDTO{}
+	outcomes := make([]error, len(rows))
+
+	for i, row := range rows {
+		if row.Err != nil {
+			outcomes[i] = row.Err
+			result.Invalid = append(result.Invalid, &dto.ImportRowErrorDTO{Line: row.Line, Reason: row.Err.Error()})
+			continue
+		}
+
+		_, err := processor.process(row.Transaction)
+		if err == nil {
+			continue
+		}
+		outcomes[i] = err
+
+		rowErr := &dto.ImportRowErrorDTO{Line: row.Line, TransactionID: row.Transaction.ID, Reason: err.Error()}
+		switch {
+		case utils.IsTransactionAlreadyExists(err):
+			result.Duplicates = append(result.Duplicates, rowErr)
+		case isRuleViolation(err):
+			result.Invalid = append(result.Invalid, rowErr)
+		default:
+			return nil, fmt.Errorf("failed to import line %d: %w", row.Line, err)
+		}
+	}
+
+	if len(pending.saved) > 0 {
+		if err := uc.transactionRepo.SaveBatch(pending.saved); err != nil {
+			return nil, err
+		}
+	}
+	result.Inserted = len(pending.saved)
+
+	if err := uc.audit(rows, outcomes); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// audit records the outcome of every row. The rows are already stored at this
+// point, so an audit failure is returned for the caller to stop on; running the
+// import again reports them as duplicates and audits them then.
+func (uc *ImportUseCaseImpl) audit(rows []*dto.ImportRowDTO, outcomes []error) error {
+	var errs []error
+	for i, row := range rows {
+		err := uc.auditUseCase.Record(&dto.CreateAuditRecordDTO{
+			Actor:   importAuditActor,
+			Action:  auditActionImport,
+			Source:  row.Source,
+			Payload: row.Payload,
+			Err:     outcomes[i],
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to audit line %d: %w", row.Line, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isRuleViolation reports whether err rejects a transaction on its content, as
+// opposed to an infrastructure failure.
+func isRuleViolation(err error) bool {
+	return utils.IsTransactionValidation(err) ||
+		utils.IsTransactionNotFound(err) ||
+		utils.IsTransactionAlreadyCancelled(err) ||
+		utils.IsExchangeRateValidation(err)
+}
+
+// pendingTransactionRepository answers the lookups of the transaction rules from
+// the underlying repository plus the transactions saved to it so far, which are
+// kept in memory until the caller stores them. Listing methods only see the
+// underlying repository.
+type pendingTransactionRepository struct {
+	repository.TransactionRepository
+	saved []*repo_model.TransactionModel
+	byID  map[string]*repo_model.TransactionModel
+}
+
+func newPendingTransactionRepository(transactionRepo repository.TransactionRepository) *pendingTransactionRepository {
+	return &pendingTransactionRepository{
+		TransactionRepository: transactionRepo,
+		byID:                  make(map[string]*repo_model.TransactionModel),
+	}
+}
+
+func (r *pendingTransactionRepository) Save(transaction *repo_model.TransactionModel) error {
+	r.saved = append(r.saved, transaction)
+	r.byID[transaction.ID] = transaction
+	return nil
+}
+
+func (r *pendingTransactionRepository) SaveChecked(transaction *repo_model.TransactionModel, check func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error) error {
+	totals, err := r.GetTotals(transaction.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction totals: %w", err)
+	}
+
+	var linked []*repo_model.TransactionModel
+	if transaction.OriginalTransactionID != nil {
+		if linked, err = r.GetByOriginalID(*transaction.OriginalTransactionID); err != nil {
+			return fmt.Errorf("failed to get linked transactions: %w", err)
+		}
+	}
+
+	if err := check(totals, linked); err != nil {
+		return err
+	}
+	return r.Save(transaction)
+}
+
+func (r *pendingTransactionRepository) SaveBatch(transactions []*repo_model.TransactionModel) error {
+	for _, transaction := range transactions {
+		if err := r.Save(transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *pendingTransactionRepository) GetByID(id string) (*repo_model.TransactionModel, error) {
+	if model, ok := r.byID[id]; ok {
+		return model, nil
+	}
+	return r.TransactionRepository.GetByID(id)
+}
+
+func (r *pendingTransactionRepository) GetByRoundID(roundID string) ([]*repo_model.TransactionModel, error) {
+	models, err := r.TransactionRepository.GetByRoundID(roundID)
+	if err != nil {
+		return nil, err
+	}
+	return append(models, r.filter(func(model *repo_model.TransactionModel) bool {
+		return model.RoundID == roundID
+	})...), nil
+}
+
+func (r *pendingTransactionRepository) GetByOriginalID(originalID string) ([]*repo_model.TransactionModel, error) {
+	models, err := r.TransactionRepository.GetByOriginalID(originalID)
+	if err != nil {
+		return nil, err
+	}
+	return append(models, r.filter(func(model *repo_model.TransactionModel) bool {
+		return model.OriginalTransactionID != nil && *model.OriginalTransactionID == originalID
+	})...), nil
+}
+
+func (r *pendingTransactionRepository) GetTotals(userID string) ([]*repo_model.TransactionTotalModel, error) {
+	totals, err := r.TransactionRepository.GetTotals(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, model := range r.filter(func(model *repo_model.TransactionModel) bool { return model.UserID == userID }) {
+		total := &repo_model.TransactionTotalModel{
+			Currency:        model.Currency,
+			TransactionType: model.TransactionType,
+			Total:           int64(model.Amount),
+			Count:           1,
+		}
+		if model.OriginalTransactionID != nil {
+			original, err := r.GetByID(*model.OriginalTransactionID)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				return nil, err
+			}
+			if original != nil {
+				total.OriginalTransactionType = original.TransactionType
+			}
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, nil
+}
+
+func (r *pendingTransactionRepository) filter(match func(*repo_model.TransactionModel) bool) []*repo_model.TransactionModel {
+	var models []*repo_model.TransactionModel
+	for _, model := range r.saved {
+		if match(model) {
+			models = append(models, model)
+		}
+	}
+	return models
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAuditUseCase struct {
+	records []*dto.CreateAuditRecordDTO
+	err     error
+}
+
+func (m *MockAuditUseCase) Record(record *dto.CreateAuditRecordDTO) error {
+	m.records = append(m.records, record)
+	return m.err
+}
+
+func (m *MockAuditUseCase) VerifyChain(from, to time.Time) (*dto.AuditVerificationDTO, error) {
+	return &dto.AuditVerificationDTO{Valid: true}, nil
+}
+
+func importRow(line int, transaction *dto.CreateTransactionDTO) *dto.ImportRowDTO {
+	if transaction.Currency == "" {
+		transaction.Currency = "EUR"
+	}
+	return &dto.ImportRowDTO{
+		Line:        line,
+		Source:      fmt.Sprintf("history.csv:%d", line),
+		Payload:     []byte(transaction.ID),
+		Transaction: transaction,
+	}
+}
+
+func TestImportUseCase_ImportBatch(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	mockAudit := &MockAuditUseCase{}
+	useCase := NewImportUseCaseImpl(mockRepo, &MockCurrencyConverter{rates: map[string]string{"USD": "0.9"}}, mockAudit)
+
+	userID := "550e8400-e29b-41d4-a716-446655440010"
+	existingID := "550e8400-e29b-41d4-a716-446655440000"
+	betID := "550e8400-e29b-41d4-a716-446655440001"
+	placedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mockRepo.On("GetByID", existingID).Return(&repo_model.TransactionModel{ID: existingID}, nil)
+	mockRepo.On("GetByID", mock.Anything).Return(nil, repository.ErrNotFound)
+	mockRepo.On("GetByRoundID", "round-1").Return(nil, nil)
+	mockRepo.On("GetByOriginalID", betID).Return(nil, nil)
+	mockRepo.On("GetTotals", userID).Return([]*repo_model.TransactionTotalModel{
+		{Currency: "USD", TransactionType: "deposit", Total: 1000, Count: 1},
+	}, nil)
+
+	var saved []*repo_model.TransactionModel
+	mockRepo.On("SaveBatch", mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(0).([]*repo_model.TransactionModel)
+	}).Return(nil).Once()
+
+	result, err := useCase.ImportBatch([]*dto.ImportRowDTO{
+		importRow(2, &dto.CreateTransactionDTO{ID: betID, UserID: userID, TransactionType: "bet", Amount: 1000, Currency: "USD", RoundID: "round-1", Timestamp: placedAt}),
+		importRow(3, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440002", UserID: userID, TransactionType: "win", Amount: 500, Currency: "USD", RoundID: "round-1", Timestamp: placedAt.Add(time.Minute)}),
+		importRow(4, &dto.CreateTransactionDTO{ID: existingID, UserID: userID, TransactionType: "bet", Amount: 100}),
+		importRow(5, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440003", UserID: userID, TransactionType: "bet", Amount: 0}),
+		importRow(6, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440004", UserID: userID, TransactionType: "rollback", Amount: 1000, Currency: "USD", OriginalTransactionID: betID}),
+		importRow(7, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440005", UserID: userID, TransactionType: "rollback", Amount: 1000, Currency: "USD", OriginalTransactionID: betID}),
+		importRow(8, &dto.CreateTransactionDTO{ID: betID, UserID: userID, TransactionType: "bet", Amount: 1000, Currency: "USD"}),
+		importRow(9, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440006", UserID: userID, TransactionType: "withdrawal", Amount: 100}),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Inserted)
+
+	if assert.Len(t, result.Duplicates, 2) {
+		assert.Equal(t, 4, result.Duplicates[0].Line)
+		assert.Equal(t, 8, result.Duplicates[1].Line)
+	}
+
+	if assert.Len(t, result.Invalid, 3) {
+		assert.Equal(t, 5, result.Invalid[0].Line)
+		assert.Equal(t, 7, result.Invalid[1].Line)
+		assert.Contains(t, result.Invalid[1].Reason, "already cancelled")
+		assert.Equal(t, 9, result.Invalid[2].Line)
+		assert.Contains(t, result.Invalid[2].Reason, "insufficient EUR balance")
+	}
+
+	if assert.Len(t, saved, 3) {
+		assert.Equal(t, betID, saved[0].ID)
+		assert.True(t, saved[0].Timestamp.Equal(placedAt))
+		assert.Equal(t, int64(900), *saved[0].ReportingAmount)
+		assert.Equal(t, "win", saved[1].TransactionType)
+		assert.Equal(t, "rollback", saved[2].TransactionType)
+	}
+
+	if assert.Len(t, mockAudit.records, 8) {
+		for i, record := range mockAudit.records {
+			line := i + 2
+			assert.Equal(t, "import", record.Actor)
+			assert.Equal(t, "transaction.import", record.Action)
+			assert.Equal(t, fmt.Sprintf("history.csv:%d", line), record.Source)
+			inserted := line == 2 || line == 3 || line == 6
+			assert.Equal(t, inserted, record.Err == nil, "line %d", line)
+		}
+		assert.True(t, utils.IsTransactionAlreadyExists(mockAudit.records[2].Err))
+		assert.Equal(t, []byte(betID), mockAudit.records[0].Payload)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportUseCase_ImportBatch_UnreadableRows(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	mockAudit := &MockAuditUseCase{}
+	useCase := NewImportUseCaseImpl(mockRepo, &MockCurrencyConverter{}, mockAudit)
+
+	readErr := errors.New("invalid JSON")
+	result, err := useCase.ImportBatch([]*dto.ImportRowDTO{
+		{Line: 3, Source: "history.ndjson:3", Payload: []byte("not json"), Err: readErr},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Inserted)
+	if assert.Len(t, result.Invalid, 1) {
+		assert.Equal(t, 3, result.Invalid[0].Line)
+		assert.Equal(t, "invalid JSON", result.Invalid[0].Reason)
+	}
+	if assert.Len(t, mockAudit.records, 1) {
+		assert.Equal(t, "history.ndjson:3", mockAudit.records[0].Source)
+		assert.Equal(t, readErr, mockAudit.records[0].Err)
+	}
+	mockRepo.AssertNotCalled(t, "SaveBatch", mock.Anything)
+}
+
+func TestImportUseCase_ImportBatch_AuditError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewImportUseCaseImpl(mockRepo, &MockCurrencyConverter{}, &MockAuditUseCase{err: errors.New("audit unavailable")})
+
+	mockRepo.On("GetByID", mock.Anything).Return(nil, repository.ErrNotFound)
+	mockRepo.On("SaveBatch", mock.Anything).Return(nil).Once()
+
+	result, err := useCase.ImportBatch([]*dto.ImportRowDTO{
+		importRow(1, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440001", UserID: "user", TransactionType: "deposit", Amount: 100}),
+	})
+
+	assert.ErrorContains(t, err, "failed to audit line 1")
+	assert.Nil(t, result)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportUseCase_ImportBatch_WithdrawalAgainstImportedDeposit(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewImportUseCaseImpl(mockRepo, &MockCurrencyConverter{}, &MockAuditUseCase{})
+
+	userID := "550e8400-e29b-41d4-a716-446655440010"
+	mockRepo.On("GetByID", mock.Anything).Return(nil, repository.ErrNotFound)
+	mockRepo.On("GetTotals", userID).Return([]*repo_model.TransactionTotalModel{
+		{Currency: "EUR", TransactionType: "deposit", Total: 100, Count: 1},
+	}, nil)
+	mockRepo.On("SaveBatch", mock.MatchedBy(func(models []*repo_model.TransactionModel) bool {
+		return len(models) == 2
+	})).Return(nil).Once()
+
+	result, err := useCase.ImportBatch([]*dto.ImportRowDTO{
+		importRow(1, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440001", UserID: userID, TransactionType: "deposit", Amount: 400}),
+		importRow(2, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440002", UserID: userID, TransactionType: "withdrawal", Amount: 500}),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Inserted)
+	assert.Empty(t, result.Invalid)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestImportUseCase_ImportBatch_RepositoryError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewImportUseCaseImpl(mockRepo, &MockCurrencyConverter{}, &MockAuditUseCase{})
+
+	mockRepo.On("GetByID", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+	result, err := useCase.ImportBatch([]*dto.ImportRowDTO{
+		importRow(1, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440001", UserID: "user", TransactionType: "bet", Amount: 100}),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.False(t, utils.IsTransactionValidation(err))
+	mockRepo.AssertNotCalled(t, "SaveBatch", mock.Anything)
+}
+
+func TestImportUseCase_ImportBatch_SaveError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	mockAudit := &MockAuditUseCase{}
+	useCase := NewImportUseCaseImpl(mockRepo, &MockCurrencyConverter{}, mockAudit)
+
+	mockRepo.On("GetByID", mock.Anything).Return(nil, repository.ErrNotFound)
+	mockRepo.On("GetTotals", "user").Return([]*repo_model.TransactionTotalModel{
+		{Currency: "EUR", TransactionType: "deposit", Total: 100, Count: 1},
+	}, nil)
+	mockRepo.On("SaveBatch", mock.Anything).Return(errors.New("database error")).Once()
+
+	result, err := useCase.ImportBatch([]*dto.ImportRowDTO{
+		importRow(1, &dto.CreateTransactionDTO{ID: "550e8400-e29b-41d4-a716-446655440001", UserID: "user", TransactionType: "bet", Amount: 100}),
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+	assert.Empty(t, mockAudit.records)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/domain/entity"
+	"casino/utils"
+)
+
+type ReportUseCaseImpl struct {
+	reportingRepo repository.ReportingRepository
+	now           func() time.Time
+}
+
+func NewReportUseCaseImpl(reportingRepo repository.ReportingRepository) *ReportUseCaseImpl {
+	return &ReportUseCaseImpl{
+		reportingRepo: reportingRepo,
+		now:           time.Now,
+	}
+}
+
+// GetGGR reports gross gaming revenue in the reporting currency. Transactions
+// without a reporting amount are left out of the sums and counted separately.
+func (uc *ReportUseCaseImpl) GetGGR(filter *dto.GGRFilterDTO) (*dto.GGRReportDTO, error) {
+	groupBy := entity.ReportGroupBy(filter.GroupBy)
+	if groupBy == "" {
+		groupBy = entity.ReportGroupByDay
+	}
+
+	if !groupBy.IsValid() {
+		return nil, &utils.ReportValidationError{Reason: fmt.Sprintf("unsupported group_by %q", filter.GroupBy)}
+	}
+
+	if !filter.To.After(filter.From) {
+		return nil, &utils.ReportValidationError{Reason: "to must be after from"}
+	}
+
+	// Whole-day ranges are served from the daily aggregates. Anything finer,
+	// and grouping by game, needs the raw transactions.
+	getGGR := uc.reportingRepo.GetGGR
+	if isDayStart(filter.From) && isDayStart(filter.To) && groupBy != entity.ReportGroupByGame {
+		getGGR = uc.reportingRepo.GetDailyGGR
+	}
+
+	rows, err := getGGR(filter.From, filter.To, string(groupBy))
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := getGGR(filter.From, filter.To, "")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.GGRReportDTO{
+		From:     filter.From,
+		To:       filter.To,
+		GroupBy:  string(groupBy),
+		Currency: string(entity.ReportingCurrency),
+		Rows:     make([]*dto.GGRRowDTO, len(rows)),
+		Total:    &dto.GGRRowDTO{},
+	}
+
+	for i, row := range rows {
+		report.Rows[i] = ggrRow(row)
+	}
+
+	if len(totals) > 0 {
+		report.Total = ggrRow(totals[0])
+	}
+
+	return report, nil
+}
+
+// RebuildDailyAggregates recomputes the daily aggregates for whole UTC days in
+// [from, to) and returns the number of aggregate rows written.
+func (uc *ReportUseCaseImpl) RebuildDailyAggregates(from, to time.Time) (int64, error) {
+	if !isDayStart(from) || !isDayStart(to) {
+		return 0, &utils.ReportValidationError{Reason: "from and to must be at midnight UTC"}
+	}
+
+	if !to.After(from) {
+		return 0, &utils.ReportValidationError{Reason: "to must be after from"}
+	}
+
+	return uc.reportingRepo.RebuildDailyAggregates(from.UTC(), to.UTC())
+}
+
+func isDayStart(t time.Time) bool {
+	return t.Equal(repo_model.AggregateDay(t))
+}
+
+func ggrRow(model *repo_model.GGRRowModel) *dto.GGRRowDTO {
+	return &dto.GGRRowDTO{
+		Key:              model.GroupKey,
+		TotalBets:        model.TotalBets,
+		TotalWins:        model.TotalWins,
+		GGR:              model.TotalBets - model.TotalWins,
+		BetCount:         model.BetCount,
+		UniquePlayers:    model.UniquePlayers,
+		UnconvertedCount: model.UnconvertedCount,
+	}
+}
+
+// GetUserStats summarises a player's activity over their whole history and the
+// last 24 hours, 7 days and 30 days. Amounts are in the reporting currency.
+func (uc *ReportUseCaseImpl) GetUserStats(userID string) (*dto.UserStatsDTO, error) {
+	now := uc.now().UTC()
+	stats := &dto.UserStatsDTO{
+		UserID:   userID,
+		Currency: string(entity.ReportingCurrency),
+	}
+
+	periods := []struct {
+		target **dto.UserStatsPeriodDTO
+		window time.Duration
+	}{
+		{&stats.Lifetime, 0},
+		{&stats.Last24h, 24 * time.Hour},
+		{&stats.Last7d, 7 * 24 * time.Hour},
+		{&stats.Last30d, 30 * 24 * time.Hour},
+	}
+
+	for _, period := range periods {
+		var since *time.Time
+		if period.window > 0 {
+			start := now.Add(-period.window)
+			since = &start
+		}
+
+		model, err := uc.reportingRepo.GetUserStats(userID, since)
+		if err != nil {
+			return nil, err
+		}
+		*period.target = userStatsPeriod(model)
+	}
+
+	return stats, nil
+}
+
+func userStatsPeriod(model *repo_model.UserStatsModel) *dto.UserStatsPeriodDTO {
+	period := &dto.UserStatsPeriodDTO{
+		TotalBets:        model.TotalBets,
+		TotalWins:        model.TotalWins,
+		NetResult:        model.TotalWins - model.TotalBets,
+		TransactionCount: model.TransactionCount,
+		BetCount:         model.BetCount,
+		WinCount:         model.WinCount,
+		BiggestWin:       model.BiggestWin,
+		UnconvertedCount: model.UnconvertedCount,
+		FirstActivity:    model.FirstActivity,
+		LastActivity:     model.LastActivity,
+	}
+
+	if model.TotalBets > 0 {
+		rtp := float64(model.TotalWins) / float64(model.TotalBets) * 100
+		period.RTP = &rtp
+	}
+
+	return period
+}
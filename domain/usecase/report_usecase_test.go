@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockReportingRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportingRepository) GetGGR(from, to time.Time, groupBy string) ([]*repo_model.GGRRowModel, error) {
+	args := m.Called(from, to, groupBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.GGRRowModel), args.Error(1)
+}
+
+func (m *MockReportingRepository) GetUserStats(userID string, since *time.Time) (*repo_model.UserStatsModel, error) {
+	args := m.Called(userID, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repo_model.UserStatsModel), args.Error(1)
+}
+
+func (m *MockReportingRepository) GetDailyGGR(from, to time.Time, groupBy string) ([]*repo_model.GGRRowModel, error) {
+	args := m.Called(from, to, groupBy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.GGRRowModel), args.Error(1)
+}
+
+func (m *MockReportingRepository) RebuildDailyAggregates(from, to time.Time) (int64, error) {
+	args := m.Called(from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestReportUseCase_GetGGR(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+	mockRepo.On("GetGGR", from, to, "game").Return([]*repo_model.GGRRowModel{
+		{GroupKey: "roulette", TotalBets: 500, TotalWins: 900, BetCount: 2, UniquePlayers: 1},
+		{GroupKey: "slots", TotalBets: 1000, TotalWins: 400, BetCount: 3, UniquePlayers: 2, UnconvertedCount: 1},
+	}, nil).Once()
+	mockRepo.On("GetGGR", from, to, "").Return([]*repo_model.GGRRowModel{
+		{TotalBets: 1500, TotalWins: 1300, BetCount: 5, UniquePlayers: 2, UnconvertedCount: 1},
+	}, nil).Once()
+
+	report, err := useCase.GetGGR(&dto.GGRFilterDTO{From: from, To: to, GroupBy: "game"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "game", report.GroupBy)
+	assert.Equal(t, "EUR", report.Currency)
+	assert.Len(t, report.Rows, 2)
+	assert.Equal(t, int64(-400), report.Rows[0].GGR)
+	assert.Equal(t, int64(600), report.Rows[1].GGR)
+	assert.Equal(t, int64(200), report.Total.GGR)
+	assert.Equal(t, int64(2), report.Total.UniquePlayers)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReportUseCase_GetGGR_DefaultsToDay(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	to := time.Now()
+	mockRepo.On("GetGGR", time.Time{}, to, "day").Return([]*repo_model.GGRRowModel{}, nil).Once()
+	mockRepo.On("GetGGR", time.Time{}, to, "").Return([]*repo_model.GGRRowModel{}, nil).Once()
+
+	report, err := useCase.GetGGR(&dto.GGRFilterDTO{To: to})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "day", report.GroupBy)
+	assert.Empty(t, report.Rows)
+	assert.Equal(t, int64(0), report.Total.GGR)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReportUseCase_GetGGR_WholeDaysUseAggregates(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(72 * time.Hour)
+	mockRepo.On("GetDailyGGR", from, to, "user").Return([]*repo_model.GGRRowModel{
+		{GroupKey: "user-1", TotalBets: 1000, TotalWins: 250, BetCount: 4, UniquePlayers: 1},
+	}, nil).Once()
+	mockRepo.On("GetDailyGGR", from, to, "").Return([]*repo_model.GGRRowModel{
+		{TotalBets: 1000, TotalWins: 250, BetCount: 4, UniquePlayers: 1},
+	}, nil).Once()
+
+	report, err := useCase.GetGGR(&dto.GGRFilterDTO{From: from, To: to, GroupBy: "user"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(750), report.Rows[0].GGR)
+	assert.Equal(t, int64(750), report.Total.GGR)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetGGR", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReportUseCase_GetGGR_PartialDaysUseRawTransactions(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	from := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("GetGGR", from, to, "day").Return([]*repo_model.GGRRowModel{}, nil).Once()
+	mockRepo.On("GetGGR", from, to, "").Return([]*repo_model.GGRRowModel{}, nil).Once()
+
+	_, err := useCase.GetGGR(&dto.GGRFilterDTO{From: from, To: to, GroupBy: "day"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetDailyGGR", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReportUseCase_GetGGR_Invalid(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name   string
+		filter *dto.GGRFilterDTO
+	}{
+		{"Unknown Grouping", &dto.GGRFilterDTO{From: now.Add(-time.Hour), To: now, GroupBy: "month"}},
+		{"Empty Range", &dto.GGRFilterDTO{From: now, To: now, GroupBy: "day"}},
+		{"Reversed Range", &dto.GGRFilterDTO{From: now, To: now.Add(-time.Hour), GroupBy: "day"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockReportingRepository{}
+			useCase := NewReportUseCaseImpl(mockRepo)
+
+			_, err := useCase.GetGGR(tc.filter)
+			assert.True(t, utils.IsReportValidation(err))
+			mockRepo.AssertNotCalled(t, "GetGGR", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReportUseCase_GetGGR_RepositoryError(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	to := time.Now()
+	mockRepo.On("GetGGR", time.Time{}, to, "user").Return(nil, errors.New("database error")).Once()
+
+	report, err := useCase.GetGGR(&dto.GGRFilterDTO{To: to, GroupBy: "user"})
+
+	assert.Error(t, err)
+	assert.Nil(t, report)
+	mockRepo.AssertExpectations(t)
+}
+
+func sinceWindow(now time.Time, window time.Duration) interface{} {
+	return mock.MatchedBy(func(since *time.Time) bool {
+		return since != nil && since.Equal(now.Add(-window))
+	})
+}
+
+func TestReportUseCase_GetUserStats(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	useCase.now = func() time.Time { return now }
+
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := now.Add(-time.Hour)
+	mockRepo.On("GetUserStats", "user123", (*time.Time)(nil)).Return(&repo_model.UserStatsModel{
+		TotalBets: 10000, TotalWins: 9500, BetCount: 40, WinCount: 12, TransactionCount: 55,
+		BiggestWin: 3000, UnconvertedCount: 2, FirstActivity: &first, LastActivity: &last,
+	}, nil).Once()
+	mockRepo.On("GetUserStats", "user123", sinceWindow(now, 24*time.Hour)).Return(&repo_model.UserStatsModel{
+		TotalBets: 200, TotalWins: 500, BetCount: 2, WinCount: 1, TransactionCount: 3, BiggestWin: 500,
+	}, nil).Once()
+	mockRepo.On("GetUserStats", "user123", sinceWindow(now, 7*24*time.Hour)).Return(&repo_model.UserStatsModel{}, nil).Once()
+	mockRepo.On("GetUserStats", "user123", sinceWindow(now, 30*24*time.Hour)).Return(&repo_model.UserStatsModel{
+		TotalBets: 1000, TotalWins: 800, BetCount: 5, TransactionCount: 9,
+	}, nil).Once()
+
+	stats, err := useCase.GetUserStats("user123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user123", stats.UserID)
+	assert.Equal(t, "EUR", stats.Currency)
+	assert.Equal(t, int64(-500), stats.Lifetime.NetResult)
+	assert.InDelta(t, 95.0, *stats.Lifetime.RTP, 0.0001)
+	assert.Equal(t, &first, stats.Lifetime.FirstActivity)
+	assert.Equal(t, int64(300), stats.Last24h.NetResult)
+	assert.InDelta(t, 250.0, *stats.Last24h.RTP, 0.0001)
+	assert.Nil(t, stats.Last7d.RTP)
+	assert.InDelta(t, 80.0, *stats.Last30d.RTP, 0.0001)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReportUseCase_GetUserStats_RepositoryError(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	mockRepo.On("GetUserStats", "user123", (*time.Time)(nil)).Return(nil, errors.New("database error")).Once()
+
+	stats, err := useCase.GetUserStats("user123")
+
+	assert.Error(t, err)
+	assert.Nil(t, stats)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReportUseCase_RebuildDailyAggregates(t *testing.T) {
+	mockRepo := &MockReportingRepository{}
+	useCase := NewReportUseCaseImpl(mockRepo)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("RebuildDailyAggregates", from, to).Return(int64(42), nil).Once()
+
+	written, err := useCase.RebuildDailyAggregates(from, to)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), written)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReportUseCase_RebuildDailyAggregates_Invalid(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name string
+		from time.Time
+		to   time.Time
+	}{
+		{"Partial From", day.Add(time.Hour), day.Add(24 * time.Hour)},
+		{"Partial To", day, day.Add(25 * time.Hour)},
+		{"Empty Range", day, day},
+		{"Reversed Range", day.Add(24 * time.Hour), day},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockReportingRepository{}
+			useCase := NewReportUseCaseImpl(mockRepo)
+
+			_, err := useCase.RebuildDailyAggregates(tc.from, tc.to)
+			assert.True(t, utils.IsReportValidation(err))
+			mockRepo.AssertNotCalled(t, "RebuildDailyAggregates", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/domain/entity"
+	"casino/utils"
+)
+
+const maxReasonLength = 255
+
+func invalidTransaction(dto *dto.CreateTransactionDTO, reason string) error {
+	return &utils.TransactionValidationError{TransactionID: dto.ID, Reason: reason}
+}
+
+func validateTransaction(dto *dto.CreateTransactionDTO) error {
+	if dto.ID == "" {
+		return invalidTransaction(dto, "id is required")
+	}
+	if dto.UserID == "" {
+		return invalidTransaction(dto, "user_id is required")
+	}
+	if dto.Amount == 0 {
+		return invalidTransaction(dto, "amount must be positive")
+	}
+	if uint64(dto.Amount) > math.MaxInt64 {
+		return invalidTransaction(dto, "amount exceeds the supported range")
+	}
+	if !entity.Currency(dto.Currency).IsValid() {
+		return invalidTransaction(dto, fmt.Sprintf("unknown currency %q", dto.Currency))
+	}
+
+	transactionType := entity.TransactionType(dto.TransactionType)
+	if !transactionType.IsValid() {
+		return invalidTransaction(dto, fmt.Sprintf("unknown transaction type %q", dto.TransactionType))
+	}
+
+	if transactionType == entity.TransactionTypeRollback && dto.OriginalTransactionID == "" {
+		return invalidTransaction(dto, "original_transaction_id is required for rollback")
+	}
+	if !transactionType.RequiresOriginal() && dto.OriginalTransactionID != "" {
+		return invalidTransaction(dto, fmt.Sprintf("original_transaction_id is not allowed for %s", dto.TransactionType))
+	}
+	if dto.OriginalTransactionID == dto.ID {
+		return invalidTransaction(dto, "transaction cannot reference itself")
+	}
+	if len(dto.Reason) > maxReasonLength {
+		return invalidTransaction(dto, fmt.Sprintf("reason must not exceed %d characters", maxReasonLength))
+	}
+
+	return nil
+}
+
+// checkRules applies the type specific rules that depend on stored state and
+// returns the referenced original transaction, if any.
+func (uc *TransactionUseCaseImpl) checkRules(dto *dto.CreateTransactionDTO) (*entity.Transaction, error) {
+	switch entity.TransactionType(dto.TransactionType) {
+	case entity.TransactionTypeWin:
+		return nil, uc.checkRound(dto)
+	case entity.TransactionTypeRefund:
+		return uc.checkRefund(dto)
+	case entity.TransactionTypeRollback:
+		return uc.checkRollback(dto)
+	default:
+		return nil, nil
+	}
+}
+
+// checkRound rejects a win that settles a round in which the same user has
+// not placed a bet in the same currency yet. Wins without a round id predate
+// round tracking and are accepted as before.
+func (uc *TransactionUseCaseImpl) checkRound(dto *dto.CreateTransactionDTO) error {
+	if dto.RoundID == "" {
+		return nil
+	}
+
+	models, err := uc.transactionRepo.GetByRoundID(dto.RoundID)
+	if err != nil {
+		return fmt.Errorf("failed to check round: %w", err)
+	}
+
+	for _, model := range models {
+		if entity.TransactionType(model.TransactionType) == entity.TransactionTypeBet &&
+			model.UserID == dto.UserID && model.Currency == dto.Currency {
+			return nil
+		}
+	}
+
+	return invalidTransaction(dto, fmt.Sprintf("no bet found for round %s", dto.RoundID))
+}
+
+// checkBalance returns the balance check for a bet or a withdrawal, the two
+// types that take money from the user. It is passed to SaveChecked rather than
+// run with the other rules, so that the balance is read under the user's lock
+// and concurrent debits cannot spend it twice.
+func checkBalance(dto *dto.CreateTransactionDTO) func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+	return func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		balance := balancesFromTotals(totals)[dto.Currency]
+		if int64(dto.Amount) > balance {
+			return invalidTransaction(dto, fmt.Sprintf("insufficient %s balance: %d available", dto.Currency, balance))
+		}
+		return nil
+	}
+}
+
+// checkRefund accepts a refund only for an earlier bet of the same user and
+// never for more than was staked. A refund without a reference is a
+// goodwill credit and needs no further checks.
+func (uc *TransactionUseCaseImpl) checkRefund(dto *dto.CreateTransactionDTO) (*entity.Transaction, error) {
+	if dto.OriginalTransactionID == "" {
+		return nil, nil
+	}
+
+	original, err := uc.original(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.TransactionType(original.TransactionType) != entity.TransactionTypeBet {
+		return nil, invalidTransaction(dto, "refund must reference a bet")
+	}
+	if dto.Amount > original.Amount {
+		return nil, invalidTransaction(dto, "refund exceeds the original bet amount")
+	}
+	return original, nil
+}
+
+// checkReversal returns the check for a refund or rollback against what has
+// already been reversed of the original. Refunds and a rollback together may
+// never return more than the original amount, so a refunded bet cannot also
+// be rolled back in full or the other way round. Like checkBalance it runs
+// under the user's lock, so concurrent reversals cannot both pass.
+func checkReversal(dto *dto.CreateTransactionDTO, original *entity.Transaction) func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+	return func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		var reversed uint64
+		for _, model := range linked {
+			switch entity.TransactionType(model.TransactionType) {
+			case entity.TransactionTypeRollback:
+				if entity.TransactionType(dto.TransactionType) == entity.TransactionTypeRollback {
+					return &utils.TransactionAlreadyCancelledError{TransactionID: original.ID}
+				}
+				reversed += uint64(model.Amount)
+			case entity.TransactionTypeRefund:
+				reversed += uint64(model.Amount)
+			}
+		}
+
+		if reversed+uint64(dto.Amount) > uint64(original.Amount) {
+			return invalidTransaction(dto, fmt.Sprintf("%s exceeds what is left of the original amount: %d already refunded or rolled back", dto.TransactionType, reversed))
+		}
+		return nil
+	}
+}
+
+func (uc *TransactionUseCaseImpl) checkRollback(dto *dto.CreateTransactionDTO) (*entity.Transaction, error) {
+	original, err := uc.original(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.TransactionType(original.TransactionType) == entity.TransactionTypeRollback {
+		return nil, invalidTransaction(dto, "a rollback cannot be rolled back")
+	}
+	if dto.Amount != original.Amount {
+		return nil, invalidTransaction(dto, "rollback amount must equal the original amount")
+	}
+	return original, nil
+}
+
+func (uc *TransactionUseCaseImpl) original(dto *dto.CreateTransactionDTO) (*entity.Transaction, error) {
+	model, err := uc.transactionRepo.GetByID(dto.OriginalTransactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, &utils.TransactionNotFoundError{TransactionID: dto.OriginalTransactionID}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get original transaction: %w", err)
+	}
+	if model.UserID != dto.UserID {
+		return nil, invalidTransaction(dto, "original transaction belongs to another user")
+	}
+	if model.Currency != dto.Currency {
+		return nil, invalidTransaction(dto, fmt.Sprintf("currency must match the original transaction (%s)", model.Currency))
+	}
+	return model.ToEntity(), nil
+}
+
+// balances sums the balance effect of every transaction of a user, keyed by
+// currency. Amounts in different currencies are never mixed.
+func (uc *TransactionUseCaseImpl) balances(userID string) (map[string]int64, error) {
+	totals, err := uc.transactionRepo.GetTotals(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return balancesFromTotals(totals), nil
+}
+
+func balancesFromTotals(totals []*repo_model.TransactionTotalModel) map[string]int64 {
+	balances := make(map[string]int64)
+	for _, total := range totals {
+		balances[total.Currency] += entity.BalanceEffect(
+			entity.TransactionType(total.TransactionType),
+			entity.TransactionType(total.OriginalTransactionType),
+			total.Total,
+		)
+	}
+	return balances
+}
@@ -0,0 +1,522 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	rulesUserID     = "550e8400-e29b-41d4-a716-446655440010"
+	rulesOriginalID = "550e8400-e29b-41d4-a716-446655440001"
+	rulesNewID      = "550e8400-e29b-41d4-a716-446655440099"
+)
+
+func TestValidateTransaction_OriginalReference(t *testing.T) {
+	testCases := []struct {
+		name  string
+		dto   *dto.CreateTransactionDTO
+		valid bool
+	}{
+		{"Deposit", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "deposit", Amount: 100}, true},
+		{"Bonus Credit", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bonus_credit", Amount: 100}, true},
+		{"Refund Without Original", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "refund", Amount: 100}, true},
+		{"Rollback Without Original", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 100}, false},
+		{"Deposit With Original", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "deposit", Amount: 100, OriginalTransactionID: rulesOriginalID}, false},
+		{"Self Reference", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 100, OriginalTransactionID: rulesNewID}, false},
+		{"Unknown Currency", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "XYZ", TransactionType: "deposit", Amount: 100}, false},
+		{"Missing Currency", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: "deposit", Amount: 100}, false},
+		{"Large ETH Deposit", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "ETH", TransactionType: "deposit", Amount: 5_000 * 1_000_000_000}, true},
+		{"Amount Beyond Bigint", &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "deposit", Amount: math.MaxInt64 + 1}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateTransaction(tc.dto)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, utils.IsTransactionValidation(err))
+			}
+		})
+	}
+}
+
+func TestProcessTransaction_DebitsNeedBalance(t *testing.T) {
+	totals := []*repo_model.TransactionTotalModel{
+		{Currency: "EUR", TransactionType: "deposit", Total: 1000, Count: 1},
+		{Currency: "EUR", TransactionType: "bet", Total: 300, Count: 2},
+		{Currency: "EUR", TransactionType: "win", Total: 100, Count: 1},
+		{Currency: "EUR", TransactionType: "rollback", OriginalTransactionType: "bet", Total: 100, Count: 1},
+	}
+
+	testCases := []struct {
+		name            string
+		transactionType string
+		amount          uint
+		valid           bool
+	}{
+		{"Withdrawal Within Balance", "withdrawal", 900, true},
+		{"Withdrawal Exceeds Balance", "withdrawal", 901, false},
+		{"Bet Within Balance", "bet", 900, true},
+		{"Bet Exceeds Balance", "bet", 901, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockTransactionRepository{}
+			useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+			debit := &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: tc.transactionType, Amount: tc.amount}
+
+			mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+			mockRepo.On("GetTotals", rulesUserID).Return(totals, nil).Once()
+			if tc.valid {
+				mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+			}
+
+			err := useCase.ProcessTransaction(debit)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, mockRepo.checkedSaves, "the balance must be checked within the save")
+			} else {
+				assert.True(t, utils.IsTransactionValidation(err))
+				mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessTransaction_WithdrawalTotalsError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	withdrawal := &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: "withdrawal", Amount: 100}
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, assert.AnError).Once()
+
+	err := useCase.ProcessTransaction(withdrawal)
+	assert.Error(t, err)
+	assert.False(t, utils.IsTransactionValidation(err))
+}
+
+func TestProcessTransaction_Refund(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original *repo_model.TransactionModel
+		amount   uint
+		valid    bool
+	}{
+		{"Partial Refund Of Bet", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}, 400, true},
+		{"Refund Exceeds Bet", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}, 1001, false},
+		{"Refund Of Win", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "win", Amount: 1000}, 100, false},
+		{"Refund Of Other User", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: "someone-else", Currency: "EUR", TransactionType: "bet", Amount: 1000}, 100, false},
+		{"Unknown Original", nil, 100, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockTransactionRepository{}
+			useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+			refund := &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: "refund", Amount: tc.amount, OriginalTransactionID: rulesOriginalID}
+
+			mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+			if tc.original == nil {
+				mockRepo.On("GetByID", rulesOriginalID).Return(nil, repository.ErrNotFound).Once()
+			} else {
+				mockRepo.On("GetByID", rulesOriginalID).Return(tc.original, nil).Once()
+			}
+			if tc.valid {
+				mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+				mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{}, nil).Once()
+				mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+			}
+
+			err := useCase.ProcessTransaction(refund)
+			switch {
+			case tc.valid:
+				assert.NoError(t, err)
+			case tc.original == nil:
+				assert.True(t, utils.IsTransactionNotFound(err))
+			default:
+				assert.True(t, utils.IsTransactionValidation(err))
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessTransaction_Rollback(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original *repo_model.TransactionModel
+		amount   uint
+		valid    bool
+	}{
+		{"Rollback Of Bet", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}, 1000, true},
+		{"Rollback Of Deposit", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "deposit", Amount: 500}, 500, true},
+		{"Amount Mismatch", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}, 900, false},
+		{"Rollback Of Rollback", &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 1000}, 1000, false},
+		{"Unknown Original", nil, 1000, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockTransactionRepository{}
+			useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+			rollback := &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: "rollback", Amount: tc.amount, OriginalTransactionID: rulesOriginalID}
+
+			mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+			if tc.original == nil {
+				mockRepo.On("GetByID", rulesOriginalID).Return(nil, repository.ErrNotFound).Once()
+			} else {
+				mockRepo.On("GetByID", rulesOriginalID).Return(tc.original, nil).Once()
+			}
+			if tc.valid {
+				mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+				mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{}, nil).Once()
+				mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+			}
+
+			err := useCase.ProcessTransaction(rollback)
+			switch {
+			case tc.valid:
+				assert.NoError(t, err)
+			case tc.original == nil:
+				assert.True(t, utils.IsTransactionNotFound(err))
+			default:
+				assert.True(t, utils.IsTransactionValidation(err))
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessTransaction_ReversalsLimitedToOriginal(t *testing.T) {
+	testCases := []struct {
+		name            string
+		transactionType string
+		amount          uint
+		existing        []*repo_model.TransactionModel
+		valid           bool
+	}{
+		{"Refund Within Remaining Stake", "refund", 600, []*repo_model.TransactionModel{{TransactionType: "refund", Amount: 400}}, true},
+		{"Refunds Beyond Stake", "refund", 601, []*repo_model.TransactionModel{{TransactionType: "refund", Amount: 400}}, false},
+		{"Refund Of Rolled Back Bet", "refund", 100, []*repo_model.TransactionModel{{TransactionType: "rollback", Amount: 1000}}, false},
+		{"Rollback Of Refunded Bet", "rollback", 1000, []*repo_model.TransactionModel{{TransactionType: "refund", Amount: 100}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockTransactionRepository{}
+			useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+			original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}
+
+			mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+			mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Once()
+			mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+			mockRepo.On("GetByOriginalID", rulesOriginalID).Return(tc.existing, nil).Once()
+			if tc.valid {
+				mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+			}
+
+			err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: tc.transactionType, Amount: tc.amount, OriginalTransactionID: rulesOriginalID})
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, utils.IsTransactionValidation(err), "got %v", err)
+				mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessTransaction_RollbackAlreadyCancelled(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}
+	existing := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 1000}
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+	mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{existing}, nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 1000, OriginalTransactionID: rulesOriginalID})
+	assert.True(t, utils.IsTransactionAlreadyCancelled(err))
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_RollbackSaveDuplicateRace(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Twice()
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+	mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{}, nil).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(fmt.Errorf("failed to save transaction: %w", repository.ErrDuplicate)).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 1000, OriginalTransactionID: rulesOriginalID})
+	assert.True(t, utils.IsTransactionAlreadyCancelled(err))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelTransaction(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000, RoundID: "round-1", GameID: "slots", ProviderID: "acme"}
+
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Times(2)
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+	mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{}, nil).Once()
+	mockRepo.On("Save", mock.MatchedBy(func(model *repo_model.TransactionModel) bool {
+		return model.TransactionType == "rollback" && model.Amount == 1000 && model.RoundID == "round-1" &&
+			model.OriginalTransactionID != nil && *model.OriginalTransactionID == rulesOriginalID && model.Reason == "provider outage"
+	})).Return(nil).Once()
+
+	result, err := useCase.CancelTransaction(&dto.CancelTransactionDTO{ID: rulesNewID, OriginalTransactionID: rulesOriginalID, Reason: "provider outage"})
+	assert.NoError(t, err)
+	assert.Equal(t, rulesNewID, result.ID)
+	assert.Equal(t, rulesUserID, result.UserID)
+	assert.Equal(t, "rollback", result.TransactionType)
+	assert.Equal(t, rulesOriginalID, result.OriginalTransactionID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCancelTransaction_GeneratesID(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "deposit", Amount: 500}
+
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Times(2)
+	mockRepo.On("GetByID", mock.AnythingOfType("string")).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+	mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{}, nil).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+
+	result, err := useCase.CancelTransaction(&dto.CancelTransactionDTO{OriginalTransactionID: rulesOriginalID})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.ID)
+	assert.NotEqual(t, rulesOriginalID, result.ID)
+}
+
+func TestCancelTransaction_UnknownOriginal(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	mockRepo.On("GetByID", rulesOriginalID).Return(nil, repository.ErrNotFound).Once()
+
+	_, err := useCase.CancelTransaction(&dto.CancelTransactionDTO{OriginalTransactionID: rulesOriginalID})
+	assert.True(t, utils.IsTransactionNotFound(err))
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestCancelTransaction_AlreadyCancelled(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "EUR", TransactionType: "bet", Amount: 1000}
+	existing := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: rulesUserID, Currency: "EUR", TransactionType: "rollback", Amount: 1000}
+
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Times(2)
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+	mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{existing}, nil).Once()
+
+	_, err := useCase.CancelTransaction(&dto.CancelTransactionDTO{ID: rulesNewID, OriginalTransactionID: rulesOriginalID})
+	assert.True(t, utils.IsTransactionAlreadyCancelled(err))
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestProcessTransaction_CreditTypesNeedNoState(t *testing.T) {
+	for _, transactionType := range []string{"deposit", "bonus_credit"} {
+		t.Run(transactionType, func(t *testing.T) {
+			mockRepo := &MockTransactionRepository{}
+			useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+			createDto := &dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: transactionType, Amount: 100}
+
+			mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+			mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+
+			assert.NoError(t, useCase.ProcessTransaction(createDto))
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProcessTransaction_DefaultsCurrency(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.MatchedBy(func(model *repo_model.TransactionModel) bool {
+		return model.Currency == "EUR"
+	})).Return(nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: "deposit", Amount: 100})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_StampsUTC(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	cest := time.FixedZone("CEST", 2*60*60)
+	mockRepo.On("GetByID", mock.AnythingOfType("string")).Return(nil, repository.ErrNotFound).Twice()
+	mockRepo.On("Save", mock.MatchedBy(func(model *repo_model.TransactionModel) bool {
+		return model.Timestamp.Location() == time.UTC
+	})).Return(nil).Twice()
+
+	assert.NoError(t, useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, TransactionType: "deposit", Amount: 100}))
+	assert.NoError(t, useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesOriginalID, UserID: rulesUserID, TransactionType: "deposit", Amount: 100, Timestamp: time.Date(2026, 10, 1, 0, 30, 0, 0, cest)}))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_WithdrawalUsesCurrencyBalance(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	totals := []*repo_model.TransactionTotalModel{
+		{Currency: "EUR", TransactionType: "deposit", Total: 100000, Count: 1},
+		{Currency: "BTC", TransactionType: "deposit", Total: 500, Count: 1},
+	}
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(totals, nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "BTC", TransactionType: "withdrawal", Amount: 501})
+	assert.True(t, utils.IsTransactionValidation(err))
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestProcessTransaction_RefundCurrencyMismatch(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "USD", TransactionType: "bet", Amount: 1000}
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "EUR", TransactionType: "refund", Amount: 100, OriginalTransactionID: rulesOriginalID})
+	assert.True(t, utils.IsTransactionValidation(err))
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestGetBalances(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	totals := []*repo_model.TransactionTotalModel{
+		{Currency: "EUR", TransactionType: "deposit", Total: 10000, Count: 1},
+		{Currency: "EUR", TransactionType: "bet", Total: 2550, Count: 3},
+		{Currency: "BTC", TransactionType: "deposit", Total: 150000000, Count: 1},
+		{Currency: "BTC", TransactionType: "rollback", OriginalTransactionType: "deposit", Total: 150000000, Count: 1},
+		{Currency: "USD", TransactionType: "bet", Total: 100, Count: 1},
+	}
+	mockRepo.On("GetTotals", rulesUserID).Return(totals, nil).Once()
+
+	balances, err := useCase.GetBalances(rulesUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, []*dto.BalanceDTO{
+		{Currency: "BTC", Balance: 0, Formatted: "0.00000000"},
+		{Currency: "EUR", Balance: 7450, Formatted: "74.50"},
+		{Currency: "USD", Balance: -100, Formatted: "-1.00"},
+	}, balances)
+}
+
+func TestGetBalances_RepositoryError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, assert.AnError).Once()
+
+	_, err := useCase.GetBalances(rulesUserID)
+	assert.Error(t, err)
+}
+
+func TestProcessTransaction_StoresReportingAmount(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{rates: map[string]string{"USD": "0.92"}}, nil)
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.MatchedBy(func(model *repo_model.TransactionModel) bool {
+		return model.ReportingAmount != nil && *model.ReportingAmount == 9200 &&
+			model.ExchangeRate != nil && *model.ExchangeRate == "0.92"
+	})).Return(nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "USD", TransactionType: "deposit", Amount: 10000})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_MissingRateLeavesUnconverted(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.MatchedBy(func(model *repo_model.TransactionModel) bool {
+		return model.ReportingAmount == nil && model.ExchangeRate == nil
+	})).Return(nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "BTC", TransactionType: "deposit", Amount: 100})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_ConverterError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{err: assert.AnError}, nil)
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "USD", TransactionType: "deposit", Amount: 100})
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestProcessTransaction_RollbackReusesOriginalRate(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{rates: map[string]string{"USD": "0.95"}}, nil)
+
+	originalRate := "0.92"
+	originalAmount := int64(920)
+	original := &repo_model.TransactionModel{ID: rulesOriginalID, UserID: rulesUserID, Currency: "USD", TransactionType: "bet", Amount: 1000, ReportingAmount: &originalAmount, ExchangeRate: &originalRate}
+
+	mockRepo.On("GetByID", rulesNewID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByID", rulesOriginalID).Return(original, nil).Once()
+	mockRepo.On("GetTotals", rulesUserID).Return(nil, nil).Once()
+	mockRepo.On("GetByOriginalID", rulesOriginalID).Return([]*repo_model.TransactionModel{}, nil).Once()
+	mockRepo.On("Save", mock.MatchedBy(func(model *repo_model.TransactionModel) bool {
+		return *model.ReportingAmount == 920 && *model.ExchangeRate == "0.92"
+	})).Return(nil).Once()
+
+	err := useCase.ProcessTransaction(&dto.CreateTransactionDTO{ID: rulesNewID, UserID: rulesUserID, Currency: "USD", TransactionType: "rollback", Amount: 1000, OriginalTransactionID: rulesOriginalID})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -4,43 +4,202 @@ import (
 	"casino/boundary/dto"
 	"casino/boundary/repo_model"
 	"casino/boundary/repository"
+	"casino/boundary/usecase"
+	"casino/domain/entity"
 	"casino/utils"
+	"context"
+	"errors"
 	"fmt"
+	"sort"
 )
 
 type TransactionUseCaseImpl struct {
 	transactionRepo repository.TransactionRepository
+	converter       usecase.CurrencyConverter
+	publisher       usecase.TransactionPublisher
 }
 
-func NewTransactionUseCaseImpl(transactionRepo repository.TransactionRepository) *TransactionUseCaseImpl {
+// NewTransactionUseCaseImpl creates the use case. publisher may be nil when
+// nothing listens for newly saved transactions.
+func NewTransactionUseCaseImpl(transactionRepo repository.TransactionRepository, converter usecase.CurrencyConverter, publisher usecase.TransactionPublisher) *TransactionUseCaseImpl {
 	return &TransactionUseCaseImpl{
 		transactionRepo: transactionRepo,
+		converter:       converter,
+		publisher:       publisher,
 	}
 }
 
 func (uc *TransactionUseCaseImpl) ProcessTransaction(dto *dto.CreateTransactionDTO) error {
-	existingTransaction, err := uc.transactionRepo.GetByID(dto.ID)
+	_, err := uc.process(dto)
+	return err
+}
+
+// CancelTransaction voids an earlier transaction by writing a rollback that
+// reverses its balance effect. The original row is never modified.
+func (uc *TransactionUseCaseImpl) CancelTransaction(cancel *dto.CancelTransactionDTO) (*dto.TransactionDTO, error) {
+	original, err := uc.transactionRepo.GetByID(cancel.OriginalTransactionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, &utils.TransactionNotFoundError{TransactionID: cancel.OriginalTransactionID}
+	}
 	if err != nil {
-		return fmt.Errorf("failed to check for existing transaction: %w", err)
+		return nil, fmt.Errorf("failed to get original transaction: %w", err)
 	}
 
-	if existingTransaction != nil {
-		return &utils.TransactionAlreadyExistsError{TransactionID: dto.ID}
+	id := cancel.ID
+	if id == "" {
+		id = utils.GenerateUUID()
+	}
+
+	rollback, err := uc.process(&dto.CreateTransactionDTO{
+		ID:                    id,
+		UserID:                original.UserID,
+		TransactionType:       string(entity.TransactionTypeRollback),
+		Amount:                original.Amount,
+		Currency:              original.Currency,
+		RoundID:               original.RoundID,
+		GameID:                original.GameID,
+		ProviderID:            original.ProviderID,
+		OriginalTransactionID: original.ID,
+		Reason:                cancel.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.TransactionDTO{}
+	result.FromEntity(rollback)
+	return result, nil
+}
+
+func (uc *TransactionUseCaseImpl) process(dto *dto.CreateTransactionDTO) (*entity.Transaction, error) {
+	if dto.Currency == "" {
+		dto.Currency = string(entity.DefaultCurrency)
+	}
+
+	if err := validateTransaction(dto); err != nil {
+		return nil, err
+	}
+
+	_, err := uc.transactionRepo.GetByID(dto.ID)
+	if err == nil {
+		return nil, &utils.TransactionAlreadyExistsError{TransactionID: dto.ID}
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to check for existing transaction: %w", err)
+	}
+
+	original, err := uc.checkRules(dto)
+	if err != nil {
+		return nil, err
 	}
 
 	entity := dto.ToEntity()
+	if err := uc.convert(entity, original); err != nil {
+		return nil, err
+	}
+
 	model := &repo_model.TransactionModel{}
 	model.FromEntity(entity)
-	return uc.transactionRepo.Save(model)
+	if err := uc.save(model, dto, original); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, uc.duplicate(dto, err)
+		}
+		if errors.Is(err, repository.ErrConstraint) {
+			return nil, invalidTransaction(dto, err.Error())
+		}
+		return nil, err
+	}
+
+	uc.publish(entity)
+	return entity, nil
+}
+
+// save stores the transaction, running the rules that depend on the user's
+// other transactions under the user's lock.
+func (uc *TransactionUseCaseImpl) save(model *repo_model.TransactionModel, dto *dto.CreateTransactionDTO, original *entity.Transaction) error {
+	switch transactionType := entity.TransactionType(dto.TransactionType); {
+	case transactionType == entity.TransactionTypeBet, transactionType == entity.TransactionTypeWithdrawal:
+		return uc.transactionRepo.SaveChecked(model, checkBalance(dto))
+	case transactionType.RequiresOriginal() && original != nil:
+		return uc.transactionRepo.SaveChecked(model, checkReversal(dto, original))
+	default:
+		return uc.transactionRepo.Save(model)
+	}
+}
+
+// duplicate explains a unique key violation from Save. It happens when a
+// concurrent request saved the same transaction, or another rollback of the
+// same original, between the checks in process and the insert.
+func (uc *TransactionUseCaseImpl) duplicate(dto *dto.CreateTransactionDTO, err error) error {
+	if _, getErr := uc.transactionRepo.GetByID(dto.ID); getErr == nil {
+		return &utils.TransactionAlreadyExistsError{TransactionID: dto.ID}
+	}
+	if entity.TransactionType(dto.TransactionType) == entity.TransactionTypeRollback && dto.OriginalTransactionID != "" {
+		return &utils.TransactionAlreadyCancelledError{TransactionID: dto.OriginalTransactionID}
+	}
+	return err
+}
+
+func (uc *TransactionUseCaseImpl) publish(transaction *entity.Transaction) {
+	if uc.publisher == nil {
+		return
+	}
+
+	saved := &dto.TransactionDTO{}
+	saved.FromEntity(transaction)
+	uc.publisher.Publish(saved)
+}
+
+// convert records the reporting currency amount together with the rate used.
+// Refunds and rollbacks reuse the rate of their original so that reversals
+// cancel out exactly in reports. A missing rate leaves the transaction
+// unconverted instead of rejecting it.
+func (uc *TransactionUseCaseImpl) convert(transaction *entity.Transaction, original *entity.Transaction) error {
+	if original != nil && original.ExchangeRate != "" {
+		amount, err := entity.ConvertAmount(transaction.Amount, transaction.Currency, original.ExchangeRate)
+		if err != nil {
+			return fmt.Errorf("failed to convert transaction %s: %w", transaction.ID, err)
+		}
+		transaction.ReportingAmount = &amount
+		transaction.ExchangeRate = original.ExchangeRate
+		return nil
+	}
+
+	conversion, err := uc.converter.Convert(transaction.Amount, string(transaction.Currency), transaction.Timestamp)
+	if utils.IsExchangeRateNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	transaction.ReportingAmount = &conversion.Amount
+	transaction.ExchangeRate = conversion.Rate
+	return nil
+}
+
+func (uc *TransactionUseCaseImpl) GetTransaction(id string) (*dto.TransactionDTO, error) {
+	model, err := uc.transactionRepo.GetByID(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, &utils.TransactionNotFoundError{TransactionID: id}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	transaction := &dto.TransactionDTO{}
+	transaction.FromEntity(model.ToEntity())
+	return transaction, nil
 }
 
 func (uc *TransactionUseCaseImpl) GetUserTransactions(userID string, filter *dto.TransactionFilterDTO) ([]*dto.TransactionDTO, error) {
-	var transactionType *string
-	if filter != nil && filter.TransactionType != nil {
+	var transactionType, currency *string
+	if filter != nil {
 		transactionType = filter.TransactionType
+		currency = filter.Currency
 	}
 
-	models, err := uc.transactionRepo.GetByUserID(userID, transactionType)
+	models, err := uc.transactionRepo.GetByUserID(userID, transactionType, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -55,13 +214,31 @@ func (uc *TransactionUseCaseImpl) GetUserTransactions(userID string, filter *dto
 	return dtos, nil
 }
 
+// GetReversals returns the refunds and rollbacks of a transaction, oldest
+// first.
+func (uc *TransactionUseCaseImpl) GetReversals(originalID string) ([]*dto.TransactionDTO, error) {
+	models, err := uc.transactionRepo.GetByOriginalID(originalID)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]*dto.TransactionDTO, len(models))
+	for i, model := range models {
+		dtos[i] = &dto.TransactionDTO{}
+		dtos[i].FromEntity(model.ToEntity())
+	}
+
+	return dtos, nil
+}
+
 func (uc *TransactionUseCaseImpl) GetAllTransactions(filter *dto.TransactionFilterDTO) ([]*dto.TransactionDTO, error) {
-	var transactionType *string
-	if filter != nil && filter.TransactionType != nil {
+	var transactionType, currency *string
+	if filter != nil {
 		transactionType = filter.TransactionType
+		currency = filter.Currency
 	}
 
-	models, err := uc.transactionRepo.GetAll(transactionType)
+	models, err := uc.transactionRepo.GetAll(transactionType, currency)
 	if err != nil {
 		return nil, err
 	}
@@ -75,3 +252,124 @@ func (uc *TransactionUseCaseImpl) GetAllTransactions(filter *dto.TransactionFilt
 
 	return dtos, nil
 }
+
+// ExportTransactions hands every matching transaction to fn one at a time so
+// that exports of any size run in constant memory.
+func (uc *TransactionUseCaseImpl) ExportTransactions(ctx context.Context, userID *string, filter *dto.TransactionFilterDTO, fn func(*dto.TransactionDTO) error) error {
+	var transactionType, currency *string
+	if filter != nil {
+		transactionType = filter.TransactionType
+		currency = filter.Currency
+	}
+
+	return uc.transactionRepo.Stream(ctx, userID, transactionType, currency, func(model *repo_model.TransactionModel) error {
+		transaction := &dto.TransactionDTO{}
+		transaction.FromEntity(model.ToEntity())
+		return fn(transaction)
+	})
+}
+
+func (uc *TransactionUseCaseImpl) GetRound(roundID string) (*dto.RoundDTO, error) {
+	models, err := uc.transactionRepo.GetByRoundID(roundID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(models) == 0 {
+		return nil, &utils.RoundNotFoundError{RoundID: roundID}
+	}
+
+	round := &dto.RoundDTO{
+		RoundID:    roundID,
+		GameID:     models[0].GameID,
+		ProviderID: models[0].ProviderID,
+		UserID:     models[0].UserID,
+		Currency:   models[0].Currency,
+		Bets:       []*dto.TransactionDTO{},
+		Wins:       []*dto.TransactionDTO{},
+	}
+
+	byID := make(map[string]*repo_model.TransactionModel, len(models))
+	for _, model := range models {
+		byID[model.ID] = model
+	}
+
+	// Rollbacks are netted against the type of the transaction they reverse
+	// and refunds against the bet they return, as in the GGR reports.
+	var totalBet, totalWin int64
+	for _, model := range models {
+		transaction := &dto.TransactionDTO{}
+		transaction.FromEntity(model.ToEntity())
+
+		switch entity.TransactionType(model.TransactionType) {
+		case entity.TransactionTypeBet:
+			round.Bets = append(round.Bets, transaction)
+			totalBet += int64(model.Amount)
+		case entity.TransactionTypeWin:
+			round.Wins = append(round.Wins, transaction)
+			totalWin += int64(model.Amount)
+		case entity.TransactionTypeRefund, entity.TransactionTypeRollback:
+			original, err := uc.reversedInRound(byID, model.OriginalTransactionID)
+			if err != nil {
+				return nil, err
+			}
+			if original == nil {
+				continue
+			}
+			switch entity.TransactionType(original.TransactionType) {
+			case entity.TransactionTypeBet:
+				totalBet -= int64(model.Amount)
+			case entity.TransactionTypeWin:
+				if entity.TransactionType(model.TransactionType) == entity.TransactionTypeRollback {
+					totalWin -= int64(model.Amount)
+				}
+			}
+		}
+	}
+	round.TotalBet = uint(max(totalBet, 0))
+	round.TotalWin = uint(max(totalWin, 0))
+	round.NetResult = int64(round.TotalWin) - int64(round.TotalBet)
+
+	return round, nil
+}
+
+// reversedInRound returns the transaction a refund or rollback reverses,
+// looking in the round first. It returns nil when there is no original.
+func (uc *TransactionUseCaseImpl) reversedInRound(round map[string]*repo_model.TransactionModel, originalID *string) (*repo_model.TransactionModel, error) {
+	if originalID == nil || *originalID == "" {
+		return nil, nil
+	}
+	if original, ok := round[*originalID]; ok {
+		return original, nil
+	}
+
+	original, err := uc.transactionRepo.GetByID(*originalID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	return original, err
+}
+
+func (uc *TransactionUseCaseImpl) GetBalances(userID string) ([]*dto.BalanceDTO, error) {
+	balances, err := uc.balances(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currencies := make([]string, 0, len(balances))
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	dtos := make([]*dto.BalanceDTO, len(currencies))
+	for i, currency := range currencies {
+		dtos[i] = &dto.BalanceDTO{
+			Currency:  currency,
+			Balance:   balances[currency],
+			Formatted: entity.Currency(currency).FormatAmount(balances[currency]),
+		}
+	}
+
+	return dtos, nil
+}
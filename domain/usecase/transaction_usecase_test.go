@@ -1,20 +1,51 @@
 package usecase
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
 	"casino/boundary/dto"
 	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/domain/entity"
 	"casino/utils"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+// MockCurrencyConverter converts at the rates it holds. EUR always converts
+// at 1 and any other currency without a rate has no rate available.
+type MockCurrencyConverter struct {
+	rates map[string]string
+	err   error
+}
+
+func (m *MockCurrencyConverter) Convert(amount uint, currency string, at time.Time) (*dto.ConversionDTO, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	rate, ok := m.rates[currency]
+	if currency == "EUR" {
+		rate, ok = "1", true
+	}
+	if !ok {
+		return nil, &utils.ExchangeRateNotFoundError{Currency: currency, At: at}
+	}
+
+	converted, err := entity.ConvertAmount(amount, entity.Currency(currency), rate)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ConversionDTO{Amount: converted, Rate: rate}, nil
+}
+
 type MockTransactionRepository struct {
 	mock.Mock
+	checkedSaves int
 }
 
 func (m *MockTransactionRepository) Save(transaction *repo_model.TransactionModel) error {
@@ -22,6 +53,33 @@ func (m *MockTransactionRepository) Save(transaction *repo_model.TransactionMode
 	return args.Error(0)
 }
 
+func (m *MockTransactionRepository) SaveBatch(transactions []*repo_model.TransactionModel) error {
+	args := m.Called(transactions)
+	return args.Error(0)
+}
+
+// SaveChecked runs check against the mocked GetTotals and GetByOriginalID and
+// then calls the mocked Save, counting the saves it made.
+func (m *MockTransactionRepository) SaveChecked(transaction *repo_model.TransactionModel, check func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error) error {
+	totals, err := m.GetTotals(transaction.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get transaction totals: %w", err)
+	}
+
+	var linked []*repo_model.TransactionModel
+	if transaction.OriginalTransactionID != nil {
+		if linked, err = m.GetByOriginalID(*transaction.OriginalTransactionID); err != nil {
+			return fmt.Errorf("failed to get linked transactions: %w", err)
+		}
+	}
+
+	if err := check(totals, linked); err != nil {
+		return err
+	}
+	m.checkedSaves++
+	return m.Save(transaction)
+}
+
 func (m *MockTransactionRepository) GetByID(id string) (*repo_model.TransactionModel, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
@@ -30,31 +88,67 @@ func (m *MockTransactionRepository) GetByID(id string) (*repo_model.TransactionM
 	return args.Get(0).(*repo_model.TransactionModel), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetByUserID(userID string, transactionType *string) ([]*repo_model.TransactionModel, error) {
-	args := m.Called(userID, transactionType)
+func (m *MockTransactionRepository) GetByUserID(userID string, transactionType, currency *string) ([]*repo_model.TransactionModel, error) {
+	args := m.Called(userID, transactionType, currency)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.TransactionModel), args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetAll(transactionType, currency *string) ([]*repo_model.TransactionModel, error) {
+	args := m.Called(transactionType, currency)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*repo_model.TransactionModel), args.Error(1)
 }
 
-func (m *MockTransactionRepository) GetAll(transactionType *string) ([]*repo_model.TransactionModel, error) {
-	args := m.Called(transactionType)
+func (m *MockTransactionRepository) GetByRoundID(roundID string) ([]*repo_model.TransactionModel, error) {
+	args := m.Called(roundID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).([]*repo_model.TransactionModel), args.Error(1)
 }
 
+func (m *MockTransactionRepository) GetByOriginalID(originalID string) ([]*repo_model.TransactionModel, error) {
+	args := m.Called(originalID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.TransactionModel), args.Error(1)
+}
+
+func (m *MockTransactionRepository) Stream(ctx context.Context, userID, transactionType, currency *string, fn func(*repo_model.TransactionModel) error) error {
+	args := m.Called(ctx, userID, transactionType, currency)
+	if models, ok := args.Get(0).([]*repo_model.TransactionModel); ok {
+		for _, model := range models {
+			if err := fn(model); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) GetTotals(userID string) ([]*repo_model.TransactionTotalModel, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*repo_model.TransactionTotalModel), args.Error(1)
+}
+
 func TestProcessTransaction_Idempotency(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	transactionID := "550e8400-e29b-41d4-a716-446655440001"
 	createDto := &dto.CreateTransactionDTO{
 		ID:              transactionID,
 		UserID:          "550e8400-e29b-41d4-a716-446655440010",
-		TransactionType: "bet",
+		TransactionType: "deposit",
 		Amount:          1000,
 	}
 
@@ -62,7 +156,7 @@ func TestProcessTransaction_Idempotency(t *testing.T) {
 	expectedModel := &repo_model.TransactionModel{}
 	expectedModel.FromEntity(expectedEntity)
 
-	mockRepo.On("GetByID", transactionID).Return(nil, nil).Once()
+	mockRepo.On("GetByID", transactionID).Return(nil, repository.ErrNotFound).Once()
 	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
 
 	err := useCase.ProcessTransaction(createDto)
@@ -93,12 +187,12 @@ func TestIsTransactionAlreadyExists(t *testing.T) {
 
 func TestProcessTransaction_ErrorHandling(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	createDto := &dto.CreateTransactionDTO{
 		ID:              "550e8400-e29b-41d4-a716-446655440001",
 		UserID:          "550e8400-e29b-41d4-a716-446655440010",
-		TransactionType: "bet",
+		TransactionType: "deposit",
 		Amount:          1000,
 	}
 
@@ -113,16 +207,16 @@ func TestProcessTransaction_ErrorHandling(t *testing.T) {
 
 func TestProcessTransaction_SaveError(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	createDto := &dto.CreateTransactionDTO{
 		ID:              "550e8400-e29b-41d4-a716-446655440001",
 		UserID:          "550e8400-e29b-41d4-a716-446655440010",
-		TransactionType: "bet",
+		TransactionType: "deposit",
 		Amount:          1000,
 	}
 
-	mockRepo.On("GetByID", createDto.ID).Return(nil, nil).Once()
+	mockRepo.On("GetByID", createDto.ID).Return(nil, repository.ErrNotFound).Once()
 	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(assert.AnError).Once()
 
 	err := useCase.ProcessTransaction(createDto)
@@ -132,9 +226,157 @@ func TestProcessTransaction_SaveError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProcessTransaction_SaveConstraintViolation(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	createDto := &dto.CreateTransactionDTO{
+		ID:              "550e8400-e29b-41d4-a716-446655440001",
+		UserID:          "550e8400-e29b-41d4-a716-446655440010",
+		TransactionType: "deposit",
+		Amount:          1000,
+	}
+
+	mockRepo.On("GetByID", createDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(fmt.Errorf("failed to save transaction: %w", repository.ErrConstraint)).Once()
+
+	err := useCase.ProcessTransaction(createDto)
+	assert.True(t, utils.IsTransactionValidation(err))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_SaveDuplicateRace(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	createDto := &dto.CreateTransactionDTO{
+		ID:              "550e8400-e29b-41d4-a716-446655440001",
+		UserID:          "550e8400-e29b-41d4-a716-446655440010",
+		TransactionType: "deposit",
+		Amount:          1000,
+	}
+	saved := &repo_model.TransactionModel{ID: createDto.ID, UserID: createDto.UserID, TransactionType: "deposit", Amount: 1000}
+
+	mockRepo.On("GetByID", createDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(fmt.Errorf("failed to save transaction: %w", repository.ErrDuplicate)).Once()
+	mockRepo.On("GetByID", createDto.ID).Return(saved, nil).Once()
+
+	err := useCase.ProcessTransaction(createDto)
+	assert.True(t, utils.IsTransactionAlreadyExists(err))
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_SaveDuplicateNotFound(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	createDto := &dto.CreateTransactionDTO{
+		ID:              "550e8400-e29b-41d4-a716-446655440001",
+		UserID:          "550e8400-e29b-41d4-a716-446655440010",
+		TransactionType: "deposit",
+		Amount:          1000,
+	}
+	saveErr := fmt.Errorf("failed to save transaction: %w", repository.ErrDuplicate)
+
+	mockRepo.On("GetByID", createDto.ID).Return(nil, repository.ErrNotFound).Twice()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(saveErr).Once()
+
+	err := useCase.ProcessTransaction(createDto)
+	assert.Equal(t, saveErr, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+type MockTransactionPublisher struct {
+	published []*dto.TransactionDTO
+}
+
+func (m *MockTransactionPublisher) Publish(transaction *dto.TransactionDTO) {
+	m.published = append(m.published, transaction)
+}
+
+func TestProcessTransaction_PublishesSavedTransaction(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	publisher := &MockTransactionPublisher{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, publisher)
+
+	createDto := &dto.CreateTransactionDTO{
+		ID:              "550e8400-e29b-41d4-a716-446655440001",
+		UserID:          "550e8400-e29b-41d4-a716-446655440010",
+		TransactionType: "deposit",
+		Amount:          1000,
+	}
+
+	mockRepo.On("GetByID", createDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(assert.AnError).Once()
+
+	assert.Error(t, useCase.ProcessTransaction(createDto))
+	assert.Empty(t, publisher.published)
+
+	mockRepo.On("GetByID", createDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+
+	assert.NoError(t, useCase.ProcessTransaction(createDto))
+	if assert.Len(t, publisher.published, 1) {
+		assert.Equal(t, createDto.ID, publisher.published[0].ID)
+		assert.Equal(t, uint(1000), publisher.published[0].Amount)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTransaction(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	model := &repo_model.TransactionModel{ID: "tx-1", UserID: "user-1", TransactionType: "bet", Amount: 500, Currency: "EUR"}
+	mockRepo.On("GetByID", "tx-1").Return(model, nil).Once()
+	mockRepo.On("GetByID", "tx-2").Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByID", "tx-3").Return(nil, assert.AnError).Once()
+
+	transaction, err := useCase.GetTransaction("tx-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", transaction.UserID)
+	assert.Equal(t, uint(500), transaction.Amount)
+
+	_, err = useCase.GetTransaction("tx-2")
+	assert.True(t, utils.IsTransactionNotFound(err))
+
+	_, err = useCase.GetTransaction("tx-3")
+	assert.Equal(t, assert.AnError, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetReversals(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	originalID := "tx-1"
+	models := []*repo_model.TransactionModel{
+		{ID: "tx-2", UserID: "user-1", TransactionType: "refund", Amount: 200, Currency: "EUR", OriginalTransactionID: &originalID},
+		{ID: "tx-3", UserID: "user-1", TransactionType: "rollback", Amount: 300, Currency: "EUR", OriginalTransactionID: &originalID},
+	}
+	mockRepo.On("GetByOriginalID", "tx-1").Return(models, nil).Once()
+	mockRepo.On("GetByOriginalID", "tx-4").Return(nil, assert.AnError).Once()
+
+	reversals, err := useCase.GetReversals("tx-1")
+	assert.NoError(t, err)
+	assert.Len(t, reversals, 2)
+	assert.Equal(t, "rollback", reversals[1].TransactionType)
+	assert.Equal(t, "tx-1", reversals[1].OriginalTransactionID)
+
+	_, err = useCase.GetReversals("tx-4")
+	assert.Equal(t, assert.AnError, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetUserTransactions_Success(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	userID := "550e8400-e29b-41d4-a716-446655440010"
 	models := []*repo_model.TransactionModel{
@@ -154,7 +396,7 @@ func TestGetUserTransactions_Success(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("GetByUserID", userID, (*string)(nil)).Return(models, nil).Once()
+	mockRepo.On("GetByUserID", userID, (*string)(nil), (*string)(nil)).Return(models, nil).Once()
 
 	dtos, err := useCase.GetUserTransactions(userID, nil)
 	assert.NoError(t, err)
@@ -167,7 +409,7 @@ func TestGetUserTransactions_Success(t *testing.T) {
 
 func TestGetUserTransactions_WithFilter(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	userID := "550e8400-e29b-41d4-a716-446655440010"
 	transactionType := "bet"
@@ -185,7 +427,7 @@ func TestGetUserTransactions_WithFilter(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("GetByUserID", userID, &transactionType).Return(models, nil).Once()
+	mockRepo.On("GetByUserID", userID, &transactionType, (*string)(nil)).Return(models, nil).Once()
 
 	dtos, err := useCase.GetUserTransactions(userID, filter)
 	assert.NoError(t, err)
@@ -198,11 +440,11 @@ func TestGetUserTransactions_WithFilter(t *testing.T) {
 
 func TestGetUserTransactions_EmptyResult(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	userID := "550e8400-e29b-41d4-a716-446655440010"
 
-	mockRepo.On("GetByUserID", userID, (*string)(nil)).Return([]*repo_model.TransactionModel{}, nil).Once()
+	mockRepo.On("GetByUserID", userID, (*string)(nil), (*string)(nil)).Return([]*repo_model.TransactionModel{}, nil).Once()
 
 	dtos, err := useCase.GetUserTransactions(userID, nil)
 	assert.NoError(t, err)
@@ -213,11 +455,11 @@ func TestGetUserTransactions_EmptyResult(t *testing.T) {
 
 func TestGetUserTransactions_RepositoryError(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	userID := "550e8400-e29b-41d4-a716-446655440010"
 
-	mockRepo.On("GetByUserID", userID, (*string)(nil)).Return(nil, assert.AnError).Once()
+	mockRepo.On("GetByUserID", userID, (*string)(nil), (*string)(nil)).Return(nil, assert.AnError).Once()
 
 	dtos, err := useCase.GetUserTransactions(userID, nil)
 	assert.Error(t, err)
@@ -228,7 +470,7 @@ func TestGetUserTransactions_RepositoryError(t *testing.T) {
 
 func TestGetAllTransactions_Success(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	models := []*repo_model.TransactionModel{
 		{
@@ -247,7 +489,7 @@ func TestGetAllTransactions_Success(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("GetAll", (*string)(nil)).Return(models, nil).Once()
+	mockRepo.On("GetAll", (*string)(nil), (*string)(nil)).Return(models, nil).Once()
 
 	dtos, err := useCase.GetAllTransactions(nil)
 	assert.NoError(t, err)
@@ -260,7 +502,7 @@ func TestGetAllTransactions_Success(t *testing.T) {
 
 func TestGetAllTransactions_WithFilter(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	transactionType := "win"
 	filter := &dto.TransactionFilterDTO{
@@ -277,7 +519,7 @@ func TestGetAllTransactions_WithFilter(t *testing.T) {
 		},
 	}
 
-	mockRepo.On("GetAll", &transactionType).Return(models, nil).Once()
+	mockRepo.On("GetAll", &transactionType, (*string)(nil)).Return(models, nil).Once()
 
 	dtos, err := useCase.GetAllTransactions(filter)
 	assert.NoError(t, err)
@@ -290,9 +532,9 @@ func TestGetAllTransactions_WithFilter(t *testing.T) {
 
 func TestGetAllTransactions_EmptyResult(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
-	mockRepo.On("GetAll", (*string)(nil)).Return([]*repo_model.TransactionModel{}, nil).Once()
+	mockRepo.On("GetAll", (*string)(nil), (*string)(nil)).Return([]*repo_model.TransactionModel{}, nil).Once()
 
 	dtos, err := useCase.GetAllTransactions(nil)
 	assert.NoError(t, err)
@@ -303,9 +545,9 @@ func TestGetAllTransactions_EmptyResult(t *testing.T) {
 
 func TestGetAllTransactions_RepositoryError(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
-	mockRepo.On("GetAll", (*string)(nil)).Return(nil, assert.AnError).Once()
+	mockRepo.On("GetAll", (*string)(nil), (*string)(nil)).Return(nil, assert.AnError).Once()
 
 	dtos, err := useCase.GetAllTransactions(nil)
 	assert.Error(t, err)
@@ -316,7 +558,7 @@ func TestGetAllTransactions_RepositoryError(t *testing.T) {
 
 func TestNewTransactionUseCaseImpl(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	assert.NotNil(t, useCase)
 	assert.Equal(t, mockRepo, useCase.transactionRepo)
@@ -324,7 +566,7 @@ func TestNewTransactionUseCaseImpl(t *testing.T) {
 
 func TestGetUserTransactions_DataConversion(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	userID := "550e8400-e29b-41d4-a716-446655440010"
 	model := &repo_model.TransactionModel{
@@ -337,7 +579,7 @@ func TestGetUserTransactions_DataConversion(t *testing.T) {
 
 	models := []*repo_model.TransactionModel{model}
 
-	mockRepo.On("GetByUserID", userID, (*string)(nil)).Return(models, nil).Once()
+	mockRepo.On("GetByUserID", userID, (*string)(nil), (*string)(nil)).Return(models, nil).Once()
 
 	dtos, err := useCase.GetUserTransactions(userID, nil)
 	assert.NoError(t, err)
@@ -355,7 +597,7 @@ func TestGetUserTransactions_DataConversion(t *testing.T) {
 
 func TestGetAllTransactions_DataConversion(t *testing.T) {
 	mockRepo := &MockTransactionRepository{}
-	useCase := NewTransactionUseCaseImpl(mockRepo)
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
 
 	model := &repo_model.TransactionModel{
 		ID:              "550e8400-e29b-41d4-a716-446655440001",
@@ -367,7 +609,7 @@ func TestGetAllTransactions_DataConversion(t *testing.T) {
 
 	models := []*repo_model.TransactionModel{model}
 
-	mockRepo.On("GetAll", (*string)(nil)).Return(models, nil).Once()
+	mockRepo.On("GetAll", (*string)(nil), (*string)(nil)).Return(models, nil).Once()
 
 	dtos, err := useCase.GetAllTransactions(nil)
 	assert.NoError(t, err)
@@ -382,3 +624,221 @@ func TestGetAllTransactions_DataConversion(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestProcessTransaction_Validation(t *testing.T) {
+	testCases := []struct {
+		name string
+		dto  *dto.CreateTransactionDTO
+	}{
+		{"Missing ID", &dto.CreateTransactionDTO{UserID: "user", TransactionType: "bet", Amount: 100}},
+		{"Missing UserID", &dto.CreateTransactionDTO{ID: "id", TransactionType: "bet", Amount: 100}},
+		{"Zero Amount", &dto.CreateTransactionDTO{ID: "id", UserID: "user", TransactionType: "bet", Amount: 0}},
+		{"Unknown Type", &dto.CreateTransactionDTO{ID: "id", UserID: "user", TransactionType: "jackpot", Amount: 100}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := &MockTransactionRepository{}
+			useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+			err := useCase.ProcessTransaction(tc.dto)
+			assert.Error(t, err)
+			assert.True(t, utils.IsTransactionValidation(err))
+
+			mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+			mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+		})
+	}
+}
+
+func TestProcessTransaction_WinRequiresBetInRound(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	userID := "550e8400-e29b-41d4-a716-446655440010"
+	winDto := &dto.CreateTransactionDTO{
+		ID:              "550e8400-e29b-41d4-a716-446655440002",
+		UserID:          userID,
+		TransactionType: "win",
+		Amount:          2500,
+		RoundID:         "round-1",
+	}
+
+	mockRepo.On("GetByID", winDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByRoundID", "round-1").Return([]*repo_model.TransactionModel{}, nil).Once()
+
+	err := useCase.ProcessTransaction(winDto)
+	assert.Error(t, err)
+	assert.True(t, utils.IsTransactionValidation(err))
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything)
+
+	otherUserBet := &repo_model.TransactionModel{ID: "bet-other", UserID: "someone-else", TransactionType: "bet", Amount: 100, RoundID: "round-1"}
+	mockRepo.On("GetByID", winDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByRoundID", "round-1").Return([]*repo_model.TransactionModel{otherUserBet}, nil).Once()
+
+	err = useCase.ProcessTransaction(winDto)
+	assert.True(t, utils.IsTransactionValidation(err))
+
+	otherCurrencyBet := &repo_model.TransactionModel{ID: "bet-usd", UserID: userID, TransactionType: "bet", Amount: 100, Currency: "USD", RoundID: "round-1"}
+	mockRepo.On("GetByID", winDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByRoundID", "round-1").Return([]*repo_model.TransactionModel{otherCurrencyBet}, nil).Once()
+
+	err = useCase.ProcessTransaction(winDto)
+	assert.True(t, utils.IsTransactionValidation(err))
+
+	bet := &repo_model.TransactionModel{ID: "bet-1", UserID: userID, TransactionType: "bet", Amount: 1000, Currency: "EUR", RoundID: "round-1"}
+	mockRepo.On("GetByID", winDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("GetByRoundID", "round-1").Return([]*repo_model.TransactionModel{bet}, nil).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+
+	err = useCase.ProcessTransaction(winDto)
+	assert.NoError(t, err)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProcessTransaction_WinWithoutRoundSkipsRoundCheck(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	winDto := &dto.CreateTransactionDTO{
+		ID:              "550e8400-e29b-41d4-a716-446655440002",
+		UserID:          "550e8400-e29b-41d4-a716-446655440010",
+		TransactionType: "win",
+		Amount:          2500,
+	}
+
+	mockRepo.On("GetByID", winDto.ID).Return(nil, repository.ErrNotFound).Once()
+	mockRepo.On("Save", mock.AnythingOfType("*repo_model.TransactionModel")).Return(nil).Once()
+
+	err := useCase.ProcessTransaction(winDto)
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "GetByRoundID", mock.Anything)
+}
+
+func TestGetRound_Success(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	userID := "550e8400-e29b-41d4-a716-446655440010"
+	models := []*repo_model.TransactionModel{
+		{ID: "bet-1", UserID: userID, TransactionType: "bet", Amount: 1000, RoundID: "round-1", GameID: "starburst", ProviderID: "netent", Timestamp: time.Now()},
+		{ID: "win-1", UserID: userID, TransactionType: "win", Amount: 400, RoundID: "round-1", GameID: "starburst", ProviderID: "netent", Timestamp: time.Now()},
+		{ID: "win-2", UserID: userID, TransactionType: "win", Amount: 100, RoundID: "round-1", GameID: "starburst", ProviderID: "netent", Timestamp: time.Now()},
+	}
+
+	mockRepo.On("GetByRoundID", "round-1").Return(models, nil).Once()
+
+	round, err := useCase.GetRound("round-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "starburst", round.GameID)
+	assert.Equal(t, "netent", round.ProviderID)
+	assert.Len(t, round.Bets, 1)
+	assert.Len(t, round.Wins, 2)
+	assert.Equal(t, uint(1000), round.TotalBet)
+	assert.Equal(t, uint(500), round.TotalWin)
+	assert.Equal(t, int64(-500), round.NetResult)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRound_NetsReversals(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	userID := "550e8400-e29b-41d4-a716-446655440010"
+	refundedBet, rolledBackWin, earlierBet := "bet-2", "win-1", "bet-0"
+	models := []*repo_model.TransactionModel{
+		{ID: "bet-1", UserID: userID, TransactionType: "bet", Amount: 1000, RoundID: "round-1", Timestamp: time.Now()},
+		{ID: "bet-2", UserID: userID, TransactionType: "bet", Amount: 300, RoundID: "round-1", Timestamp: time.Now()},
+		{ID: "win-1", UserID: userID, TransactionType: "win", Amount: 400, RoundID: "round-1", Timestamp: time.Now()},
+		{ID: "refund-1", UserID: userID, TransactionType: "refund", Amount: 300, RoundID: "round-1", OriginalTransactionID: &refundedBet, Timestamp: time.Now()},
+		{ID: "rollback-1", UserID: userID, TransactionType: "rollback", Amount: 400, RoundID: "round-1", OriginalTransactionID: &rolledBackWin, Timestamp: time.Now()},
+		{ID: "rollback-2", UserID: userID, TransactionType: "rollback", Amount: 200, RoundID: "round-1", OriginalTransactionID: &earlierBet, Timestamp: time.Now()},
+	}
+
+	mockRepo.On("GetByRoundID", "round-1").Return(models, nil).Once()
+	mockRepo.On("GetByID", "bet-0").Return(&repo_model.TransactionModel{ID: "bet-0", TransactionType: "bet", Amount: 200}, nil).Once()
+
+	round, err := useCase.GetRound("round-1")
+	assert.NoError(t, err)
+	assert.Len(t, round.Bets, 2)
+	assert.Len(t, round.Wins, 1)
+	assert.Equal(t, uint(800), round.TotalBet)
+	assert.Equal(t, uint(0), round.TotalWin)
+	assert.Equal(t, int64(-800), round.NetResult)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetRound_NotFound(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	mockRepo.On("GetByRoundID", "missing").Return([]*repo_model.TransactionModel{}, nil).Once()
+
+	round, err := useCase.GetRound("missing")
+	assert.Nil(t, round)
+	assert.True(t, utils.IsRoundNotFound(err))
+}
+
+func TestGetRound_RepositoryError(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	mockRepo.On("GetByRoundID", "round-1").Return(nil, assert.AnError).Once()
+
+	round, err := useCase.GetRound("round-1")
+	assert.Nil(t, round)
+	assert.Equal(t, assert.AnError, err)
+}
+
+func TestExportTransactions_Success(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	userID := "550e8400-e29b-41d4-a716-446655440010"
+	currency := "USD"
+	models := []*repo_model.TransactionModel{
+		{ID: "550e8400-e29b-41d4-a716-446655440001", UserID: userID, TransactionType: "bet", Amount: 1000, Currency: "USD", Timestamp: time.Now()},
+		{ID: "550e8400-e29b-41d4-a716-446655440002", UserID: userID, TransactionType: "win", Amount: 2000, Currency: "USD", Timestamp: time.Now()},
+	}
+
+	ctx := context.Background()
+	mockRepo.On("Stream", ctx, &userID, (*string)(nil), &currency).Return(models, nil).Once()
+
+	var exported []*dto.TransactionDTO
+	err := useCase.ExportTransactions(ctx, &userID, &dto.TransactionFilterDTO{Currency: &currency}, func(transaction *dto.TransactionDTO) error {
+		exported = append(exported, transaction)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, exported, 2)
+	assert.Equal(t, models[1].ID, exported[1].ID)
+	assert.Equal(t, "USD", exported[1].Currency)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExportTransactions_CallbackErrorStops(t *testing.T) {
+	mockRepo := &MockTransactionRepository{}
+	useCase := NewTransactionUseCaseImpl(mockRepo, &MockCurrencyConverter{}, nil)
+
+	models := []*repo_model.TransactionModel{
+		{ID: "550e8400-e29b-41d4-a716-446655440001", TransactionType: "bet", Amount: 1000, Timestamp: time.Now()},
+		{ID: "550e8400-e29b-41d4-a716-446655440002", TransactionType: "bet", Amount: 1000, Timestamp: time.Now()},
+	}
+
+	ctx := context.Background()
+	mockRepo.On("Stream", ctx, (*string)(nil), (*string)(nil), (*string)(nil)).Return(models, nil).Once()
+
+	calls := 0
+	writeErr := fmt.Errorf("client went away")
+	err := useCase.ExportTransactions(ctx, nil, nil, func(transaction *dto.TransactionDTO) error {
+		calls++
+		return writeErr
+	})
+
+	assert.Equal(t, writeErr, err)
+	assert.Equal(t, 1, calls)
+}
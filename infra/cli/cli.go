@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"casino/boundary/usecase"
+)
+
+// CLI runs the maintenance commands that are invoked as
+// `casino <command> [flags]` instead of starting the server.
+type CLI struct {
+	reportUseCase usecase.ReportUseCase
+	importUseCase usecase.ImportUseCase
+	replayer      Replayer
+	migrator      Migrator
+	out           io.Writer
+}
+
+func NewCLI(reportUseCase usecase.ReportUseCase, importUseCase usecase.ImportUseCase, replayer Replayer, migrator Migrator, out io.Writer) *CLI {
+	return &CLI{
+		reportUseCase: reportUseCase,
+		importUseCase: importUseCase,
+		replayer:      replayer,
+		migrator:      migrator,
+		out:           out,
+	}
+}
+
+func (c *CLI) Run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command given")
+	}
+
+	switch args[0] {
+	case "backfill-aggregates":
+		return c.backfillAggregates(args[1:])
+	case "import":
+		return c.importTransactions(args[1:])
+	case "replay":
+		return c.replay(args[1:])
+	case "migrate":
+		return c.migrate(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// backfillAggregates rebuilds the daily aggregates one day at a time so that a
+// long range does not hold a single huge database transaction.
+func (c *CLI) backfillAggregates(args []string) error {
+	flags := flag.NewFlagSet("backfill-aggregates", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	fromValue := flags.String("from", "", "first day to rebuild (YYYY-MM-DD)")
+	toValue := flags.String("to", "", "last day to rebuild, inclusive (YYYY-MM-DD)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	from, err := time.Parse(time.DateOnly, *fromValue)
+	if err != nil {
+		return fmt.Errorf("-from must be a date (YYYY-MM-DD)")
+	}
+
+	to, err := time.Parse(time.DateOnly, *toValue)
+	if err != nil {
+		return fmt.Errorf("-to must be a date (YYYY-MM-DD)")
+	}
+
+	if to.Before(from) {
+		return fmt.Errorf("-to must not be before -from")
+	}
+
+	var total int64
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		written, err := c.reportUseCase.RebuildDailyAggregates(day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", day.Format(time.DateOnly), err)
+		}
+		total += written
+		fmt.Fprintf(c.out, "%s: %d aggregate rows\n", day.Format(time.DateOnly), written)
+	}
+
+	fmt.Fprintf(c.out, "rebuilt %d aggregate rows from %s to %s\n", total, from.Format(time.DateOnly), to.Format(time.DateOnly))
+	return nil
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+)
+
+type MockReportUseCase struct {
+	rebuilt []time.Time
+	err     error
+}
+
+func (m *MockReportUseCase) GetGGR(filter *dto.GGRFilterDTO) (*dto.GGRReportDTO, error) {
+	return nil, nil
+}
+
+func (m *MockReportUseCase) GetUserStats(userID string) (*dto.UserStatsDTO, error) {
+	return nil, nil
+}
+
+func (m *MockReportUseCase) RebuildDailyAggregates(from, to time.Time) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.rebuilt = append(m.rebuilt, from)
+	if !to.Equal(from.AddDate(0, 0, 1)) {
+		return 0, errors.New("expected a single day")
+	}
+	return 3, nil
+}
+
+func TestCLI_BackfillAggregates(t *testing.T) {
+	mockUseCase := &MockReportUseCase{}
+	out := &bytes.Buffer{}
+
+	err := NewCLI(mockUseCase, nil, nil, nil, out).Run([]string{"backfill-aggregates", "-from", "2026-02-27", "-to", "2026-03-01"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockUseCase.rebuilt) != 3 {
+		t.Fatalf("Expected 3 days to be rebuilt, got %d", len(mockUseCase.rebuilt))
+	}
+
+	if !mockUseCase.rebuilt[2].Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the last day to be 2026-03-01, got %s", mockUseCase.rebuilt[2])
+	}
+
+	if !strings.Contains(out.String(), "rebuilt 9 aggregate rows from 2026-02-27 to 2026-03-01") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestCLI_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+		err  error
+	}{
+		{"No Command", nil, nil},
+		{"Unknown Command", []string{"migrate"}, nil},
+		{"Missing From", []string{"backfill-aggregates", "-to", "2026-03-01"}, nil},
+		{"Bad To", []string{"backfill-aggregates", "-from", "2026-03-01", "-to", "March"}, nil},
+		{"Reversed Range", []string{"backfill-aggregates", "-from", "2026-03-02", "-to", "2026-03-01"}, nil},
+		{"Unknown Flag", []string{"backfill-aggregates", "-days", "3"}, nil},
+		{"Use Case Error", []string{"backfill-aggregates", "-from", "2026-03-01", "-to", "2026-03-01"}, errors.New("database error")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewCLI(&MockReportUseCase{err: tc.err}, nil, nil, nil, &bytes.Buffer{}).Run(tc.args)
+			if err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
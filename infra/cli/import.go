@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"casino/boundary/dto"
+)
+
+// importCheckpoint is written next to an import file after every stored batch.
+// A later run of the same file skips everything up to Line and continues the
+// summary, so a crashed import can simply be started again.
+type importCheckpoint struct {
+	Line       int                    `json:"line"`
+	Inserted   int                    `json:"inserted"`
+	Duplicates []*importCheckpointRow `json:"duplicates"`
+	Invalid    []*importCheckpointRow `json:"invalid"`
+}
+
+type importCheckpointRow struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+func checkpointPath(path string) string {
+	return path + ".checkpoint"
+}
+
+func loadCheckpoint(path string) (*importCheckpoint, error) {
+	data, err := os.ReadFile(checkpointPath(path))
+	if os.IsNotExist(err) {
+		return &importCheckpoint{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := &importCheckpoint{}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %w", checkpointPath(path), err)
+	}
+	return checkpoint, nil
+}
+
+// save replaces the checkpoint file atomically so that a crash while writing
+// leaves the previous checkpoint in place.
+func (c *importCheckpoint) save(path string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	tmp := checkpointPath(path) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, checkpointPath(path)); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (c *importCheckpoint) add(result *dto.ImportBatchResultDTO) {
+	c.Inserted += result.Inserted
+	for _, row := range result.Duplicates {
+		c.Duplicates = append(c.Duplicates, &importCheckpointRow{Line: row.Line, Reason: row.Reason})
+	}
+	for _, row := range result.Invalid {
+		c.Invalid = append(c.Invalid, &importCheckpointRow{Line: row.Line, Reason: row.Reason})
+	}
+}
+
+func (c *CLI) importTransactions(args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	format := flags.String("format", "", "file format: csv or ndjson (default: from the file extension)")
+	batchSize := flags.Int("batch-size", 500, "number of rows stored per database transaction")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no files given")
+	}
+
+	if *batchSize <= 0 {
+		return fmt.Errorf("-batch-size must be positive")
+	}
+
+	for _, path := range flags.Args() {
+		if err := c.importFile(path, *format, *batchSize); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (c *CLI) importFile(path, format string, batchSize int) error {
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			format = "csv"
+		case ".ndjson", ".jsonl":
+			format = "ndjson"
+		default:
+			return fmt.Errorf("cannot tell the format from the extension, use -format")
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader importReader
+	switch format {
+	case "csv":
+		if reader, err = newCSVImportReader(file); err != nil {
+			return err
+		}
+	case "ndjson":
+		reader = newNDJSONImportReader(file)
+	default:
+		return fmt.Errorf("-format must be csv or ndjson")
+	}
+
+	checkpoint, err := loadCheckpoint(path)
+	if err != nil {
+		return err
+	}
+	if checkpoint.Line > 0 {
+		fmt.Fprintf(c.out, "%s: resuming after line %d\n", path, checkpoint.Line)
+	}
+
+	var batch []*dto.ImportRowDTO
+	lastLine := checkpoint.Line
+
+	flush := func() error {
+		result := &dto.ImportBatchResultDTO{}
+		if len(batch) > 0 {
+			if result, err = c.importUseCase.ImportBatch(batch); err != nil {
+				return err
+			}
+		}
+
+		checkpoint.add(result)
+		checkpoint.Line = lastLine
+		batch = nil
+		return checkpoint.save(path)
+	}
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if record.line <= checkpoint.Line {
+			continue
+		}
+		lastLine = record.line
+
+		// Unreadable rows go to the use case as well, so that they are
+		// reported and audited in line order with the rest.
+		batch = append(batch, &dto.ImportRowDTO{
+			Line:        record.line,
+			Source:      fmt.Sprintf("%s:%d", path, record.line),
+			Payload:     record.payload,
+			Transaction: record.transaction,
+			Err:         record.err,
+		})
+		if len(batch) >= batchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	c.printImportSummary(path, checkpoint)
+	return os.Remove(checkpointPath(path))
+}
+
+func (c *CLI) printImportSummary(path string, checkpoint *importCheckpoint) {
+	fmt.Fprintf(c.out, "%s: %d inserted, %d duplicate, %d invalid\n",
+		path, checkpoint.Inserted, len(checkpoint.Duplicates), len(checkpoint.Invalid))
+	for _, row := range checkpoint.Duplicates {
+		fmt.Fprintf(c.out, "  line %d: duplicate: %s\n", row.Line, row.Reason)
+	}
+	for _, row := range checkpoint.Invalid {
+		fmt.Fprintf(c.out, "  line %d: invalid: %s\n", row.Line, row.Reason)
+	}
+}
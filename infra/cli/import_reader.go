@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"casino/boundary/dto"
+)
+
+// importRecord is one data line of an import file. Lines that cannot be read
+// as a transaction carry err instead. payload is the line as JSON, for the
+// audit trail.
+type importRecord struct {
+	line        int
+	payload     []byte
+	transaction *dto.CreateTransactionDTO
+	err         error
+}
+
+type importReader interface {
+	// Next returns io.EOF after the last record.
+	Next() (*importRecord, error)
+}
+
+// importLine is the NDJSON representation of a transaction. CSV files use the
+// same names as column headers, which matches the transaction export.
+type importLine struct {
+	ID                    string `json:"id"`
+	UserID                string `json:"user_id"`
+	TransactionType       string `json:"transaction_type"`
+	Amount                uint   `json:"amount"`
+	Currency              string `json:"currency"`
+	Timestamp             string `json:"timestamp"`
+	RoundID               string `json:"round_id"`
+	GameID                string `json:"game_id"`
+	ProviderID            string `json:"provider_id"`
+	OriginalTransactionID string `json:"original_transaction_id"`
+	Reason                string `json:"reason"`
+}
+
+func (l *importLine) toDTO() (*dto.CreateTransactionDTO, error) {
+	if l.Timestamp == "" {
+		return nil, fmt.Errorf("timestamp is required")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339, l.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("timestamp must be an RFC3339 timestamp")
+	}
+
+	return &dto.CreateTransactionDTO{
+		ID:                    l.ID,
+		UserID:                l.UserID,
+		TransactionType:       l.TransactionType,
+		Amount:                l.Amount,
+		Currency:              l.Currency,
+		RoundID:               l.RoundID,
+		GameID:                l.GameID,
+		ProviderID:            l.ProviderID,
+		OriginalTransactionID: l.OriginalTransactionID,
+		Reason:                l.Reason,
+		Timestamp:             timestamp.UTC(),
+	}, nil
+}
+
+var requiredImportColumns = []string{"id", "user_id", "transaction_type", "amount", "timestamp"}
+
+type csvImportReader struct {
+	reader  *csv.Reader
+	header  []string
+	columns map[string]int
+}
+
+func newCSVImportReader(r io.Reader) (*csvImportReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		header[i] = strings.TrimSpace(strings.ToLower(name))
+		columns[header[i]] = i
+	}
+
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("header is missing column %q", name)
+		}
+	}
+
+	return &csvImportReader{reader: reader, header: header, columns: columns}, nil
+}
+
+func (cr *csvImportReader) Next() (*importRecord, error) {
+	fields, err := cr.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &importRecord{line: parseErr.StartLine, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := cr.reader.FieldPos(0)
+	payload := cr.payload(fields)
+	value := func(name string) string {
+		i, ok := cr.columns[name]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	amount, err := strconv.ParseUint(value("amount"), 10, 64)
+	if err != nil {
+		return &importRecord{line: line, payload: payload, err: fmt.Errorf("amount must be a positive integer")}, nil
+	}
+
+	record := &importLine{
+		ID:                    value("id"),
+		UserID:                value("user_id"),
+		TransactionType:       value("transaction_type"),
+		Amount:                uint(amount),
+		Currency:              value("currency"),
+		Timestamp:             value("timestamp"),
+		RoundID:               value("round_id"),
+		GameID:                value("game_id"),
+		ProviderID:            value("provider_id"),
+		OriginalTransactionID: value("original_transaction_id"),
+		Reason:                value("reason"),
+	}
+
+	transaction, err := record.toDTO()
+	return &importRecord{line: line, payload: payload, transaction: transaction, err: err}, nil
+}
+
+// payload encodes a row as a JSON object keyed by the header.
+func (cr *csvImportReader) payload(fields []string) []byte {
+	row := make(map[string]string, len(fields))
+	for i, field := range fields {
+		name := fmt.Sprintf("column_%d", i+1)
+		if i < len(cr.header) {
+			name = cr.header[i]
+		}
+		row[name] = field
+	}
+	payload, _ := json.Marshal(row)
+	return payload
+}
+
+type ndjsonImportReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func newNDJSONImportReader(r io.Reader) *ndjsonImportReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	return &ndjsonImportReader{scanner: scanner}
+}
+
+func (nr *ndjsonImportReader) Next() (*importRecord, error) {
+	for nr.scanner.Scan() {
+		nr.line++
+		text := strings.TrimSpace(nr.scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		payload := []byte(text)
+		var record importLine
+		if err := json.Unmarshal(payload, &record); err != nil {
+			return &importRecord{line: nr.line, payload: payload, err: fmt.Errorf("invalid JSON: %w", err)}, nil
+		}
+
+		transaction, err := record.toDTO()
+		return &importRecord{line: nr.line, payload: payload, transaction: transaction, err: err}, nil
+	}
+
+	if err := nr.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readAllRecords(t *testing.T, reader importReader) []*importRecord {
+	t.Helper()
+	var records []*importRecord
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+func TestCSVImportReader(t *testing.T) {
+	input := "id,user_id,transaction_type,amount,currency,timestamp,extra\n" +
+		"tx-1,user-1,bet,100,USD,2026-03-01T10:00:00Z,ignored\n" +
+		"tx-2,user-1,win,lots,USD,2026-03-01T10:01:00Z,\n" +
+		"tx-3,user-1,win,50,USD,yesterday,\n"
+
+	reader, err := newCSVImportReader(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	records := readAllRecords(t, reader)
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	first := records[0]
+	if first.err != nil || first.line != 2 {
+		t.Fatalf("Expected a valid record on line 2, got line %d with error %v", first.line, first.err)
+	}
+	if first.transaction.ID != "tx-1" || first.transaction.Amount != 100 || first.transaction.Currency != "USD" {
+		t.Errorf("Unexpected transaction %+v", first.transaction)
+	}
+	if !first.transaction.Timestamp.Equal(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected timestamp %s", first.transaction.Timestamp)
+	}
+	expectedPayload := `{"amount":"100","currency":"USD","extra":"ignored","id":"tx-1","timestamp":"2026-03-01T10:00:00Z","transaction_type":"bet","user_id":"user-1"}`
+	if string(first.payload) != expectedPayload {
+		t.Errorf("Expected payload %s, got %s", expectedPayload, first.payload)
+	}
+
+	if records[1].err == nil || records[1].line != 3 {
+		t.Errorf("Expected an amount error on line 3, got line %d with error %v", records[1].line, records[1].err)
+	}
+	if records[2].err == nil || records[2].line != 4 {
+		t.Errorf("Expected a timestamp error on line 4, got line %d with error %v", records[2].line, records[2].err)
+	}
+}
+
+func TestCSVImportReader_MissingColumn(t *testing.T) {
+	_, err := newCSVImportReader(strings.NewReader("id,user_id,transaction_type,amount\n"))
+	if err == nil || !strings.Contains(err.Error(), "timestamp") {
+		t.Errorf("Expected a missing timestamp column error, got %v", err)
+	}
+}
+
+func TestNDJSONImportReader(t *testing.T) {
+	input := `{"id":"tx-1","user_id":"user-1","transaction_type":"bet","amount":100,"timestamp":"2026-03-01T10:00:00Z"}` + "\n" +
+		"\n" +
+		`{"id":"tx-2",` + "\n" +
+		`{"id":"tx-3","user_id":"user-1","transaction_type":"win","amount":50}` + "\n"
+
+	records := readAllRecords(t, newNDJSONImportReader(strings.NewReader(input)))
+	if len(records) != 3 {
+		t.Fatalf("Expected 3 records, got %d", len(records))
+	}
+
+	if records[0].err != nil || records[0].line != 1 || records[0].transaction.ID != "tx-1" {
+		t.Errorf("Unexpected first record %+v", records[0])
+	}
+	if records[1].err == nil || records[1].line != 3 {
+		t.Errorf("Expected a JSON error on line 3, got line %d with error %v", records[1].line, records[1].err)
+	}
+	if records[2].err == nil || records[2].line != 4 {
+		t.Errorf("Expected a missing timestamp error on line 4, got line %d with error %v", records[2].line, records[2].err)
+	}
+}
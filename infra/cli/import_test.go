@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"casino/boundary/dto"
+)
+
+type MockImportUseCase struct {
+	batches [][]*dto.ImportRowDTO
+	seen    map[string]bool
+	// failOnBatch makes the n-th call (1-based) fail, simulating a crash.
+	failOnBatch int
+}
+
+func (m *MockImportUseCase) ImportBatch(rows []*dto.ImportRowDTO) (*dto.ImportBatchResultDTO, error) {
+	m.batches = append(m.batches, rows)
+	if len(m.batches) == m.failOnBatch {
+		return nil, errors.New("database error")
+	}
+	if m.seen == nil {
+		m.seen = map[string]bool{}
+	}
+
+	result := &dto.ImportBatchResultDTO{}
+	for _, row := range rows {
+		if row.Err != nil {
+			result.Invalid = append(result.Invalid, &dto.ImportRowErrorDTO{Line: row.Line, Reason: row.Err.Error()})
+			continue
+		}
+		if m.seen[row.Transaction.ID] {
+			result.Duplicates = append(result.Duplicates, &dto.ImportRowErrorDTO{
+				Line:          row.Line,
+				TransactionID: row.Transaction.ID,
+				Reason:        "transaction already exists",
+			})
+			continue
+		}
+		m.seen[row.Transaction.ID] = true
+		result.Inserted++
+	}
+	return result, nil
+}
+
+func writeImportFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write import file: %v", err)
+	}
+	return path
+}
+
+const importNDJSON = `{"id":"tx-1","user_id":"user-1","transaction_type":"bet","amount":100,"timestamp":"2026-03-01T10:00:00Z"}
+{"id":"tx-2","user_id":"user-1","transaction_type":"win","amount":50,"timestamp":"2026-03-01T10:01:00Z"}
+not json
+{"id":"tx-1","user_id":"user-1","transaction_type":"bet","amount":100,"timestamp":"2026-03-01T10:00:00Z"}
+{"id":"tx-3","user_id":"user-1","transaction_type":"bet","amount":10,"timestamp":"2026-03-01T10:02:00Z"}
+`
+
+func TestCLI_Import(t *testing.T) {
+	path := writeImportFile(t, "transactions.ndjson", importNDJSON)
+	mockUseCase := &MockImportUseCase{}
+	out := &bytes.Buffer{}
+
+	err := NewCLI(nil, mockUseCase, nil, nil, out).Run([]string{"import", "-batch-size", "2", path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockUseCase.batches) != 3 {
+		t.Errorf("Expected 3 batches, got %d", len(mockUseCase.batches))
+	}
+
+	unreadable := mockUseCase.batches[1][0]
+	if unreadable.Source != path+":3" || string(unreadable.Payload) != "not json" || unreadable.Err == nil {
+		t.Errorf("Expected the unreadable line to be passed on with its source, got %+v", unreadable)
+	}
+
+	output := out.String()
+	for _, expected := range []string{
+		"3 inserted, 1 duplicate, 1 invalid",
+		"line 4: duplicate: transaction already exists",
+		"line 3: invalid: invalid JSON",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, output)
+		}
+	}
+
+	if _, err := os.Stat(checkpointPath(path)); !os.IsNotExist(err) {
+		t.Error("Expected the checkpoint to be removed after a complete import")
+	}
+}
+
+func TestCLI_Import_ResumesAfterFailure(t *testing.T) {
+	path := writeImportFile(t, "transactions.ndjson", importNDJSON)
+	mockUseCase := &MockImportUseCase{failOnBatch: 2}
+
+	err := NewCLI(nil, mockUseCase, nil, nil, &bytes.Buffer{}).Run([]string{"import", "-batch-size", "2", path})
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	checkpoint, err := loadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if checkpoint.Line != 2 || checkpoint.Inserted != 2 {
+		t.Fatalf("Expected a checkpoint after line 2 with 2 inserted, got %+v", checkpoint)
+	}
+
+	mockUseCase.failOnBatch = 0
+	attempted := len(mockUseCase.batches)
+	out := &bytes.Buffer{}
+	if err := NewCLI(nil, mockUseCase, nil, nil, out).Run([]string{"import", "-batch-size", "2", path}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	resumed := mockUseCase.batches[attempted]
+	if resumed[0].Line != 3 {
+		t.Errorf("Expected the resumed import to start after the checkpoint, got line %d", resumed[0].Line)
+	}
+
+	output := out.String()
+	if !strings.Contains(output, "resuming after line 2") || !strings.Contains(output, "3 inserted, 1 duplicate, 1 invalid") {
+		t.Errorf("Unexpected output %q", output)
+	}
+}
+
+func TestCLI_Import_Errors(t *testing.T) {
+	csvWithoutTimestamp := writeImportFile(t, "transactions.csv", "id,user_id,transaction_type,amount\n")
+	unknownExtension := writeImportFile(t, "transactions.txt", importNDJSON)
+
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{"No Files", []string{"import"}},
+		{"Bad Batch Size", []string{"import", "-batch-size", "0", unknownExtension}},
+		{"Unknown Extension", []string{"import", unknownExtension}},
+		{"Unknown Format", []string{"import", "-format", "xml", unknownExtension}},
+		{"Missing File", []string{"import", filepath.Join(t.TempDir(), "missing.csv")}},
+		{"Missing Column", []string{"import", csvWithoutTimestamp}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := NewCLI(nil, &MockImportUseCase{}, nil, nil, &bytes.Buffer{}).Run(tc.args)
+			if err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"casino/infra/migrations"
+)
+
+type Migrator interface {
+	Up(ctx context.Context, steps int) ([]*migrations.Migration, error)
+	Down(ctx context.Context, steps int) ([]*migrations.Migration, error)
+	Status(ctx context.Context) ([]*migrations.Status, error)
+}
+
+// migrate manages the database schema with the migrations compiled into the
+// binary. create works on the source tree and needs no database.
+func (c *CLI) migrate(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("migrate needs a subcommand: up, down, status or create")
+	}
+
+	switch args[0] {
+	case "up":
+		return c.migrateUp(args[1:])
+	case "down":
+		return c.migrateDown(args[1:])
+	case "status":
+		return c.migrateStatus()
+	case "create":
+		return c.migrateCreate(args[1:])
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+}
+
+func (c *CLI) migrateUp(args []string) error {
+	flags := flag.NewFlagSet("migrate up", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	steps := flags.Int("steps", 0, "apply at most this many migrations, 0 for all")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *steps < 0 {
+		return fmt.Errorf("-steps must not be negative")
+	}
+	if c.migrator == nil {
+		return fmt.Errorf("migrate is not available")
+	}
+
+	applied, err := c.migrator.Up(context.Background(), *steps)
+	for _, migration := range applied {
+		fmt.Fprintf(c.out, "applied %03d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(c.out, "schema is up to date")
+	}
+	return nil
+}
+
+func (c *CLI) migrateDown(args []string) error {
+	flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *steps < 1 {
+		return fmt.Errorf("-steps must be at least 1")
+	}
+	if c.migrator == nil {
+		return fmt.Errorf("migrate is not available")
+	}
+
+	reverted, err := c.migrator.Down(context.Background(), *steps)
+	for _, migration := range reverted {
+		fmt.Fprintf(c.out, "reverted %03d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(reverted) == 0 {
+		fmt.Fprintln(c.out, "no migrations to revert")
+	}
+	return nil
+}
+
+func (c *CLI) migrateStatus() error {
+	if c.migrator == nil {
+		return fmt.Errorf("migrate is not available")
+	}
+
+	statuses, err := c.migrator.Status(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		switch {
+		case status.Missing:
+			fmt.Fprintf(c.out, "%03d  applied %s, no files in this build\n", status.Version, status.AppliedAt.Format(time.RFC3339))
+		case status.AppliedAt != nil:
+			fmt.Fprintf(c.out, "%03d_%s  applied %s\n", status.Version, status.Name, status.AppliedAt.Format(time.RFC3339))
+		default:
+			fmt.Fprintf(c.out, "%03d_%s  pending\n", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+func (c *CLI) migrateCreate(args []string) error {
+	flags := flag.NewFlagSet("migrate create", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	dir := flags.String("dir", migrations.Dir, "directory holding the migration files")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: migrate create [-dir DIR] NAME")
+	}
+
+	up, down, err := migrations.Create(*dir, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "created %s\ncreated %s\n", up, down)
+	return nil
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"casino/infra/migrations"
+)
+
+type MockMigrator struct {
+	steps    int
+	applied  []*migrations.Migration
+	statuses []*migrations.Status
+	err      error
+}
+
+func (m *MockMigrator) Up(ctx context.Context, steps int) ([]*migrations.Migration, error) {
+	m.steps = steps
+	return m.applied, m.err
+}
+
+func (m *MockMigrator) Down(ctx context.Context, steps int) ([]*migrations.Migration, error) {
+	m.steps = steps
+	return m.applied, m.err
+}
+
+func (m *MockMigrator) Status(ctx context.Context) ([]*migrations.Status, error) {
+	return m.statuses, m.err
+}
+
+func TestCLI_MigrateUp(t *testing.T) {
+	migrator := &MockMigrator{applied: []*migrations.Migration{{Version: 10, Name: "daily_user_aggregates"}, {Version: 11, Name: "outbox"}}}
+	out := &bytes.Buffer{}
+
+	if err := NewCLI(nil, nil, nil, migrator, out).Run([]string{"migrate", "up"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if migrator.steps != 0 {
+		t.Errorf("Expected every migration to be applied, got steps %d", migrator.steps)
+	}
+	if out.String() != "applied 010_daily_user_aggregates\napplied 011_outbox\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestCLI_MigrateUp_UpToDate(t *testing.T) {
+	out := &bytes.Buffer{}
+
+	if err := NewCLI(nil, nil, nil, &MockMigrator{}, out).Run([]string{"migrate", "up", "-steps", "2"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.String() != "schema is up to date\n" {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestCLI_MigrateDown(t *testing.T) {
+	migrator := &MockMigrator{applied: []*migrations.Migration{{Version: 11, Name: "outbox"}}, err: errors.New("lock timeout")}
+	out := &bytes.Buffer{}
+
+	err := NewCLI(nil, nil, nil, migrator, out).Run([]string{"migrate", "down", "-steps", "2"})
+	if err == nil || err.Error() != "lock timeout" {
+		t.Fatalf("Expected the migrator error, got %v", err)
+	}
+	if migrator.steps != 2 {
+		t.Errorf("Expected 2 steps, got %d", migrator.steps)
+	}
+	if out.String() != "reverted 011_outbox\n" {
+		t.Errorf("Expected the reverted migrations to be reported, got %q", out.String())
+	}
+}
+
+func TestCLI_MigrateStatus(t *testing.T) {
+	appliedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	migrator := &MockMigrator{statuses: []*migrations.Status{
+		{Version: 1, Name: "init", AppliedAt: &appliedAt},
+		{Version: 2, Name: "audit_log"},
+		{Version: 3, AppliedAt: &appliedAt, Missing: true},
+	}}
+	out := &bytes.Buffer{}
+
+	if err := NewCLI(nil, nil, nil, migrator, out).Run([]string{"migrate", "status"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "001_init  applied 2026-10-01T12:00:00Z\n002_audit_log  pending\n003  applied 2026-10-01T12:00:00Z, no files in this build\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestCLI_MigrateCreate(t *testing.T) {
+	dir := t.TempDir()
+	out := &bytes.Buffer{}
+
+	if err := NewCLI(nil, nil, nil, nil, out).Run([]string{"migrate", "create", "-dir", dir, "add_bonus"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{"001_add_bonus.up.sql", "001_add_bonus.down.sql"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be created, got %v", name, err)
+		}
+	}
+}
+
+func TestCLI_Migrate_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		migrator Migrator
+	}{
+		{"No Subcommand", []string{"migrate"}, &MockMigrator{}},
+		{"Unknown Subcommand", []string{"migrate", "sideways"}, &MockMigrator{}},
+		{"Negative Up Steps", []string{"migrate", "up", "-steps", "-1"}, &MockMigrator{}},
+		{"Zero Down Steps", []string{"migrate", "down", "-steps", "0"}, &MockMigrator{}},
+		{"Create Without Name", []string{"migrate", "create"}, nil},
+		{"Without Migrator", []string{"migrate", "status"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewCLI(nil, nil, nil, tt.migrator, &bytes.Buffer{}).Run(tt.args); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
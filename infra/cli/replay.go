@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"casino/infra/kafka"
+)
+
+type Replayer interface {
+	Replay(ctx context.Context, options *kafka.ReplayOptions) (*kafka.ReplayResult, error)
+}
+
+// replay reprocesses a window of a Kafka topic. Transactions are idempotent
+// on their ID, so replaying messages that were already applied is safe and
+// only counts them as already present.
+func (c *CLI) replay(args []string) error {
+	flags := flag.NewFlagSet("replay", flag.ContinueOnError)
+	flags.SetOutput(c.out)
+	topic := flags.String("topic", "casino-transactions-stream", "configured topic to replay")
+	fromOffset := flags.Int64("from-offset", -1, "offset to start at in every partition")
+	fromTime := flags.String("from-time", "", "start at the first message written at or after this time (RFC 3339)")
+	toOffset := flags.Int64("to-offset", -1, "offset to stop before in every partition")
+	toTime := flags.String("to-time", "", "stop before the first message written at or after this time (RFC 3339)")
+	dryRun := flags.Bool("dry-run", false, "only report which transactions are new, without processing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if c.replayer == nil {
+		return fmt.Errorf("replay is not available")
+	}
+
+	options := &kafka.ReplayOptions{Topic: *topic, DryRun: *dryRun}
+	if *fromOffset >= 0 {
+		options.FromOffset = fromOffset
+	}
+	if *toOffset >= 0 {
+		options.ToOffset = toOffset
+	}
+
+	var err error
+	if options.FromTime, err = parseReplayTime("-from-time", *fromTime); err != nil {
+		return err
+	}
+	if options.ToTime, err = parseReplayTime("-to-time", *toTime); err != nil {
+		return err
+	}
+
+	if (options.FromOffset == nil) == (options.FromTime == nil) {
+		return fmt.Errorf("give exactly one of -from-offset and -from-time")
+	}
+	if options.ToOffset != nil && options.ToTime != nil {
+		return fmt.Errorf("give at most one of -to-offset and -to-time")
+	}
+
+	// An interrupt stops the replay between messages and still prints how
+	// far it got.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	result, err := c.replayer.Replay(ctx, options)
+	if result != nil {
+		c.printReplaySummary(options, result)
+	}
+	return err
+}
+
+func parseReplayTime(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC 3339 time", name)
+	}
+	return &t, nil
+}
+
+func (c *CLI) printReplaySummary(options *kafka.ReplayOptions, result *kafka.ReplayResult) {
+	for _, partition := range result.Partitions {
+		fmt.Fprintf(c.out, "  partition %d: offsets %d to %d", partition.Partition, partition.From, partition.To)
+		if partition.Incomplete() {
+			fmt.Fprintf(c.out, ", incomplete: stopped at %d", partition.Reached)
+		}
+		fmt.Fprintln(c.out)
+	}
+
+	mode := "replayed"
+	if options.DryRun {
+		mode = "dry run:"
+	}
+	fmt.Fprintf(c.out, "%s %s %d messages: %d new, %d already present, %d failed\n",
+		mode, options.Topic, result.Read, result.New, result.Present, result.Failed)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"casino/infra/kafka"
+)
+
+type MockReplayer struct {
+	options *kafka.ReplayOptions
+	result  *kafka.ReplayResult
+	err     error
+}
+
+func (m *MockReplayer) Replay(ctx context.Context, options *kafka.ReplayOptions) (*kafka.ReplayResult, error) {
+	m.options = options
+	return m.result, m.err
+}
+
+func TestCLI_Replay(t *testing.T) {
+	replayer := &MockReplayer{result: &kafka.ReplayResult{
+		Partitions: []*kafka.ReplayPartitionResult{{Partition: 0, From: 10, To: 20, Reached: 20}},
+		Read:       10,
+		New:        4,
+		Present:    5,
+		Failed:     1,
+	}}
+	out := &bytes.Buffer{}
+
+	err := NewCLI(nil, nil, replayer, nil, out).Run([]string{"replay", "-topic", "casino-deposits", "-from-time", "2026-10-01T00:00:00Z", "-to-offset", "20", "-dry-run"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	options := replayer.options
+	if options.Topic != "casino-deposits" || !options.DryRun || options.FromOffset != nil || options.ToTime != nil {
+		t.Errorf("Unexpected replay options %+v", options)
+	}
+	if !options.FromTime.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || *options.ToOffset != 20 {
+		t.Errorf("Unexpected replay bounds %v %v", options.FromTime, *options.ToOffset)
+	}
+
+	expected := "  partition 0: offsets 10 to 20\ndry run: casino-deposits 10 messages: 4 new, 5 already present, 1 failed\n"
+	if out.String() != expected {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+}
+
+func TestCLI_Replay_ReportsPartialResult(t *testing.T) {
+	replayer := &MockReplayer{result: &kafka.ReplayResult{
+		Partitions: []*kafka.ReplayPartitionResult{{Partition: 1, From: 0, To: 5, Reached: 3}},
+		Read:       3,
+		New:        3,
+	}, err: errors.New("broker gone")}
+	out := &bytes.Buffer{}
+
+	err := NewCLI(nil, nil, replayer, nil, out).Run([]string{"replay", "-from-offset", "0"})
+	if err == nil || err.Error() != "broker gone" {
+		t.Fatalf("Expected the replay error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "partition 1: offsets 0 to 5, incomplete: stopped at 3\n") ||
+		!strings.Contains(out.String(), "replayed casino-transactions-stream 3 messages: 3 new") {
+		t.Errorf("Expected the partial summary, got %q", out.String())
+	}
+}
+
+func TestCLI_Replay_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"No Start", []string{"replay"}, "give exactly one of -from-offset and -from-time"},
+		{"Two Starts", []string{"replay", "-from-offset", "0", "-from-time", "2026-10-01T00:00:00Z"}, "give exactly one of -from-offset and -from-time"},
+		{"Two Ends", []string{"replay", "-from-offset", "0", "-to-offset", "5", "-to-time", "2026-10-01T00:00:00Z"}, "give at most one of -to-offset and -to-time"},
+		{"Bad Time", []string{"replay", "-from-time", "yesterday"}, "-from-time must be an RFC 3339 time"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replayer := &MockReplayer{}
+
+			err := NewCLI(nil, nil, replayer, nil, &bytes.Buffer{}).Run(tc.args)
+			if err == nil || err.Error() != tc.expected {
+				t.Errorf("Expected error %q, got %v", tc.expected, err)
+			}
+			if replayer.options != nil {
+				t.Error("Expected replay not to run")
+			}
+		})
+	}
+}
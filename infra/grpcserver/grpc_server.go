@@ -0,0 +1,71 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/infra/grpcserver/pb"
+
+	"google.golang.org/grpc"
+)
+
+// GRPCServer serves the transaction use case to internal services next to
+// the REST API.
+type GRPCServer struct {
+	server *grpc.Server
+}
+
+// NewGRPCServer serves callers presenting one of serviceTokens, which maps
+// service names to their tokens.
+func NewGRPCServer(transactionUseCase usecase.TransactionUseCase, auditUseCase usecase.AuditUseCase, serviceTokens map[string]string, logger logging.Logger) *GRPCServer {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			requestIDUnaryInterceptor,
+			loggingUnaryInterceptor(logger),
+			authUnaryInterceptor(serviceTokens),
+		),
+		grpc.ChainStreamInterceptor(
+			requestIDStreamInterceptor,
+			loggingStreamInterceptor(logger),
+			authStreamInterceptor(serviceTokens),
+		),
+	)
+	pb.RegisterTransactionServiceServer(server, &transactionService{
+		transactionUseCase: transactionUseCase,
+		auditUseCase:       auditUseCase,
+		logger:             logger,
+	})
+
+	return &GRPCServer{server: server}
+}
+
+func (s *GRPCServer) Start(address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+func (s *GRPCServer) Serve(listener net.Listener) error {
+	return s.server.Serve(listener)
+}
+
+// Stop stops accepting calls and waits for the running ones to finish. Calls
+// still running when ctx is done, such as long ListTransactions streams, are
+// cancelled.
+func (s *GRPCServer) Stop(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+}
@@ -0,0 +1,146 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"time"
+
+	"casino/boundary/logging"
+	"casino/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const requestIDMetadataKey = "x-request-id"
+
+// serverStream lets stream interceptors replace the context handlers see.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	if ctx.Value(utils.CtxKeyRequestID) != nil {
+		return ctx
+	}
+
+	requestID := utils.GenerateUUID()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestIDMetadataKey); len(values) > 0 && values[0] != "" {
+			requestID = values[0]
+		}
+	}
+	return context.WithValue(ctx, utils.CtxKeyRequestID, requestID)
+}
+
+func requestIDUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(withRequestID(ctx), req)
+}
+
+func requestIDStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &serverStream{ServerStream: stream, ctx: withRequestID(stream.Context())})
+}
+
+// loggingUnaryInterceptor is the gRPC counterpart of middleware.LoggingMiddleware.
+func loggingUnaryInterceptor(logger logging.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		logger.Info(ctx, fmt.Sprintf("Request started: %s", info.FullMethod))
+
+		resp, err := handler(ctx, req)
+
+		logCompleted(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func loggingStreamInterceptor(logger logging.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		logger.Info(stream.Context(), fmt.Sprintf("Request started: %s", info.FullMethod))
+
+		err := handler(srv, stream)
+
+		logCompleted(stream.Context(), logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCompleted(ctx context.Context, logger logging.Logger, method string, start time.Time, err error) {
+	if err != nil {
+		logger.Error(ctx, err)
+	}
+	logger.Info(ctx, fmt.Sprintf("Request completed: %s - Code: %s - Duration: %s",
+		method,
+		status.Code(err),
+		time.Since(start).String()),
+	)
+}
+
+func authUnaryInterceptor(serviceTokens map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, serviceTokens)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func authStreamInterceptor(serviceTokens map[string]string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), serviceTokens)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate finds the service the bearer token belongs to and stores its
+// name in the context under utils.CtxKeyActor.
+func authenticate(ctx context.Context, serviceTokens map[string]string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "missing service token")
+	}
+
+	presented := []byte(strings.TrimPrefix(values[0], "Bearer "))
+	caller := ""
+	for service, token := range serviceTokens {
+		if subtle.ConstantTimeCompare(presented, []byte(token)) == 1 {
+			caller = service
+		}
+	}
+	if caller == "" {
+		return nil, status.Error(codes.Unauthenticated, "invalid service token")
+	}
+	return context.WithValue(ctx, utils.CtxKeyActor, caller), nil
+}
+
+// ParseServiceTokens reads a comma separated list of service=token pairs,
+// naming the services allowed to call the gRPC API.
+func ParseServiceTokens(value string) (map[string]string, error) {
+	serviceTokens := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		service, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || service == "" || token == "" {
+			return nil, fmt.Errorf("service token %q is not of the form service=token", pair)
+		}
+		if _, ok := serviceTokens[service]; ok {
+			return nil, fmt.Errorf("service %s has more than one token", service)
+		}
+		serviceTokens[service] = token
+	}
+	return serviceTokens, nil
+}
@@ -0,0 +1,106 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"casino/infra/grpcserver/pb"
+	"casino/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthInterceptors(t *testing.T) {
+	client := newTestClient(t, newMockUseCase(), &MockLogger{})
+
+	testCases := []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"Missing Token", context.Background()},
+		{"Wrong Token", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer wrong")},
+		{"Wrong Scheme", metadata.AppendToOutgoingContext(context.Background(), "authorization", "Basic "+testServiceToken)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.GetTransaction(tc.ctx, &pb.GetTransactionRequest{Id: "tx-1"})
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("Expected Unauthenticated for a unary call, got %v", err)
+			}
+
+			stream, err := client.ListTransactions(tc.ctx, &pb.ListTransactionsRequest{})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("Expected Unauthenticated for a stream, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_IdentifiesService(t *testing.T) {
+	serviceTokens := map[string]string{"payments": "payments-token", "lobby": "lobby-token"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer lobby-token"))
+
+	ctx, err := authenticate(ctx, serviceTokens)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if actor := ctx.Value(utils.CtxKeyActor); actor != "lobby" {
+		t.Errorf("Expected the lobby service as the actor, got %v", actor)
+	}
+}
+
+func TestParseServiceTokens(t *testing.T) {
+	serviceTokens, err := ParseServiceTokens("payments=abc, lobby=def")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(serviceTokens) != 2 || serviceTokens["payments"] != "abc" || serviceTokens["lobby"] != "def" {
+		t.Errorf("Unexpected service tokens %v", serviceTokens)
+	}
+
+	for _, value := range []string{"abc", "payments=", "=abc", "payments=abc,payments=def"} {
+		if _, err := ParseServiceTokens(value); err == nil {
+			t.Errorf("Expected %q to be rejected", value)
+		}
+	}
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	logger := &MockLogger{}
+	client := newTestClient(t, newMockUseCase(), logger)
+
+	if _, err := client.GetTransaction(authorized(), &pb.GetTransactionRequest{Id: "missing"}); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if len(logger.messages) != 2 {
+		t.Fatalf("Expected a start and a completion message, got %v", logger.messages)
+	}
+	if logger.messages[0] != "Request started: /casino.transaction.v1.TransactionService/GetTransaction" {
+		t.Errorf("Unexpected start message %q", logger.messages[0])
+	}
+	if !strings.Contains(logger.messages[1], "Code: NotFound") {
+		t.Errorf("Expected the status code to be logged, got %q", logger.messages[1])
+	}
+	if len(logger.errors) != 1 {
+		t.Errorf("Expected the error to be logged, got %v", logger.errors)
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	incoming := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestIDMetadataKey, "req-1"))
+	if requestID := withRequestID(incoming).Value(utils.CtxKeyRequestID); requestID != "req-1" {
+		t.Errorf("Expected the request ID from metadata, got %v", requestID)
+	}
+
+	if requestID := withRequestID(context.Background()).Value(utils.CtxKeyRequestID); requestID == nil || requestID == "" {
+		t.Error("Expected a generated request ID")
+	}
+}
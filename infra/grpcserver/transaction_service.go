@@ -0,0 +1,193 @@
+package grpcserver
+
+import (
+	"context"
+
+	"casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/domain/entity"
+	"casino/infra/grpcserver/pb"
+	"casino/utils"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	auditActionProcessTransaction = "transaction.process"
+	auditActionCancelTransaction  = "transaction.cancel"
+)
+
+type transactionService struct {
+	pb.UnimplementedTransactionServiceServer
+	transactionUseCase usecase.TransactionUseCase
+	auditUseCase       usecase.AuditUseCase
+	logger             logging.Logger
+}
+
+func (s *transactionService) GetTransaction(ctx context.Context, req *pb.GetTransactionRequest) (*pb.Transaction, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	transaction, err := s.transactionUseCase.GetTransaction(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toProtoTransaction(transaction), nil
+}
+
+func (s *transactionService) ListTransactions(req *pb.ListTransactionsRequest, stream pb.TransactionService_ListTransactionsServer) error {
+	filter := &dto.TransactionFilterDTO{
+		TransactionType: req.TransactionType,
+		Currency:        req.Currency,
+	}
+
+	err := s.transactionUseCase.ExportTransactions(stream.Context(), req.UserId, filter, func(transaction *dto.TransactionDTO) error {
+		return stream.Send(toProtoTransaction(transaction))
+	})
+	if err != nil {
+		return statusError(err)
+	}
+	return nil
+}
+
+func (s *transactionService) ListUserTransactions(ctx context.Context, req *pb.ListUserTransactionsRequest) (*pb.ListUserTransactionsResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	filter := &dto.TransactionFilterDTO{
+		TransactionType: req.TransactionType,
+		Currency:        req.Currency,
+	}
+
+	transactions, err := s.transactionUseCase.GetUserTransactions(req.GetUserId(), filter)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &pb.ListUserTransactionsResponse{Transactions: make([]*pb.Transaction, len(transactions))}
+	for i, transaction := range transactions {
+		response.Transactions[i] = toProtoTransaction(transaction)
+	}
+	return response, nil
+}
+
+func (s *transactionService) CreateTransaction(ctx context.Context, req *pb.CreateTransactionRequest) (*pb.Transaction, error) {
+	if entity.TransactionType(req.GetTransactionType()) == entity.TransactionTypeRollback {
+		rollback, err := s.transactionUseCase.CancelTransaction(&dto.CancelTransactionDTO{
+			ID:                    req.GetId(),
+			OriginalTransactionID: req.GetOriginalTransactionId(),
+			Reason:                req.GetReason(),
+		})
+		s.audit(ctx, auditActionCancelTransaction, req, err)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		return toProtoTransaction(rollback), nil
+	}
+
+	err := s.transactionUseCase.ProcessTransaction(&dto.CreateTransactionDTO{
+		ID:                    req.GetId(),
+		UserID:                req.GetUserId(),
+		TransactionType:       req.GetTransactionType(),
+		Amount:                uint(req.GetAmount()),
+		Currency:              req.GetCurrency(),
+		RoundID:               req.GetRoundId(),
+		GameID:                req.GetGameId(),
+		ProviderID:            req.GetProviderId(),
+		OriginalTransactionID: req.GetOriginalTransactionId(),
+		Reason:                req.GetReason(),
+	})
+	s.audit(ctx, auditActionProcessTransaction, req, err)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	transaction, err := s.transactionUseCase.GetTransaction(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toProtoTransaction(transaction), nil
+}
+
+func (s *transactionService) GetBalance(ctx context.Context, req *pb.GetBalanceRequest) (*pb.GetBalanceResponse, error) {
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	balances, err := s.transactionUseCase.GetBalances(req.GetUserId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	response := &pb.GetBalanceResponse{UserId: req.GetUserId(), Balances: make([]*pb.Balance, len(balances))}
+	for i, balance := range balances {
+		response.Balances[i] = &pb.Balance{
+			Currency:  balance.Currency,
+			Balance:   balance.Balance,
+			Formatted: balance.Formatted,
+		}
+	}
+	return response, nil
+}
+
+// audit records a transaction created over gRPC the way the Kafka consumer
+// records one it consumed, with the calling service as the actor.
+func (s *transactionService) audit(ctx context.Context, action string, req *pb.CreateTransactionRequest, actionErr error) {
+	actor, _ := ctx.Value(utils.CtxKeyActor).(string)
+	requestID, _ := ctx.Value(utils.CtxKeyRequestID).(string)
+	payload, _ := protojson.Marshal(req)
+
+	err := s.auditUseCase.Record(&dto.CreateAuditRecordDTO{
+		Actor:   actor,
+		Action:  action,
+		Source:  "grpc:" + requestID,
+		Payload: payload,
+		Err:     actionErr,
+	})
+	if err != nil {
+		s.logger.Error(ctx, err)
+	}
+}
+
+func toProtoTransaction(transaction *dto.TransactionDTO) *pb.Transaction {
+	return &pb.Transaction{
+		Id:                    transaction.ID,
+		UserId:                transaction.UserID,
+		TransactionType:       transaction.TransactionType,
+		Amount:                uint64(transaction.Amount),
+		Currency:              transaction.Currency,
+		Timestamp:             timestamppb.New(transaction.Timestamp),
+		RoundId:               transaction.RoundID,
+		GameId:                transaction.GameID,
+		ProviderId:            transaction.ProviderID,
+		OriginalTransactionId: transaction.OriginalTransactionID,
+		Reason:                transaction.Reason,
+		ReportingAmount:       transaction.ReportingAmount,
+		ExchangeRate:          transaction.ExchangeRate,
+	}
+}
+
+// statusError maps use case errors to gRPC codes the same way the REST
+// handlers map them to HTTP statuses.
+func statusError(err error) error {
+	switch {
+	case utils.IsTransactionValidation(err), utils.IsExchangeRateValidation(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case utils.IsTransactionNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case utils.IsTransactionAlreadyExists(err):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case utils.IsTransactionAlreadyCancelled(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case status.Code(err) == codes.Canceled || status.Code(err) == codes.DeadlineExceeded:
+		return err
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,329 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+	"casino/infra/grpcserver/pb"
+	"casino/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testService      = "payments"
+	testServiceToken = "service-token"
+)
+
+type MockTransactionUseCase struct {
+	transactions map[string]*dto.TransactionDTO
+	processed    *dto.CreateTransactionDTO
+	processError error
+	cancelled    *dto.CancelTransactionDTO
+	balances     []*dto.BalanceDTO
+	lastUserID   *string
+	lastFilter   *dto.TransactionFilterDTO
+}
+
+func (m *MockTransactionUseCase) ProcessTransaction(create *dto.CreateTransactionDTO) error {
+	m.processed = create
+	if m.processError != nil {
+		return m.processError
+	}
+	m.transactions[create.ID] = &dto.TransactionDTO{
+		ID:              create.ID,
+		UserID:          create.UserID,
+		TransactionType: create.TransactionType,
+		Amount:          create.Amount,
+		Currency:        create.Currency,
+		Timestamp:       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+	return nil
+}
+
+func (m *MockTransactionUseCase) GetTransaction(id string) (*dto.TransactionDTO, error) {
+	transaction, ok := m.transactions[id]
+	if !ok {
+		return nil, &utils.TransactionNotFoundError{TransactionID: id}
+	}
+	return transaction, nil
+}
+
+func (m *MockTransactionUseCase) GetUserTransactions(userID string, filter *dto.TransactionFilterDTO) ([]*dto.TransactionDTO, error) {
+	m.lastUserID = &userID
+	m.lastFilter = filter
+	var result []*dto.TransactionDTO
+	for _, transaction := range m.transactions {
+		if transaction.UserID == userID {
+			result = append(result, transaction)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockTransactionUseCase) GetReversals(originalID string) ([]*dto.TransactionDTO, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionUseCase) GetAllTransactions(filter *dto.TransactionFilterDTO) ([]*dto.TransactionDTO, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionUseCase) GetRound(roundID string) (*dto.RoundDTO, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionUseCase) CancelTransaction(cancel *dto.CancelTransactionDTO) (*dto.TransactionDTO, error) {
+	m.cancelled = cancel
+	return &dto.TransactionDTO{ID: cancel.ID, TransactionType: "rollback", OriginalTransactionID: cancel.OriginalTransactionID}, nil
+}
+
+func (m *MockTransactionUseCase) GetBalances(userID string) ([]*dto.BalanceDTO, error) {
+	return m.balances, nil
+}
+
+func (m *MockTransactionUseCase) ExportTransactions(ctx context.Context, userID *string, filter *dto.TransactionFilterDTO, fn func(*dto.TransactionDTO) error) error {
+	m.lastUserID = userID
+	m.lastFilter = filter
+	for _, id := range []string{"tx-1", "tx-2"} {
+		if transaction, ok := m.transactions[id]; ok {
+			if err := fn(transaction); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type MockAuditUseCase struct {
+	records []*dto.CreateAuditRecordDTO
+}
+
+func (m *MockAuditUseCase) Record(record *dto.CreateAuditRecordDTO) error {
+	m.records = append(m.records, record)
+	return nil
+}
+
+func (m *MockAuditUseCase) VerifyChain(from, to time.Time) (*dto.AuditVerificationDTO, error) {
+	return &dto.AuditVerificationDTO{Valid: true}, nil
+}
+
+type MockLogger struct {
+	errors   []error
+	messages []string
+}
+
+func (m *MockLogger) Error(ctx context.Context, errs ...error) {
+	m.errors = append(m.errors, errs...)
+}
+
+func (m *MockLogger) Info(ctx context.Context, messages ...string) {
+	m.messages = append(m.messages, messages...)
+}
+
+func newTestClient(t *testing.T, useCase *MockTransactionUseCase, logger *MockLogger) pb.TransactionServiceClient {
+	t.Helper()
+	return newAuditedTestClient(t, useCase, &MockAuditUseCase{}, logger)
+}
+
+func newAuditedTestClient(t *testing.T, useCase *MockTransactionUseCase, auditUseCase *MockAuditUseCase, logger *MockLogger) pb.TransactionServiceClient {
+	t.Helper()
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewGRPCServer(useCase, auditUseCase, map[string]string{testService: testServiceToken, "lobby": "lobby-token"}, logger)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Stop(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewTransactionServiceClient(conn)
+}
+
+func authorized() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+testServiceToken)
+}
+
+func newMockUseCase() *MockTransactionUseCase {
+	return &MockTransactionUseCase{transactions: map[string]*dto.TransactionDTO{
+		"tx-1": {ID: "tx-1", UserID: "user-1", TransactionType: "bet", Amount: 500, Currency: "EUR", Timestamp: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		"tx-2": {ID: "tx-2", UserID: "user-2", TransactionType: "win", Amount: 200, Currency: "EUR", Timestamp: time.Date(2026, 3, 1, 9, 5, 0, 0, time.UTC)},
+	}}
+}
+
+func TestTransactionService_GetTransaction(t *testing.T) {
+	client := newTestClient(t, newMockUseCase(), &MockLogger{})
+
+	transaction, err := client.GetTransaction(authorized(), &pb.GetTransactionRequest{Id: "tx-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transaction.UserId != "user-1" || transaction.Amount != 500 || !transaction.Timestamp.AsTime().Equal(time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected transaction %v", transaction)
+	}
+
+	_, err = client.GetTransaction(authorized(), &pb.GetTransactionRequest{Id: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("Expected NotFound, got %v", err)
+	}
+
+	_, err = client.GetTransaction(authorized(), &pb.GetTransactionRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestTransactionService_ListTransactions(t *testing.T) {
+	useCase := newMockUseCase()
+	client := newTestClient(t, useCase, &MockLogger{})
+
+	currency := "EUR"
+	stream, err := client.ListTransactions(authorized(), &pb.ListTransactionsRequest{Currency: &currency})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var ids []string
+	for {
+		transaction, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		ids = append(ids, transaction.Id)
+	}
+
+	if len(ids) != 2 || ids[0] != "tx-1" || ids[1] != "tx-2" {
+		t.Errorf("Expected tx-1 and tx-2 in order, got %v", ids)
+	}
+	if useCase.lastUserID != nil || *useCase.lastFilter.Currency != "EUR" {
+		t.Errorf("Unexpected filter %+v", useCase.lastFilter)
+	}
+}
+
+func TestTransactionService_ListUserTransactions(t *testing.T) {
+	useCase := newMockUseCase()
+	client := newTestClient(t, useCase, &MockLogger{})
+
+	transactionType := "bet"
+	response, err := client.ListUserTransactions(authorized(), &pb.ListUserTransactionsRequest{UserId: "user-1", TransactionType: &transactionType})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Transactions) != 1 || response.Transactions[0].Id != "tx-1" {
+		t.Errorf("Unexpected transactions %v", response.Transactions)
+	}
+	if *useCase.lastFilter.TransactionType != "bet" {
+		t.Errorf("Expected the type filter to be passed on, got %+v", useCase.lastFilter)
+	}
+}
+
+func TestTransactionService_CreateTransaction(t *testing.T) {
+	useCase := newMockUseCase()
+	audit := &MockAuditUseCase{}
+	client := newAuditedTestClient(t, useCase, audit, &MockLogger{})
+
+	transaction, err := client.CreateTransaction(authorized(), &pb.CreateTransactionRequest{
+		Id:              "tx-3",
+		UserId:          "user-1",
+		TransactionType: "deposit",
+		Amount:          1000,
+		Currency:        "EUR",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if transaction.Id != "tx-3" || useCase.processed.Amount != 1000 {
+		t.Errorf("Unexpected transaction %v", transaction)
+	}
+
+	rollback, err := client.CreateTransaction(authorized(), &pb.CreateTransactionRequest{
+		Id:                    "tx-4",
+		TransactionType:       "rollback",
+		OriginalTransactionId: "tx-1",
+		Reason:                "duplicate bet",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rollback.OriginalTransactionId != "tx-1" || useCase.cancelled.Reason != "duplicate bet" {
+		t.Errorf("Expected the rollback to go through CancelTransaction, got %v", rollback)
+	}
+
+	if len(audit.records) != 2 {
+		t.Fatalf("Expected both transactions to be audited, got %d records", len(audit.records))
+	}
+	for i, action := range []string{auditActionProcessTransaction, auditActionCancelTransaction} {
+		record := audit.records[i]
+		if record.Action != action || record.Actor != testService || !strings.HasPrefix(record.Source, "grpc:") || record.Err != nil {
+			t.Errorf("Unexpected audit record %+v", record)
+		}
+	}
+	if !strings.Contains(string(audit.records[0].Payload), `"tx-3"`) {
+		t.Errorf("Expected the request as the audited payload, got %s", audit.records[0].Payload)
+	}
+}
+
+func TestTransactionService_CreateTransaction_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected codes.Code
+	}{
+		{"Validation", &utils.TransactionValidationError{Reason: "amount must be positive"}, codes.InvalidArgument},
+		{"Duplicate", &utils.TransactionAlreadyExistsError{TransactionID: "tx-1"}, codes.AlreadyExists},
+		{"Already Cancelled", &utils.TransactionAlreadyCancelledError{TransactionID: "tx-1"}, codes.FailedPrecondition},
+		{"Database", errors.New("database error"), codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			useCase := newMockUseCase()
+			useCase.processError = tc.err
+			audit := &MockAuditUseCase{}
+			client := newAuditedTestClient(t, useCase, audit, &MockLogger{})
+
+			_, err := client.CreateTransaction(authorized(), &pb.CreateTransactionRequest{Id: "tx-3", UserId: "user-1", TransactionType: "bet", Amount: 1})
+			if status.Code(err) != tc.expected {
+				t.Errorf("Expected %s, got %v", tc.expected, err)
+			}
+			if len(audit.records) != 1 || audit.records[0].Err != tc.err {
+				t.Errorf("Expected the failure to be audited, got %+v", audit.records)
+			}
+		})
+	}
+}
+
+func TestTransactionService_GetBalance(t *testing.T) {
+	useCase := newMockUseCase()
+	useCase.balances = []*dto.BalanceDTO{{Currency: "EUR", Balance: 300, Formatted: "3.00 EUR"}}
+	client := newTestClient(t, useCase, &MockLogger{})
+
+	response, err := client.GetBalance(authorized(), &pb.GetBalanceRequest{UserId: "user-1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if response.UserId != "user-1" || len(response.Balances) != 1 || response.Balances[0].Balance != 300 {
+		t.Errorf("Unexpected response %v", response)
+	}
+}
@@ -0,0 +1,123 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ConsumerConfig lists the topics to consume and how to treat each one. It
+// is read from a JSON file so topics can be added without code changes.
+type ConsumerConfig struct {
+	Brokers       []string      `json:"brokers"`
+	GroupID       string        `json:"group_id"`
+	AvroSchemaDir string        `json:"avro_schema_dir"`
+	Topics        []TopicConfig `json:"topics"`
+}
+
+type TopicConfig struct {
+	Topic   string `json:"topic"`
+	Handler string `json:"handler"`
+	// ContentType picks the decoder for messages without a content-type
+	// header. Defaults to JSON.
+	ContentType string      `json:"content_type"`
+	Retry       RetryConfig `json:"retry"`
+	// DLQTopic receives messages that could not be processed. Leave empty to
+	// only log and audit them.
+	DLQTopic string `json:"dlq_topic"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int `json:"max_attempts"`
+	// Backoff is a Go duration string such as "3s".
+	Backoff string `json:"backoff"`
+}
+
+// DefaultConsumerConfig is the single transaction stream consumed before
+// topics became configurable.
+func DefaultConsumerConfig() *ConsumerConfig {
+	return &ConsumerConfig{
+		Brokers:       []string{"localhost:9092"},
+		GroupID:       "casino-transaction-consumer",
+		AvroSchemaDir: "schemas/avro",
+		Topics: []TopicConfig{
+			{
+				Topic:   "casino-transactions-stream",
+				Handler: HandlerTransactions,
+				Retry:   RetryConfig{MaxAttempts: 3, Backoff: "3s"},
+			},
+		},
+	}
+}
+
+func LoadConsumerConfig(path string) (*ConsumerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kafka consumer config: %w", err)
+	}
+
+	config := &ConsumerConfig{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid kafka consumer config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func (c *ConsumerConfig) validate(handlers map[string]MessageHandler) error {
+	if len(c.Brokers) == 0 {
+		return fmt.Errorf("kafka consumer config has no brokers")
+	}
+	if c.GroupID == "" {
+		return fmt.Errorf("kafka consumer config has no group_id")
+	}
+	if len(c.Topics) == 0 {
+		return fmt.Errorf("kafka consumer config has no topics")
+	}
+
+	seen := make(map[string]bool)
+	for _, topic := range c.Topics {
+		if topic.Topic == "" {
+			return fmt.Errorf("kafka consumer config has a topic without a name")
+		}
+		if seen[topic.Topic] {
+			return fmt.Errorf("topic %s is configured twice", topic.Topic)
+		}
+		seen[topic.Topic] = true
+
+		if _, ok := handlers[topic.Handler]; !ok {
+			return fmt.Errorf("topic %s: unknown handler %q", topic.Topic, topic.Handler)
+		}
+		if topic.ContentType == ContentTypeAvro && c.AvroSchemaDir == "" {
+			return fmt.Errorf("topic %s: avro needs avro_schema_dir", topic.Topic)
+		}
+		if topic.ContentType != "" && topic.ContentType != ContentTypeAvro {
+			if _, ok := defaultDecoders[topic.ContentType]; !ok {
+				return fmt.Errorf("topic %s: unsupported content type %q", topic.Topic, topic.ContentType)
+			}
+		}
+		if topic.DLQTopic == topic.Topic {
+			return fmt.Errorf("topic %s: dlq_topic must differ from the topic", topic.Topic)
+		}
+		if _, err := topic.retryPolicy(); err != nil {
+			return fmt.Errorf("topic %s: %w", topic.Topic, err)
+		}
+	}
+	return nil
+}
+
+func (t TopicConfig) retryPolicy() (RetryPolicy, error) {
+	policy := RetryPolicy{MaxAttempts: t.Retry.MaxAttempts}
+	if t.Retry.MaxAttempts < 0 {
+		return policy, fmt.Errorf("retry max_attempts must not be negative")
+	}
+
+	if t.Retry.Backoff != "" {
+		backoff, err := time.ParseDuration(t.Retry.Backoff)
+		if err != nil || backoff < 0 {
+			return policy, fmt.Errorf("invalid retry backoff %q", t.Retry.Backoff)
+		}
+		policy.Backoff = backoff
+	}
+	return policy, nil
+}
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConsumerConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kafka.json")
+	config := `{
+		"brokers": ["kafka:29092"],
+		"group_id": "casino",
+		"topics": [
+			{"topic": "casino-rollbacks", "handler": "rollbacks", "content_type": "application/x-protobuf", "retry": {"max_attempts": 5, "backoff": "250ms"}, "dlq_topic": "casino-rollbacks-dlq"}
+		]
+	}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConsumerConfig(path)
+	if err != nil {
+		t.Fatalf("Expected config to load, got %v", err)
+	}
+	if err := loaded.validate(NewHandlers(&MockTransactionUseCase{})); err != nil {
+		t.Fatalf("Expected config to be valid, got %v", err)
+	}
+
+	topic := loaded.Topics[0]
+	if topic.Handler != HandlerRollbacks || topic.ContentType != ContentTypeProtobuf || topic.DLQTopic != "casino-rollbacks-dlq" {
+		t.Errorf("Unexpected topic config %+v", topic)
+	}
+	policy, _ := topic.retryPolicy()
+	if policy.MaxAttempts != 5 || policy.Backoff != 250*time.Millisecond {
+		t.Errorf("Unexpected retry policy %+v", policy)
+	}
+}
+
+func TestLoadConsumerConfig_Errors(t *testing.T) {
+	if _, err := LoadConsumerConfig(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+
+	path := filepath.Join(t.TempDir(), "kafka.json")
+	_ = os.WriteFile(path, []byte(`{"topics": [`), 0o644)
+	if _, err := LoadConsumerConfig(path); err == nil {
+		t.Error("Expected error for malformed JSON")
+	}
+}
+
+func TestConsumerConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mutate   func(config *ConsumerConfig)
+		expected string
+	}{
+		{"No Brokers", func(c *ConsumerConfig) { c.Brokers = nil }, "no brokers"},
+		{"No Group", func(c *ConsumerConfig) { c.GroupID = "" }, "no group_id"},
+		{"No Topics", func(c *ConsumerConfig) { c.Topics = nil }, "no topics"},
+		{"Duplicate Topic", func(c *ConsumerConfig) { c.Topics = append(c.Topics, c.Topics[0]) }, "configured twice"},
+		{"Unknown Handler", func(c *ConsumerConfig) { c.Topics[0].Handler = "bonuses" }, "unknown handler"},
+		{"Unknown Content Type", func(c *ConsumerConfig) { c.Topics[0].ContentType = "application/xml" }, "unsupported content type"},
+		{"Avro Without Schemas", func(c *ConsumerConfig) { c.AvroSchemaDir = ""; c.Topics[0].ContentType = ContentTypeAvro }, "avro_schema_dir"},
+		{"DLQ Loop", func(c *ConsumerConfig) { c.Topics[0].DLQTopic = c.Topics[0].Topic }, "dlq_topic"},
+		{"Bad Backoff", func(c *ConsumerConfig) { c.Topics[0].Retry.Backoff = "soon" }, "invalid retry backoff"},
+		{"Negative Attempts", func(c *ConsumerConfig) { c.Topics[0].Retry.MaxAttempts = -1 }, "max_attempts"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultConsumerConfig()
+			tc.mutate(config)
+
+			err := config.validate(NewHandlers(&MockTransactionUseCase{}))
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"casino/boundary/dto"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Pause stops the consumer from processing further messages and waits for
+// the one in flight. A message fetched while paused is held until Resume
+// without being committed, so a restart in the meantime delivers it again.
+func (kc *KafkaConsumer) Pause(ctx context.Context) error {
+	kc.mu.Lock()
+	if !kc.paused {
+		kc.paused = true
+		kc.resumed = make(chan struct{})
+	}
+	kc.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		kc.inFlight.Lock()
+		kc.inFlight.Unlock()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (kc *KafkaConsumer) Resume() {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if kc.paused {
+		kc.paused = false
+		close(kc.resumed)
+	}
+}
+
+// acquire waits until the consumer is not paused and takes inFlight. It
+// returns false if ctx is cancelled first.
+func (kc *KafkaConsumer) acquire(ctx context.Context) bool {
+	for {
+		kc.mu.Lock()
+		resumed := kc.resumed
+		paused := kc.paused
+		kc.mu.Unlock()
+
+		if paused {
+			select {
+			case <-ctx.Done():
+				return false
+			case <-resumed:
+			}
+		}
+
+		kc.inFlight.Lock()
+		kc.mu.Lock()
+		paused = kc.paused
+		kc.mu.Unlock()
+		if !paused {
+			return true
+		}
+		kc.inFlight.Unlock()
+	}
+}
+
+func (kc *KafkaConsumer) received(message kafka.Message) {
+	at := message.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	kc.mu.Lock()
+	kc.lastMessageAt = at
+	kc.mu.Unlock()
+}
+
+func (kc *KafkaConsumer) setRunning(running bool) {
+	kc.mu.Lock()
+	kc.running = running
+	kc.mu.Unlock()
+}
+
+func (kc *KafkaConsumer) setRetrying(retrying bool) {
+	kc.mu.Lock()
+	kc.retrying = retrying
+	kc.mu.Unlock()
+}
+
+func (kc *KafkaConsumer) status() *dto.TopicStatusDTO {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	status := &dto.TopicStatusDTO{Topic: kc.reader.Config().Topic}
+	switch {
+	case kc.paused:
+		status.State = dto.ConsumerStatePaused
+	case !kc.running:
+		status.State = dto.ConsumerStateStopped
+	case kc.retrying:
+		status.State = dto.ConsumerStateRetrying
+	default:
+		status.State = dto.ConsumerStateRunning
+	}
+
+	if !kc.lastMessageAt.IsZero() {
+		lastMessageAt := kc.lastMessageAt
+		status.LastMessageAt = &lastMessageAt
+	}
+	return status
+}
@@ -0,0 +1,191 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"casino/boundary/dto"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// channelKafkaReader hands out messages sent on a channel so tests decide
+// when the consumer gets its next message.
+type channelKafkaReader struct {
+	messages  chan kafka.Message
+	mu        sync.Mutex
+	committed int
+}
+
+func (r *channelKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	case message := <-r.messages:
+		return message, nil
+	}
+}
+
+func (r *channelKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	r.committed += len(msgs)
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *channelKafkaReader) commits() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.committed
+}
+
+func (r *channelKafkaReader) Config() kafka.ReaderConfig {
+	return kafka.ReaderConfig{Topic: "casino-transactions-stream", GroupID: "casino"}
+}
+
+func (r *channelKafkaReader) Close() error {
+	return nil
+}
+
+// blockingHandler counts messages and blocks each one until released.
+type blockingHandler struct {
+	mu      sync.Mutex
+	handled int
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *blockingHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	h.started <- struct{}{}
+	<-h.release
+	h.mu.Lock()
+	h.handled++
+	h.mu.Unlock()
+	return auditActionProcessTransaction, nil
+}
+
+func (h *blockingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.handled
+}
+
+func newControlledConsumer() (*KafkaConsumer, *channelKafkaReader, *blockingHandler) {
+	reader := &channelKafkaReader{messages: make(chan kafka.Message)}
+	handler := &blockingHandler{started: make(chan struct{}, 1), release: make(chan struct{})}
+	return &KafkaConsumer{
+		reader:       reader,
+		handler:      handler,
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+	}, reader, handler
+}
+
+func TestKafkaConsumer_PauseWaitsForMessageInFlight(t *testing.T) {
+	consumer, reader, handler := newControlledConsumer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Start(ctx)
+
+	reader.messages <- kafka.Message{Value: []byte(`{}`)}
+	<-handler.started
+
+	paused := make(chan error, 1)
+	go func() { paused <- consumer.Pause(context.Background()) }()
+
+	select {
+	case <-paused:
+		t.Fatal("Expected Pause to wait for the message in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(handler.release)
+	if err := <-paused; err != nil {
+		t.Fatalf("Expected Pause to succeed once drained, got %v", err)
+	}
+	if status := consumer.status(); status.State != dto.ConsumerStatePaused {
+		t.Errorf("Expected paused state, got %s", status.State)
+	}
+
+	// A message fetched while paused is held, uncommitted, until Resume.
+	reader.messages <- kafka.Message{Value: []byte(`{}`)}
+	time.Sleep(20 * time.Millisecond)
+	if handler.count() != 1 {
+		t.Fatalf("Expected no processing while paused, got %d messages", handler.count())
+	}
+	if reader.commits() != 1 {
+		t.Fatalf("Expected only the processed message to be committed, got %d commits", reader.commits())
+	}
+
+	consumer.Resume()
+	<-handler.started
+	if status := consumer.status(); status.State != dto.ConsumerStateRunning {
+		t.Errorf("Expected running state after resume, got %s", status.State)
+	}
+}
+
+func TestKafkaConsumer_PauseTimeout(t *testing.T) {
+	consumer, reader, handler := newControlledConsumer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go consumer.Start(ctx)
+
+	reader.messages <- kafka.Message{Value: []byte(`{}`)}
+	<-handler.started
+
+	pauseCtx, pauseCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer pauseCancel()
+	if err := consumer.Pause(pauseCtx); err != context.DeadlineExceeded {
+		t.Errorf("Expected the drain to time out, got %v", err)
+	}
+	if status := consumer.status(); status.State != dto.ConsumerStatePaused {
+		t.Errorf("Expected the consumer to stay paused, got %s", status.State)
+	}
+	close(handler.release)
+}
+
+func TestKafkaConsumer_StopsWhilePaused(t *testing.T) {
+	consumer, reader, _ := newControlledConsumer()
+	if err := consumer.Pause(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		consumer.Start(ctx)
+		close(stopped)
+	}()
+
+	reader.messages <- kafka.Message{Value: []byte(`{}`)}
+	cancel()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Expected a paused consumer to stop on cancellation")
+	}
+	if reader.commits() != 0 {
+		t.Error("Expected the held message to stay uncommitted")
+	}
+}
+
+func TestKafkaConsumer_Status(t *testing.T) {
+	consumer, _, _ := newControlledConsumer()
+
+	if status := consumer.status(); status.State != dto.ConsumerStateStopped || status.LastMessageAt != nil || status.Topic != "casino-transactions-stream" {
+		t.Errorf("Expected a stopped consumer without messages, got %+v", status)
+	}
+
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	consumer.received(kafka.Message{Time: at})
+	consumer.setRunning(true)
+	consumer.setRetrying(true)
+
+	status := consumer.status()
+	if status.State != dto.ConsumerStateRetrying || !status.LastMessageAt.Equal(at) {
+		t.Errorf("Expected retrying with the last message time, got %+v", status)
+	}
+}
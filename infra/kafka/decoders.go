@@ -0,0 +1,166 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"casino/infra/kafka/pb"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+// The content-type message header selects the decoder. Messages without it
+// are JSON, which is all producers sent before the envelope existed.
+const (
+	ContentTypeHeader   = "content-type"
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeAvro     = "application/avro"
+)
+
+type MessageDecoder interface {
+	// Decode returns the envelope as written. Upcasting is up to the caller.
+	Decode(value []byte) (*TransactionEnvelope, error)
+}
+
+// defaultDecoders need no configuration. Avro needs a schema registry and is
+// added with KafkaConsumer.RegisterDecoder.
+var defaultDecoders = map[string]MessageDecoder{
+	ContentTypeJSON:     JSONDecoder{},
+	ContentTypeProtobuf: ProtobufDecoder{},
+}
+
+type JSONDecoder struct{}
+
+func (JSONDecoder) Decode(value []byte) (*TransactionEnvelope, error) {
+	var probe struct {
+		SchemaVersion *int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(value, &probe); err != nil {
+		return nil, err
+	}
+
+	// Without a schema version this is a bare version 1 transaction.
+	if probe.SchemaVersion == nil {
+		envelope := &TransactionEnvelope{SchemaVersion: 1}
+		if err := json.Unmarshal(value, &envelope.Transaction); err != nil {
+			return nil, err
+		}
+		return envelope, nil
+	}
+
+	envelope := &TransactionEnvelope{}
+	if err := json.Unmarshal(value, envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+type ProtobufDecoder struct{}
+
+func (ProtobufDecoder) Decode(value []byte) (*TransactionEnvelope, error) {
+	message := &pb.TransactionEnvelope{}
+	if err := proto.Unmarshal(value, message); err != nil {
+		return nil, err
+	}
+
+	transaction := message.GetTransaction()
+	return &TransactionEnvelope{
+		SchemaVersion: int(message.GetSchemaVersion()),
+		EventType:     message.GetEventType(),
+		Transaction: TransactionMessage{
+			ID:                    transaction.GetId(),
+			UserID:                transaction.GetUserId(),
+			TransactionType:       transaction.GetTransactionType(),
+			Amount:                uint(transaction.GetAmount()),
+			Currency:              transaction.GetCurrency(),
+			RoundID:               transaction.GetRoundId(),
+			GameID:                transaction.GetGameId(),
+			ProviderID:            transaction.GetProviderId(),
+			OriginalTransactionID: transaction.GetOriginalTransactionId(),
+			Reason:                transaction.GetReason(),
+		},
+	}, nil
+}
+
+// FileSchemaRegistry serves Avro schemas from a directory holding one
+// <schema id>.avsc file per schema. Schemas are read on first use.
+type FileSchemaRegistry struct {
+	dir     string
+	mu      sync.Mutex
+	schemas map[uint32]avro.Schema
+}
+
+func NewFileSchemaRegistry(dir string) *FileSchemaRegistry {
+	return &FileSchemaRegistry{
+		dir:     dir,
+		schemas: make(map[uint32]avro.Schema),
+	}
+}
+
+func (r *FileSchemaRegistry) Schema(id uint32) (avro.Schema, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if schema, ok := r.schemas[id]; ok {
+		return schema, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(r.dir, strconv.FormatUint(uint64(id), 10)+".avsc"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown avro schema %d: %w", id, err)
+	}
+
+	schema, err := avro.Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema %d: %w", id, err)
+	}
+
+	r.schemas[id] = schema
+	return schema, nil
+}
+
+// AvroDecoder reads the Confluent wire format: a zero magic byte, the
+// big-endian schema ID and the Avro binary encoded envelope.
+type AvroDecoder struct {
+	registry *FileSchemaRegistry
+}
+
+func NewAvroDecoder(registry *FileSchemaRegistry) *AvroDecoder {
+	return &AvroDecoder{registry: registry}
+}
+
+func (d *AvroDecoder) Decode(value []byte) (*TransactionEnvelope, error) {
+	if len(value) < 5 || value[0] != 0 {
+		return nil, fmt.Errorf("avro message without schema ID")
+	}
+
+	schema, err := d.registry.Schema(binary.BigEndian.Uint32(value[1:5]))
+	if err != nil {
+		return nil, err
+	}
+
+	// Decoding into a generic value and going through JSON lets any schema
+	// whose field names match the envelope be used, whatever it adds.
+	var decoded map[string]any
+	if err := avro.Unmarshal(schema, value[5:], &decoded); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope := &TransactionEnvelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
@@ -0,0 +1,148 @@
+package kafka
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"casino/infra/kafka/pb"
+
+	"github.com/hamba/avro/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestJSONDecoder(t *testing.T) {
+	testCases := []struct {
+		name            string
+		value           string
+		expectedVersion int
+		expectedEvent   string
+	}{
+		{"Bare Version 1", `{"id": "tx-1", "transaction_type": "bet", "amount": 100}`, 1, ""},
+		{"Envelope", `{"schema_version": 2, "event_type": "transaction.created", "transaction": {"id": "tx-1", "transaction_type": "bet", "amount": 100}}`, 2, EventTypeTransactionCreated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envelope, err := JSONDecoder{}.Decode([]byte(tc.value))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if envelope.SchemaVersion != tc.expectedVersion || envelope.EventType != tc.expectedEvent {
+				t.Errorf("Unexpected envelope %+v", envelope)
+			}
+			if envelope.Transaction.ID != "tx-1" || envelope.Transaction.Amount != 100 {
+				t.Errorf("Unexpected transaction %+v", envelope.Transaction)
+			}
+		})
+	}
+
+	if _, err := (JSONDecoder{}).Decode([]byte(`not json`)); err == nil {
+		t.Error("Expected error for malformed JSON")
+	}
+}
+
+func TestProtobufDecoder(t *testing.T) {
+	value, err := proto.Marshal(&pb.TransactionEnvelope{
+		SchemaVersion: 2,
+		EventType:     EventTypeTransactionCreated,
+		Transaction: &pb.TransactionMessage{
+			Id:              "tx-1",
+			UserId:          "user-1",
+			TransactionType: "win",
+			Amount:          250,
+			Currency:        "USD",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+
+	envelope, err := ProtobufDecoder{}.Decode(value)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if envelope.SchemaVersion != 2 || envelope.EventType != EventTypeTransactionCreated {
+		t.Errorf("Unexpected envelope %+v", envelope)
+	}
+	if envelope.Transaction.UserID != "user-1" || envelope.Transaction.Amount != 250 || envelope.Transaction.Currency != "USD" {
+		t.Errorf("Unexpected transaction %+v", envelope.Transaction)
+	}
+}
+
+func avroMessage(t *testing.T, schemaID uint32, schema avro.Schema, value any) []byte {
+	t.Helper()
+	data, err := avro.Marshal(schema, value)
+	if err != nil {
+		t.Fatalf("Failed to encode avro: %v", err)
+	}
+
+	message := []byte{0}
+	message = binary.BigEndian.AppendUint32(message, schemaID)
+	return append(message, data...)
+}
+
+func TestAvroDecoder(t *testing.T) {
+	registry := NewFileSchemaRegistry(filepath.Join("..", "..", "schemas", "avro"))
+	schema, err := registry.Schema(1)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	value := avroMessage(t, 1, schema, map[string]any{
+		"schema_version": 2,
+		"event_type":     EventTypeTransactionCreated,
+		"transaction": map[string]any{
+			"id":                      "tx-1",
+			"user_id":                 "user-1",
+			"transaction_type":        "bet",
+			"amount":                  int64(500),
+			"currency":                "EUR",
+			"round_id":                "round-1",
+			"game_id":                 "",
+			"provider_id":             "",
+			"original_transaction_id": "",
+			"reason":                  "",
+		},
+	})
+
+	envelope, err := NewAvroDecoder(registry).Decode(value)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if envelope.SchemaVersion != 2 || envelope.EventType != EventTypeTransactionCreated {
+		t.Errorf("Unexpected envelope %+v", envelope)
+	}
+	if envelope.Transaction.ID != "tx-1" || envelope.Transaction.Amount != 500 || envelope.Transaction.RoundID != "round-1" {
+		t.Errorf("Unexpected transaction %+v", envelope.Transaction)
+	}
+}
+
+func TestAvroDecoder_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "2.avsc"), []byte(`{"type": "nonsense"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write schema: %v", err)
+	}
+	decoder := NewAvroDecoder(NewFileSchemaRegistry(dir))
+
+	testCases := []struct {
+		name  string
+		value []byte
+	}{
+		{"No Magic Byte", []byte{1, 0, 0, 0, 1, 2}},
+		{"Too Short", []byte{0, 0}},
+		{"Unknown Schema", []byte{0, 0, 0, 0, 9, 2}},
+		{"Invalid Schema", []byte{0, 0, 0, 0, 2, 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := decoder.Decode(tc.value); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
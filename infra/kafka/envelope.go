@@ -0,0 +1,73 @@
+package kafka
+
+import (
+	"fmt"
+
+	"casino/domain/entity"
+)
+
+// CurrentSchemaVersion is the envelope version the consumer works with.
+// Older messages are upcast to it, newer ones are rejected.
+const CurrentSchemaVersion = 3
+
+// weiPerGwei converts the ETH amounts of version 2 and older, which were in
+// wei, to the gwei amounts of version 3.
+const weiPerGwei = 1_000_000_000
+
+const (
+	EventTypeTransactionCreated   = "transaction.created"
+	EventTypeTransactionCancelled = "transaction.cancelled"
+)
+
+// TransactionEnvelope wraps a transaction stream message with the schema
+// version it was written with and what kind of event it is.
+type TransactionEnvelope struct {
+	SchemaVersion int                `json:"schema_version"`
+	EventType     string             `json:"event_type"`
+	Transaction   TransactionMessage `json:"transaction"`
+}
+
+// upcasters[v] turns a version v envelope into a version v+1 envelope.
+var upcasters = map[int]func(envelope *TransactionEnvelope) error{
+	// Version 1 messages were bare transactions without an envelope. A
+	// rollback meant cancelling the original, anything else a new transaction.
+	1: func(envelope *TransactionEnvelope) error {
+		envelope.EventType = EventTypeTransactionCreated
+		if entity.TransactionType(envelope.Transaction.TransactionType) == entity.TransactionTypeRollback {
+			envelope.EventType = EventTypeTransactionCancelled
+		}
+		return nil
+	},
+	// Version 3 counts ETH in gwei instead of wei. Amounts that are not a
+	// whole number of gwei cannot be stored and are rejected.
+	2: func(envelope *TransactionEnvelope) error {
+		transaction := &envelope.Transaction
+		if entity.Currency(transaction.Currency) != entity.CurrencyETH {
+			return nil
+		}
+		if transaction.Amount%weiPerGwei != 0 {
+			return fmt.Errorf("ETH amount %d wei is not a whole number of gwei", transaction.Amount)
+		}
+		transaction.Amount /= weiPerGwei
+		return nil
+	},
+}
+
+func upcast(envelope *TransactionEnvelope) error {
+	if envelope.SchemaVersion < 1 || envelope.SchemaVersion > CurrentSchemaVersion {
+		return fmt.Errorf("unsupported schema version %d", envelope.SchemaVersion)
+	}
+
+	for envelope.SchemaVersion < CurrentSchemaVersion {
+		upcaster, ok := upcasters[envelope.SchemaVersion]
+		if !ok {
+			return fmt.Errorf("no upcaster from schema version %d", envelope.SchemaVersion)
+		}
+		if err := upcaster(envelope); err != nil {
+			return fmt.Errorf("failed to upcast schema version %d: %w", envelope.SchemaVersion, err)
+		}
+		envelope.SchemaVersion++
+	}
+
+	return nil
+}
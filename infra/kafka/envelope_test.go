@@ -0,0 +1,72 @@
+package kafka
+
+import (
+	"testing"
+)
+
+func TestUpcast(t *testing.T) {
+	testCases := []struct {
+		name              string
+		envelope          TransactionEnvelope
+		expectedEventType string
+	}{
+		{"Version 1 Bet", TransactionEnvelope{SchemaVersion: 1, Transaction: TransactionMessage{TransactionType: "bet"}}, EventTypeTransactionCreated},
+		{"Version 1 Rollback", TransactionEnvelope{SchemaVersion: 1, Transaction: TransactionMessage{TransactionType: "rollback"}}, EventTypeTransactionCancelled},
+		{"Current Version", TransactionEnvelope{SchemaVersion: CurrentSchemaVersion, EventType: EventTypeTransactionCreated}, EventTypeTransactionCreated},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envelope := tc.envelope
+			if err := upcast(&envelope); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if envelope.SchemaVersion != CurrentSchemaVersion {
+				t.Errorf("Expected schema version %d, got %d", CurrentSchemaVersion, envelope.SchemaVersion)
+			}
+			if envelope.EventType != tc.expectedEventType {
+				t.Errorf("Expected event type %s, got %s", tc.expectedEventType, envelope.EventType)
+			}
+		})
+	}
+}
+
+func TestUpcast_ETHAmountsToGwei(t *testing.T) {
+	testCases := []struct {
+		name     string
+		envelope TransactionEnvelope
+		expected uint
+	}{
+		{"Version 2 Wei", TransactionEnvelope{SchemaVersion: 2, Transaction: TransactionMessage{Currency: "ETH", Amount: 1_500_000_000_000_000_000}}, 1_500_000_000},
+		{"Version 1 Wei", TransactionEnvelope{SchemaVersion: 1, Transaction: TransactionMessage{Currency: "ETH", Amount: 2_000_000_000}}, 2},
+		{"Version 2 Other Currency", TransactionEnvelope{SchemaVersion: 2, Transaction: TransactionMessage{Currency: "BTC", Amount: 1_000}}, 1_000},
+		{"Current Version Gwei", TransactionEnvelope{SchemaVersion: CurrentSchemaVersion, Transaction: TransactionMessage{Currency: "ETH", Amount: 1_000}}, 1_000},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			envelope := tc.envelope
+			if err := upcast(&envelope); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if envelope.Transaction.Amount != tc.expected {
+				t.Errorf("Expected amount %d, got %d", tc.expected, envelope.Transaction.Amount)
+			}
+		})
+	}
+}
+
+func TestUpcast_RejectsSubGweiAmounts(t *testing.T) {
+	envelope := TransactionEnvelope{SchemaVersion: 2, Transaction: TransactionMessage{Currency: "ETH", Amount: 1_500_000_001}}
+	if err := upcast(&envelope); err == nil {
+		t.Error("Expected an ETH amount with a fraction of a gwei to be rejected")
+	}
+}
+
+func TestUpcast_UnsupportedVersion(t *testing.T) {
+	for _, version := range []int{0, CurrentSchemaVersion + 1} {
+		if err := upcast(&TransactionEnvelope{SchemaVersion: version}); err == nil {
+			t.Errorf("Expected error for schema version %d", version)
+		}
+	}
+}
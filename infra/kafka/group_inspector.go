@@ -0,0 +1,120 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"casino/boundary/dto"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// groupInspector asks the brokers how far a consumer group has come on each
+// partition of its topics.
+type groupInspector interface {
+	Partitions(ctx context.Context, groupID string, topics []string) (map[string][]*dto.PartitionStatusDTO, error)
+}
+
+type brokerGroupInspector struct {
+	client *kafka.Client
+}
+
+func newBrokerGroupInspector(brokers []string) *brokerGroupInspector {
+	return &brokerGroupInspector{client: &kafka.Client{Addr: kafka.TCP(brokers...)}}
+}
+
+func (i *brokerGroupInspector) Partitions(ctx context.Context, groupID string, topics []string) (map[string][]*dto.PartitionStatusDTO, error) {
+	metadata, err := i.client.Metadata(ctx, &kafka.MetadataRequest{Topics: topics})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read topic metadata: %w", err)
+	}
+
+	partitionIDs := make(map[string][]int)
+	offsetRequests := make(map[string][]kafka.OffsetRequest)
+	for _, topic := range metadata.Topics {
+		if topic.Error != nil {
+			return nil, fmt.Errorf("topic %s: %w", topic.Name, topic.Error)
+		}
+		for _, partition := range topic.Partitions {
+			partitionIDs[topic.Name] = append(partitionIDs[topic.Name], partition.ID)
+			offsetRequests[topic.Name] = append(offsetRequests[topic.Name], kafka.LastOffsetOf(partition.ID))
+		}
+	}
+
+	offsets, err := i.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: offsetRequests})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read high water marks: %w", err)
+	}
+
+	committed, err := i.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: groupID, Topics: partitionIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read committed offsets: %w", err)
+	}
+	if committed.Error != nil {
+		return nil, fmt.Errorf("failed to read committed offsets: %w", committed.Error)
+	}
+
+	groups, err := i.client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{groupID}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe consumer group: %w", err)
+	}
+
+	assignees := make(map[string]map[int]string)
+	for _, group := range groups.Groups {
+		for _, member := range group.Members {
+			for _, topic := range member.MemberAssignments.Topics {
+				if assignees[topic.Topic] == nil {
+					assignees[topic.Topic] = make(map[int]string)
+				}
+				for _, partition := range topic.Partitions {
+					assignees[topic.Topic][partition] = member.ClientID + "@" + member.ClientHost
+				}
+			}
+		}
+	}
+
+	result := make(map[string][]*dto.PartitionStatusDTO)
+	for topic, partitionOffsets := range offsets.Topics {
+		committedOffsets := make(map[int]int64)
+		for _, partition := range committed.Topics[topic] {
+			committedOffsets[partition.Partition] = partition.CommittedOffset
+		}
+
+		for _, partitionOffset := range partitionOffsets {
+			if partitionOffset.Error != nil {
+				return nil, fmt.Errorf("topic %s partition %d: %w", topic, partitionOffset.Partition, partitionOffset.Error)
+			}
+			committedOffset, ok := committedOffsets[partitionOffset.Partition]
+			if !ok {
+				committedOffset = -1
+			}
+			result[topic] = append(result[topic], partitionStatus(
+				partitionOffset.Partition,
+				assignees[topic][partitionOffset.Partition],
+				committedOffset,
+				partitionOffset.LastOffset,
+			))
+		}
+		sort.Slice(result[topic], func(a, b int) bool {
+			return result[topic][a].Partition < result[topic][b].Partition
+		})
+	}
+	return result, nil
+}
+
+// partitionStatus takes a negative committed offset to mean the group has
+// not committed anything yet.
+func partitionStatus(partition int, assignedTo string, committed, highWaterMark int64) *dto.PartitionStatusDTO {
+	status := &dto.PartitionStatusDTO{
+		Partition:     partition,
+		AssignedTo:    assignedTo,
+		HighWaterMark: highWaterMark,
+	}
+	if committed >= 0 {
+		lag := max(highWaterMark-committed, 0)
+		status.CommittedOffset = &committed
+		status.Lag = &lag
+	}
+	return status
+}
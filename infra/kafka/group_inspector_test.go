@@ -0,0 +1,28 @@
+package kafka
+
+import (
+	"testing"
+)
+
+func TestPartitionStatus(t *testing.T) {
+	status := partitionStatus(2, "casino@host-1", 90, 100)
+	if status.Partition != 2 || status.AssignedTo != "casino@host-1" || status.HighWaterMark != 100 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+	if *status.CommittedOffset != 90 || *status.Lag != 10 {
+		t.Errorf("Expected committed 90 and lag 10, got %d and %d", *status.CommittedOffset, *status.Lag)
+	}
+}
+
+func TestPartitionStatus_NothingCommitted(t *testing.T) {
+	status := partitionStatus(0, "", -1, 100)
+	if status.CommittedOffset != nil || status.Lag != nil {
+		t.Errorf("Expected unknown committed offset and lag, got %+v", status)
+	}
+}
+
+func TestPartitionStatus_CommitPastHighWaterMark(t *testing.T) {
+	if status := partitionStatus(0, "", 120, 100); *status.Lag != 0 {
+		t.Errorf("Expected lag not to go negative, got %d", *status.Lag)
+	}
+}
@@ -0,0 +1,178 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"casino/boundary/dto"
+	"casino/boundary/usecase"
+	"casino/domain/entity"
+	"casino/utils"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Handler names topics are bound to in the consumer configuration.
+const (
+	HandlerTransactions = "transactions"
+	HandlerRollbacks    = "rollbacks"
+	HandlerDeposits     = "deposits"
+	HandlerPlayerEvents = "player_events"
+)
+
+const auditActionPlayerEvent = "player.event"
+
+// MessageHandler processes the messages of the topics it is bound to.
+type MessageHandler interface {
+	// Handle returns the audit action the message is recorded under along
+	// with the processing error, if any.
+	Handle(ctx context.Context, message *Message) (string, error)
+}
+
+// Message is a consumed record together with the decoder configured for
+// the topic it came from.
+type Message struct {
+	kafka.Message
+	decode func(kafka.Message) (*TransactionEnvelope, error)
+}
+
+// Envelope decodes the message and brings it up to CurrentSchemaVersion.
+// Messages that cannot be decoded are reported as validation errors.
+func (m *Message) Envelope() (*TransactionEnvelope, error) {
+	envelope, err := m.decode(m.Message)
+	if err != nil {
+		return nil, &utils.TransactionValidationError{Reason: err.Error()}
+	}
+	return envelope, nil
+}
+
+// NewHandlers returns every built-in handler keyed by the name topics
+// refer to it with.
+func NewHandlers(useCase usecase.TransactionUseCase) map[string]MessageHandler {
+	return map[string]MessageHandler{
+		HandlerTransactions: NewTransactionHandler(useCase),
+		HandlerRollbacks:    NewRollbackHandler(useCase),
+		HandlerDeposits:     NewDepositHandler(useCase),
+		HandlerPlayerEvents: &PlayerEventHandler{},
+	}
+}
+
+// TransactionHandler routes transaction stream events to the use case by
+// event type.
+type TransactionHandler struct {
+	useCase usecase.TransactionUseCase
+}
+
+func NewTransactionHandler(useCase usecase.TransactionUseCase) *TransactionHandler {
+	return &TransactionHandler{useCase: useCase}
+}
+
+func (h *TransactionHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	envelope, err := message.Envelope()
+	if err != nil {
+		return auditActionProcessTransaction, err
+	}
+
+	switch envelope.EventType {
+	case EventTypeTransactionCreated:
+		return auditActionProcessTransaction, h.useCase.ProcessTransaction(createTransactionDTO(&envelope.Transaction))
+	case EventTypeTransactionCancelled:
+		_, err := h.useCase.CancelTransaction(cancelTransactionDTO(&envelope.Transaction))
+		return auditActionCancelTransaction, err
+	default:
+		return auditActionProcessTransaction, &utils.TransactionValidationError{TransactionID: envelope.Transaction.ID, Reason: fmt.Sprintf("unknown event type %q", envelope.EventType)}
+	}
+}
+
+// RollbackHandler serves topics that only carry cancellations.
+type RollbackHandler struct {
+	useCase usecase.TransactionUseCase
+}
+
+func NewRollbackHandler(useCase usecase.TransactionUseCase) *RollbackHandler {
+	return &RollbackHandler{useCase: useCase}
+}
+
+func (h *RollbackHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	envelope, err := message.Envelope()
+	if err != nil {
+		return auditActionCancelTransaction, err
+	}
+
+	if envelope.EventType != EventTypeTransactionCancelled {
+		return auditActionCancelTransaction, &utils.TransactionValidationError{TransactionID: envelope.Transaction.ID, Reason: fmt.Sprintf("event type %q on a rollback topic", envelope.EventType)}
+	}
+
+	_, err = h.useCase.CancelTransaction(cancelTransactionDTO(&envelope.Transaction))
+	return auditActionCancelTransaction, err
+}
+
+// DepositHandler serves payment provider topics, which may only create
+// deposits.
+type DepositHandler struct {
+	useCase usecase.TransactionUseCase
+}
+
+func NewDepositHandler(useCase usecase.TransactionUseCase) *DepositHandler {
+	return &DepositHandler{useCase: useCase}
+}
+
+func (h *DepositHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	envelope, err := message.Envelope()
+	if err != nil {
+		return auditActionProcessTransaction, err
+	}
+
+	transaction := &envelope.Transaction
+	if envelope.EventType != EventTypeTransactionCreated || entity.TransactionType(transaction.TransactionType) != entity.TransactionTypeDeposit {
+		return auditActionProcessTransaction, &utils.TransactionValidationError{TransactionID: transaction.ID, Reason: fmt.Sprintf("%s %s on a deposit topic", envelope.EventType, transaction.TransactionType)}
+	}
+
+	return auditActionProcessTransaction, h.useCase.ProcessTransaction(createTransactionDTO(transaction))
+}
+
+// PlayerEventHandler accepts player lifecycle events. Nothing acts on them
+// yet; checking they are well formed lets the consumer keep them in the
+// audit log.
+type PlayerEventHandler struct{}
+
+type playerEventMessage struct {
+	EventType string `json:"event_type"`
+	UserID    string `json:"user_id"`
+}
+
+func (h *PlayerEventHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	var event playerEventMessage
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return auditActionPlayerEvent, &utils.TransactionValidationError{Reason: err.Error()}
+	}
+
+	if event.EventType == "" || event.UserID == "" {
+		return auditActionPlayerEvent, &utils.TransactionValidationError{Reason: "player event requires event_type and user_id"}
+	}
+	return auditActionPlayerEvent, nil
+}
+
+func createTransactionDTO(transaction *TransactionMessage) *dto.CreateTransactionDTO {
+	return &dto.CreateTransactionDTO{
+		ID:                    transaction.ID,
+		UserID:                transaction.UserID,
+		TransactionType:       transaction.TransactionType,
+		Amount:                transaction.Amount,
+		Currency:              transaction.Currency,
+		RoundID:               transaction.RoundID,
+		GameID:                transaction.GameID,
+		ProviderID:            transaction.ProviderID,
+		OriginalTransactionID: transaction.OriginalTransactionID,
+		Reason:                transaction.Reason,
+	}
+}
+
+func cancelTransactionDTO(transaction *TransactionMessage) *dto.CancelTransactionDTO {
+	return &dto.CancelTransactionDTO{
+		ID:                    transaction.ID,
+		OriginalTransactionID: transaction.OriginalTransactionID,
+		Reason:                transaction.Reason,
+	}
+}
@@ -0,0 +1,129 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"casino/utils"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func newTestMessage(value string) *Message {
+	return &Message{Message: kafka.Message{Value: []byte(value)}, decode: (&KafkaConsumer{}).decode}
+}
+
+func TestRollbackHandler_Handle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectCancels int
+		expectInvalid bool
+	}{
+		{"Bare Rollback", `{"id": "r1", "transaction_type": "rollback", "original_transaction_id": "t1"}`, 1, false},
+		{"Cancelled Envelope", `{"schema_version": 2, "event_type": "transaction.cancelled", "transaction": {"id": "r1", "original_transaction_id": "t1"}}`, 1, false},
+		{"Created Event", `{"id": "t1", "transaction_type": "bet", "amount": 100}`, 0, true},
+		{"Malformed", `{"amount": "bad"}`, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUseCase := &MockTransactionUseCase{}
+
+			action, err := NewRollbackHandler(mockUseCase).Handle(context.Background(), newTestMessage(tc.value))
+
+			if action != auditActionCancelTransaction {
+				t.Errorf("Expected cancel action, got %s", action)
+			}
+			if mockUseCase.cancelCount != tc.expectCancels || mockUseCase.processCount != 0 {
+				t.Errorf("Expected %d cancels and no processing, got %d and %d", tc.expectCancels, mockUseCase.cancelCount, mockUseCase.processCount)
+			}
+			if utils.IsTransactionValidation(err) != tc.expectInvalid {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_Handle_PassesReason(t *testing.T) {
+	mockUseCase := &MockTransactionUseCase{}
+	value := `{"schema_version": 2, "event_type": "transaction.created", "transaction": {"id": "f1", "user_id": "u1", "transaction_type": "refund", "amount": 100, "original_transaction_id": "t1", "reason": "game malfunction"}}`
+
+	action, err := NewTransactionHandler(mockUseCase).Handle(context.Background(), newTestMessage(value))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if action != auditActionProcessTransaction || mockUseCase.processCount != 1 {
+		t.Fatalf("Expected the refund to be processed, got %s and %d calls", action, mockUseCase.processCount)
+	}
+	if mockUseCase.lastProcess.Reason != "game malfunction" || mockUseCase.lastProcess.OriginalTransactionID != "t1" {
+		t.Errorf("Expected the reason to reach the use case, got %+v", mockUseCase.lastProcess)
+	}
+}
+
+func TestDepositHandler_Handle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectProcess int
+		expectInvalid bool
+	}{
+		{"Deposit", `{"id": "d1", "user_id": "u1", "transaction_type": "deposit", "amount": 500}`, 1, false},
+		{"Bet", `{"id": "b1", "user_id": "u1", "transaction_type": "bet", "amount": 500}`, 0, true},
+		{"Rollback", `{"id": "r1", "transaction_type": "rollback", "original_transaction_id": "d1"}`, 0, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUseCase := &MockTransactionUseCase{}
+
+			action, err := NewDepositHandler(mockUseCase).Handle(context.Background(), newTestMessage(tc.value))
+
+			if action != auditActionProcessTransaction {
+				t.Errorf("Expected process action, got %s", action)
+			}
+			if mockUseCase.processCount != tc.expectProcess || mockUseCase.cancelCount != 0 {
+				t.Errorf("Expected %d processed and no cancels, got %d and %d", tc.expectProcess, mockUseCase.processCount, mockUseCase.cancelCount)
+			}
+			if utils.IsTransactionValidation(err) != tc.expectInvalid {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestPlayerEventHandler_Handle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectInvalid bool
+	}{
+		{"Valid", `{"event_type": "player.registered", "user_id": "u1"}`, false},
+		{"Missing User", `{"event_type": "player.registered"}`, true},
+		{"Malformed", `not json`, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			action, err := (&PlayerEventHandler{}).Handle(context.Background(), newTestMessage(tc.value))
+
+			if action != auditActionPlayerEvent {
+				t.Errorf("Expected player event action, got %s", action)
+			}
+			if utils.IsTransactionValidation(err) != tc.expectInvalid {
+				t.Errorf("Unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestNewHandlers(t *testing.T) {
+	handlers := NewHandlers(&MockTransactionUseCase{})
+
+	for _, name := range []string{HandlerTransactions, HandlerRollbacks, HandlerDeposits, HandlerPlayerEvents} {
+		if handlers[name] == nil {
+			t.Errorf("Expected a %s handler", name)
+		}
+	}
+}
@@ -2,38 +2,91 @@ package kafka
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"casino/boundary/dto"
 	"casino/boundary/logging"
+	"casino/boundary/repository"
 	"casino/boundary/usecase"
 	"casino/utils"
 
 	"github.com/segmentio/kafka-go"
 )
 
+const (
+	auditActor                    = "kafka-consumer"
+	auditActionProcessTransaction = "transaction.process"
+	auditActionCancelTransaction  = "transaction.cancel"
+
+	CorrelationIDHeader = "correlation_id"
+	RequestIDHeader     = "request_id"
+
+	DLQTopicHeader     = "dlq-original-topic"
+	DLQPartitionHeader = "dlq-original-partition"
+	DLQOffsetHeader    = "dlq-original-offset"
+	DLQErrorHeader     = "dlq-error"
+)
+
+// KafkaReader fetches without committing, so the consumer commits a message
+// only once it has been handled. kafka-go's ReadMessage would commit it
+// before processing starts whenever the reader is in a group.
 type KafkaReader interface {
-	ReadMessage(ctx context.Context) (kafka.Message, error)
+	FetchMessage(ctx context.Context) (kafka.Message, error)
 	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	// Config tells the topic and consumer group the reader was created for.
+	Config() kafka.ReaderConfig
 	Close() error
 }
 
+// KafkaConsumer reads one topic and hands every message to the topic's
+// handler, retrying database outages and dead-lettering what still fails.
 type KafkaConsumer struct {
-	reader  KafkaReader
-	useCase usecase.TransactionUseCase
-	logger  logging.Logger
+	reader             KafkaReader
+	handler            MessageHandler
+	auditUseCase       usecase.AuditUseCase
+	logger             logging.Logger
+	decoders           map[string]MessageDecoder
+	defaultContentType string
+	retry              RetryPolicy
+	dlq                KafkaWriter
+
+	// inFlight is held while a message is processed so Pause can wait for it.
+	inFlight      sync.Mutex
+	mu            sync.Mutex
+	running       bool
+	retrying      bool
+	paused        bool
+	resumed       chan struct{}
+	lastMessageAt time.Time
 }
 
+// RetryPolicy bounds how often a message is retried while the database is
+// unreachable. Other errors are never retried.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+}
+
+var defaultRetryPolicy = RetryPolicy{MaxAttempts: 3, Backoff: 3 * time.Second}
+
 type TransactionMessage struct {
-	ID              string `json:"id"`
-	UserID          string `json:"user_id"`
-	TransactionType string `json:"transaction_type"`
-	Amount          uint   `json:"amount"`
+	ID                    string `json:"id"`
+	UserID                string `json:"user_id"`
+	TransactionType       string `json:"transaction_type"`
+	Amount                uint   `json:"amount"`
+	Currency              string `json:"currency"`
+	RoundID               string `json:"round_id"`
+	GameID                string `json:"game_id"`
+	ProviderID            string `json:"provider_id"`
+	OriginalTransactionID string `json:"original_transaction_id"`
+	Reason                string `json:"reason"`
 }
 
-func NewKafkaConsumer(brokers []string, topic string, useCase usecase.TransactionUseCase, logger logging.Logger) *KafkaConsumer {
+func NewKafkaConsumer(brokers []string, topic string, useCase usecase.TransactionUseCase, auditUseCase usecase.AuditUseCase, logger logging.Logger) *KafkaConsumer {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        brokers,
 		Topic:          topic,
@@ -42,66 +95,225 @@ func NewKafkaConsumer(brokers []string, topic string, useCase usecase.Transactio
 	})
 
 	return &KafkaConsumer{
-		reader:  reader,
-		useCase: useCase,
-		logger:  logger,
+		reader:       reader,
+		handler:      NewTransactionHandler(useCase),
+		auditUseCase: auditUseCase,
+		logger:       logger,
+		retry:        defaultRetryPolicy,
+	}
+}
+
+// RegisterDecoder adds or replaces the decoder for messages whose
+// content-type header is contentType.
+func (kc *KafkaConsumer) RegisterDecoder(contentType string, decoder MessageDecoder) {
+	if kc.decoders == nil {
+		kc.decoders = make(map[string]MessageDecoder)
 	}
+	kc.decoders[contentType] = decoder
 }
 
 func (kc *KafkaConsumer) Start(ctx context.Context) {
+	kc.setRunning(true)
+	defer kc.setRunning(false)
+
 	for {
 		select {
 		case <-ctx.Done():
 			kc.logger.Info(ctx, "Kafka consumer stopping due to context cancellation")
 			return
 		default:
-			message, err := kc.reader.ReadMessage(ctx)
+			message, err := kc.reader.FetchMessage(ctx)
 			if err != nil {
 				kc.logger.Error(ctx, fmt.Errorf("failed to read message: %w", err))
 				continue
 			}
 
-			var transactionMsg TransactionMessage
-			if err := json.Unmarshal(message.Value, &transactionMsg); err != nil {
-				kc.logger.Error(ctx, fmt.Errorf("failed to unmarshal message: %w", err))
-				_ = kc.reader.CommitMessages(ctx, message)
-				continue
-			}
+			kc.received(message)
 
-			createDto := &dto.CreateTransactionDTO{
-				ID:              transactionMsg.ID,
-				UserID:          transactionMsg.UserID,
-				TransactionType: transactionMsg.TransactionType,
-				Amount:          transactionMsg.Amount,
+			if !kc.deliver(ctx, message) {
+				kc.logger.Info(ctx, "Kafka consumer stopping due to context cancellation")
+				return
 			}
+		}
+	}
+}
+
+// deliver processes message and commits it. A message that still fails
+// because the database is unavailable is neither committed nor
+// dead-lettered but tried again after the backoff, so it is not lost and
+// later messages do not overtake it. deliver returns false, leaving the
+// message uncommitted for the next consumer, once ctx is cancelled.
+func (kc *KafkaConsumer) deliver(ctx context.Context, message kafka.Message) bool {
+	messageCtx := messageContext(ctx, message)
+	for {
+		if !kc.acquire(ctx) {
+			return false
+		}
 
-			maxRetries := 3
-			var processErr error
+		err := kc.processMessage(messageCtx, message)
+		if !errors.Is(err, repository.ErrUnavailable) {
+			// A message that was handled is committed even when the
+			// consumer is shutting down.
+			kc.commit(context.WithoutCancel(messageCtx), message)
+			kc.inFlight.Unlock()
+			return true
+		}
+		kc.inFlight.Unlock()
 
-			for attempt := 1; attempt <= maxRetries; attempt++ {
+		kc.setRetrying(true)
+		select {
+		case <-ctx.Done():
+			kc.setRetrying(false)
+			return false
+		case <-time.After(kc.retry.Backoff):
+		}
+		kc.setRetrying(false)
+	}
+}
 
-				processErr = kc.useCase.ProcessTransaction(createDto)
-				
-				if utils.IsDatabaseConnectionError(processErr) {
-					if attempt < maxRetries {
-						time.Sleep(3 * time.Second)
-						continue
-					}
-				}
-				break
-			}
+func (kc *KafkaConsumer) commit(ctx context.Context, message kafka.Message) {
+	if err := kc.reader.CommitMessages(ctx, message); err != nil {
+		kc.logger.Error(ctx, fmt.Errorf("failed to commit message: %w", err))
+	}
+}
 
-			if processErr != nil {
-				kc.logger.Error(ctx, processErr)
-			} else {
-				kc.logger.Info(ctx, "trans " + createDto.ID + " saved")
-			}
+// messageContext carries the producer's correlation ID, or a fresh one, as
+// the request ID and tags log lines with where the message came from.
+func messageContext(ctx context.Context, message kafka.Message) context.Context {
+	requestID := headerValue(message, CorrelationIDHeader)
+	if requestID == "" {
+		requestID = headerValue(message, RequestIDHeader)
+	}
+	if requestID == "" {
+		requestID = utils.GenerateUUID()
+	}
 
-			_ = kc.reader.CommitMessages(ctx, message)
+	ctx = context.WithValue(ctx, utils.CtxKeyRequestID, requestID)
+	return utils.WithLogFields(ctx,
+		utils.LogField{Key: "topic", Value: message.Topic},
+		utils.LogField{Key: "partition", Value: strconv.Itoa(message.Partition)},
+		utils.LogField{Key: "offset", Value: strconv.FormatInt(message.Offset, 10)},
+	)
+}
+
+func headerValue(message kafka.Message, key string) string {
+	value := ""
+	for _, header := range message.Headers {
+		if header.Key == key {
+			value = string(header.Value)
 		}
 	}
+	return value
+}
+
+// processMessage runs the message through the handler, dead-letters and
+// audits it, and returns the processing error.
+func (kc *KafkaConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	action, processErr := kc.handle(ctx, &Message{Message: message, decode: kc.decode})
+
+	if processErr != nil {
+		kc.logger.Error(ctx, processErr)
+		if kc.dlq != nil && !isDuplicate(processErr) && !errors.Is(processErr, repository.ErrUnavailable) {
+			kc.deadLetter(ctx, message, processErr)
+		}
+	} else {
+		kc.logger.Info(ctx, action+" succeeded")
+	}
+
+	kc.audit(ctx, message, action, processErr)
+	return processErr
+}
+
+func (kc *KafkaConsumer) handle(ctx context.Context, message *Message) (string, error) {
+	attempts := max(kc.retry.MaxAttempts, 1)
+
+	for attempt := 1; ; attempt++ {
+		action, err := kc.handler.Handle(ctx, message)
+		if !errors.Is(err, repository.ErrUnavailable) || attempt >= attempts {
+			return action, err
+		}
+
+		kc.setRetrying(true)
+		select {
+		case <-ctx.Done():
+			kc.setRetrying(false)
+			return action, err
+		case <-time.After(kc.retry.Backoff):
+		}
+		kc.setRetrying(false)
+	}
+}
+
+// isDuplicate tells replays of messages that were already applied, which
+// are expected with at-least-once delivery and not worth dead-lettering.
+func isDuplicate(err error) bool {
+	return utils.IsTransactionAlreadyExists(err) || utils.IsTransactionAlreadyCancelled(err)
+}
+
+// deadLetter copies the message to the dead letter topic with headers
+// saying where it came from and why it failed.
+func (kc *KafkaConsumer) deadLetter(ctx context.Context, message kafka.Message, processErr error) {
+	headers := append([]kafka.Header{}, message.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: DLQTopicHeader, Value: []byte(message.Topic)},
+		kafka.Header{Key: DLQPartitionHeader, Value: []byte(strconv.Itoa(message.Partition))},
+		kafka.Header{Key: DLQOffsetHeader, Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		kafka.Header{Key: DLQErrorHeader, Value: []byte(processErr.Error())},
+	)
+
+	err := kc.dlq.WriteMessages(ctx, kafka.Message{Key: message.Key, Value: message.Value, Headers: headers})
+	if err != nil {
+		kc.logger.Error(ctx, fmt.Errorf("failed to dead-letter message: %w", err))
+	}
+}
+
+// decode picks the decoder from the content-type header and brings the
+// envelope up to CurrentSchemaVersion.
+func (kc *KafkaConsumer) decode(message kafka.Message) (*TransactionEnvelope, error) {
+	contentType := headerValue(message, ContentTypeHeader)
+	if contentType == "" {
+		contentType = kc.defaultContentType
+	}
+	if contentType == "" {
+		contentType = ContentTypeJSON
+	}
+
+	decoder, ok := kc.decoders[contentType]
+	if !ok {
+		decoder, ok = defaultDecoders[contentType]
+	}
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	envelope, err := decoder.Decode(message.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := upcast(envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+func (kc *KafkaConsumer) audit(ctx context.Context, message kafka.Message, action string, processErr error) {
+	err := kc.auditUseCase.Record(&dto.CreateAuditRecordDTO{
+		Actor:   auditActor,
+		Action:  action,
+		Source:  fmt.Sprintf("kafka:%s/%d/%d", message.Topic, message.Partition, message.Offset),
+		Payload: message.Value,
+		Err:     processErr,
+	})
+	if err != nil {
+		kc.logger.Error(ctx, err)
+	}
 }
 
 func (kc *KafkaConsumer) Close() error {
-	return kc.reader.Close()
+	err := kc.reader.Close()
+	if kc.dlq != nil {
+		err = errors.Join(err, kc.dlq.Close())
+	}
+	return err
 }
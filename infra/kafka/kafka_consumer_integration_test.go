@@ -0,0 +1,158 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	domainusecases "casino/domain/usecase"
+	"casino/infra/repository"
+
+	"github.com/segmentio/kafka-go"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const integrationTopic = "casino-transactions-stream"
+
+func setupConsumerTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("Skipping test: failed to connect to database: %v", err)
+	}
+
+	// Every connection to :memory: opens its own database, and the consumer
+	// queries from another goroutine.
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := db.AutoMigrate(&repo_model.TransactionModel{}, &repo_model.DailyUserAggregateModel{}, &repo_model.OutboxEventModel{}, &repo_model.ExchangeRateModel{}); err != nil {
+		t.Skipf("Skipping test: failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func newIntegrationConsumer(db *gorm.DB, reader KafkaReader) *KafkaConsumer {
+	exchangeRateUseCase := domainusecases.NewExchangeRateUseCaseImpl(repository.NewPostgresExchangeRateRepository(db))
+	useCase := domainusecases.NewTransactionUseCaseImpl(repository.NewPostgresTransactionRepository(db), exchangeRateUseCase, nil)
+
+	return &KafkaConsumer{
+		reader:       reader,
+		handler:      NewTransactionHandler(useCase),
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+		retry:        defaultRetryPolicy,
+	}
+}
+
+// consumeAll runs consumer until the group has committed every message on
+// the topic.
+func consumeAll(t *testing.T, broker *MemoryBroker, consumer *KafkaConsumer) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		consumer.Start(ctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		partitions, err := broker.Partitions(context.Background(), "casino", []string{integrationTopic})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		caughtUp := true
+		for _, partition := range partitions[integrationTopic] {
+			if partition.HighWaterMark > 0 && (partition.Lag == nil || *partition.Lag > 0) {
+				caughtUp = false
+			}
+		}
+		if caughtUp {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Timed out waiting for the consumer to commit every message")
+}
+
+func writeTransactions(t *testing.T, broker *MemoryBroker, values ...string) {
+	t.Helper()
+
+	writer := broker.Writer(integrationTopic)
+	for _, value := range values {
+		if err := writer.WriteMessages(context.Background(), kafka.Message{Key: []byte("u1"), Value: []byte(value)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func storedTransactions(t *testing.T, db *gorm.DB) map[string]repo_model.TransactionModel {
+	t.Helper()
+
+	var models []repo_model.TransactionModel
+	if err := db.Find(&models).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	stored := make(map[string]repo_model.TransactionModel)
+	for _, model := range models {
+		stored[model.ID] = model
+	}
+	return stored
+}
+
+func TestKafkaConsumer_Integration_StoresTransactions(t *testing.T) {
+	db := setupConsumerTestDB(t)
+	broker := NewMemoryBroker(3)
+	writeTransactions(t, broker,
+		`{"id": "d1", "user_id": "u1", "transaction_type": "deposit", "amount": 500}`,
+		`{"id": "b1", "user_id": "u1", "transaction_type": "bet", "amount": 100, "round_id": "r1"}`,
+		`{"id": "b1", "user_id": "u1", "transaction_type": "bet", "amount": 100, "round_id": "r1"}`,
+		`{"id": "bad", "user_id": "u1", "transaction_type": "bet"}`,
+	)
+
+	consumeAll(t, broker, newIntegrationConsumer(db, broker.Reader(integrationTopic, "casino")))
+
+	stored := storedTransactions(t, db)
+	if len(stored) != 2 {
+		t.Fatalf("Expected the deposit and the bet to be stored once each, got %d transactions", len(stored))
+	}
+	if stored["d1"].Amount != 500 || stored["b1"].Amount != 100 {
+		t.Errorf("Unexpected stored transactions %+v", stored)
+	}
+}
+
+func TestKafkaConsumer_Integration_ResumesFromCommittedOffset(t *testing.T) {
+	db := setupConsumerTestDB(t)
+	broker := NewMemoryBroker(1)
+	writeTransactions(t, broker, `{"id": "d1", "user_id": "u1", "transaction_type": "deposit", "amount": 500}`)
+
+	first := newIntegrationConsumer(db, broker.Reader(integrationTopic, "casino"))
+	consumeAll(t, broker, first)
+	first.Close()
+
+	writeTransactions(t, broker, `{"id": "d2", "user_id": "u1", "transaction_type": "deposit", "amount": 200}`)
+
+	second := newIntegrationConsumer(db, broker.Reader(integrationTopic, "casino"))
+	consumeAll(t, broker, second)
+	second.Close()
+
+	if len(storedTransactions(t, db)) != 2 {
+		t.Error("Expected both deposits to be stored")
+	}
+	if records := second.auditUseCase.(*MockAuditUseCase).records; len(records) != 1 {
+		t.Errorf("Expected the restarted consumer to only see the new message, got %d", len(records))
+	}
+}
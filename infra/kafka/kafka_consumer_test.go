@@ -8,6 +8,7 @@ import (
 	"time"
 
 	boundarydto "casino/boundary/dto"
+	"casino/boundary/repository"
 	"casino/utils"
 
 	"github.com/segmentio/kafka-go"
@@ -16,35 +17,97 @@ import (
 type MockTransactionUseCase struct {
 	processError error
 	processCount int
+	lastProcess  *boundarydto.CreateTransactionDTO
+	cancelError  error
+	cancelCount  int
+	lastCancel   *boundarydto.CancelTransactionDTO
+	stored       map[string]bool
+	reversals    []*boundarydto.TransactionDTO
 }
 
 func (m *MockTransactionUseCase) ProcessTransaction(dto *boundarydto.CreateTransactionDTO) error {
 	m.processCount++
+	m.lastProcess = dto
 	return m.processError
 }
 
+func (m *MockTransactionUseCase) GetTransaction(id string) (*boundarydto.TransactionDTO, error) {
+	if m.stored != nil && !m.stored[id] {
+		return nil, &utils.TransactionNotFoundError{TransactionID: id}
+	}
+	return &boundarydto.TransactionDTO{ID: id}, nil
+}
+
 func (m *MockTransactionUseCase) GetUserTransactions(userID string, filter *boundarydto.TransactionFilterDTO) ([]*boundarydto.TransactionDTO, error) {
 	return nil, nil
 }
 
+func (m *MockTransactionUseCase) GetReversals(originalID string) ([]*boundarydto.TransactionDTO, error) {
+	var reversals []*boundarydto.TransactionDTO
+	for _, reversal := range m.reversals {
+		if reversal.OriginalTransactionID == originalID {
+			reversals = append(reversals, reversal)
+		}
+	}
+	return reversals, nil
+}
+
 func (m *MockTransactionUseCase) GetAllTransactions(filter *boundarydto.TransactionFilterDTO) ([]*boundarydto.TransactionDTO, error) {
 	return nil, nil
 }
 
+func (m *MockTransactionUseCase) GetRound(roundID string) (*boundarydto.RoundDTO, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionUseCase) CancelTransaction(dto *boundarydto.CancelTransactionDTO) (*boundarydto.TransactionDTO, error) {
+	m.cancelCount++
+	m.lastCancel = dto
+	if m.cancelError != nil {
+		return nil, m.cancelError
+	}
+	return &boundarydto.TransactionDTO{ID: dto.ID, OriginalTransactionID: dto.OriginalTransactionID}, nil
+}
+
+func (m *MockTransactionUseCase) GetBalances(userID string) ([]*boundarydto.BalanceDTO, error) {
+	return nil, nil
+}
+
+func (m *MockTransactionUseCase) ExportTransactions(ctx context.Context, userID *string, filter *boundarydto.TransactionFilterDTO, fn func(*boundarydto.TransactionDTO) error) error {
+	return nil
+}
+
+type MockAuditUseCase struct {
+	records     []*boundarydto.CreateAuditRecordDTO
+	recordError error
+}
+
+func (m *MockAuditUseCase) Record(dto *boundarydto.CreateAuditRecordDTO) error {
+	m.records = append(m.records, dto)
+	return m.recordError
+}
+
+func (m *MockAuditUseCase) VerifyChain(from, to time.Time) (*boundarydto.AuditVerificationDTO, error) {
+	return &boundarydto.AuditVerificationDTO{Valid: true}, nil
+}
+
 type MockLogger struct {
 	errorCount int
 	infoCount  int
 	errors     []error
 	messages   []string
+	contexts   []context.Context
 }
 
 func (m *MockLogger) Error(ctx context.Context, errs ...error) {
 	m.errorCount++
+	m.contexts = append(m.contexts, ctx)
 	m.errors = append(m.errors, errs...)
 }
 
 func (m *MockLogger) Info(ctx context.Context, messages ...string) {
 	m.infoCount++
+	m.contexts = append(m.contexts, ctx)
 	m.messages = append(m.messages, messages...)
 }
 
@@ -56,6 +119,7 @@ func TestNewKafkaConsumer(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -63,7 +127,7 @@ func TestNewKafkaConsumer(t *testing.T) {
 		t.Error("Expected consumer to be created")
 	}
 
-	if consumer.useCase != mockUseCase {
+	if consumer.handler.(*TransactionHandler).useCase != mockUseCase {
 		t.Error("Expected use case to be set")
 	}
 
@@ -84,6 +148,7 @@ func TestNewKafkaConsumer_WithMultipleBrokers(t *testing.T) {
 		[]string{"localhost:9092", "localhost:9093"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -100,6 +165,7 @@ func TestKafkaConsumer_Close(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -240,6 +306,7 @@ func TestKafkaConsumer_ProcessTransaction_Success(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -255,7 +322,7 @@ func TestKafkaConsumer_ProcessTransaction_Success(t *testing.T) {
 		Amount:          message.Amount,
 	}
 
-	err := consumer.useCase.ProcessTransaction(createDto)
+	err := consumer.handler.(*TransactionHandler).useCase.ProcessTransaction(createDto)
 	if err != nil {
 		t.Errorf("Expected no error processing transaction, got %v", err)
 	}
@@ -275,6 +342,7 @@ func TestKafkaConsumer_ProcessTransaction_Error(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -290,7 +358,7 @@ func TestKafkaConsumer_ProcessTransaction_Error(t *testing.T) {
 		Amount:          message.Amount,
 	}
 
-	err := consumer.useCase.ProcessTransaction(createDto)
+	err := consumer.handler.(*TransactionHandler).useCase.ProcessTransaction(createDto)
 	if err == nil {
 		t.Error("Expected error processing transaction")
 	}
@@ -308,6 +376,7 @@ func TestKafkaConsumer_MessageProcessing(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -338,7 +407,7 @@ func TestKafkaConsumer_MessageProcessing(t *testing.T) {
 				Amount:          message.Amount,
 			}
 
-			err := consumer.useCase.ProcessTransaction(createDto)
+			err := consumer.handler.(*TransactionHandler).useCase.ProcessTransaction(createDto)
 			if err != nil {
 				t.Errorf("Expected no error processing %s, got %v", tc.name, err)
 			}
@@ -356,6 +425,7 @@ func TestKafkaConsumer_ErrorHandling(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -371,7 +441,7 @@ func TestKafkaConsumer_ErrorHandling(t *testing.T) {
 		Amount:          message.Amount,
 	}
 
-	err := consumer.useCase.ProcessTransaction(createDto)
+	err := consumer.handler.(*TransactionHandler).useCase.ProcessTransaction(createDto)
 	if err == nil {
 		t.Error("Expected error processing invalid transaction")
 	}
@@ -387,6 +457,7 @@ func TestKafkaConsumer_LoggerIntegration(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -427,11 +498,11 @@ func TestKafkaConsumer_Constructor_EdgeCases(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			consumer := NewKafkaConsumer(tc.brokers, tc.topic, mockUseCase, mockLogger)
+			consumer := NewKafkaConsumer(tc.brokers, tc.topic, mockUseCase, &MockAuditUseCase{}, mockLogger)
 			if consumer == nil {
 				t.Error("Expected consumer to be created")
 			}
-			if consumer.useCase != mockUseCase {
+			if consumer.handler.(*TransactionHandler).useCase != mockUseCase {
 				t.Error("Expected use case to be set")
 			}
 			if consumer.logger != mockLogger {
@@ -449,6 +520,7 @@ func TestKafkaConsumer_MessageProcessing_EdgeCases(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -482,7 +554,7 @@ func TestKafkaConsumer_MessageProcessing_EdgeCases(t *testing.T) {
 				Amount:          message.Amount,
 			}
 
-			err := consumer.useCase.ProcessTransaction(createDto)
+			err := consumer.handler.(*TransactionHandler).useCase.ProcessTransaction(createDto)
 			if err != nil {
 				t.Errorf("Expected no error processing %s, got %v", tc.name, err)
 			}
@@ -498,6 +570,7 @@ func TestKafkaConsumer_StructFields(t *testing.T) {
 		[]string{"localhost:9092"},
 		"test-topic",
 		mockUseCase,
+		&MockAuditUseCase{},
 		mockLogger,
 	)
 
@@ -505,7 +578,7 @@ func TestKafkaConsumer_StructFields(t *testing.T) {
 		t.Error("Expected reader to be initialized")
 	}
 
-	if consumer.useCase == nil {
+	if consumer.handler.(*TransactionHandler).useCase == nil {
 		t.Error("Expected use case to be initialized")
 	}
 
@@ -541,7 +614,7 @@ type MockKafkaReader struct {
 	closed    bool
 }
 
-func (m *MockKafkaReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+func (m *MockKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
 	if m.errOnRead != nil {
 		return kafka.Message{}, m.errOnRead
 	}
@@ -557,6 +630,10 @@ func (m *MockKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Mess
 	return nil
 }
 
+func (m *MockKafkaReader) Config() kafka.ReaderConfig {
+	return kafka.ReaderConfig{Topic: "casino-transactions-stream"}
+}
+
 func (m *MockKafkaReader) Close() error {
 	m.closed = true
 	return nil
@@ -564,9 +641,10 @@ func (m *MockKafkaReader) Close() error {
 
 func newTestKafkaConsumerWithMockReader(reader *MockKafkaReader, useCase *MockTransactionUseCase, logger *MockLogger) *KafkaConsumer {
 	return &KafkaConsumer{
-		reader:  reader,
-		useCase: useCase,
-		logger:  logger,
+		reader:       reader,
+		handler:      NewTransactionHandler(useCase),
+		auditUseCase: &MockAuditUseCase{},
+		logger:       logger,
 	}
 }
 
@@ -836,3 +914,555 @@ func TestKafkaConsumer_Start_MissingFields(t *testing.T) {
 		t.Errorf("Expected 1 message processed, got %d", mockUseCase.processCount)
 	}
 }
+
+func TestKafkaConsumer_ProcessMessage_AuditOutcomes(t *testing.T) {
+	validMsg := TransactionMessage{
+		ID:              utils.GenerateUUID(),
+		UserID:          utils.GenerateUUID(),
+		TransactionType: "bet",
+		Amount:          100,
+	}
+	validBytes, _ := json.Marshal(validMsg)
+
+	testCases := []struct {
+		name          string
+		value         []byte
+		processError  error
+		expectedCheck func(error) bool
+	}{
+		{"Accepted", validBytes, nil, func(err error) bool { return err == nil }},
+		{"Duplicate", validBytes, &utils.TransactionAlreadyExistsError{TransactionID: validMsg.ID}, utils.IsTransactionAlreadyExists},
+		{"Validation Failure", validBytes, &utils.TransactionValidationError{TransactionID: validMsg.ID, Reason: "bad"}, utils.IsTransactionValidation},
+		{"Malformed JSON", []byte(`{"amount": "bad"}`), nil, utils.IsTransactionValidation},
+		{"Other Error", validBytes, fmt.Errorf("boom"), func(err error) bool { return err != nil && err.Error() == "boom" }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAudit := &MockAuditUseCase{}
+			consumer := &KafkaConsumer{
+				handler:      NewTransactionHandler(&MockTransactionUseCase{processError: tc.processError}),
+				auditUseCase: mockAudit,
+				logger:       &MockLogger{},
+			}
+
+			message := kafka.Message{Topic: "test-topic", Partition: 2, Offset: 42, Value: tc.value}
+			consumer.processMessage(context.Background(), message)
+
+			if len(mockAudit.records) != 1 {
+				t.Fatalf("Expected 1 audit record, got %d", len(mockAudit.records))
+			}
+
+			record := mockAudit.records[0]
+			if !tc.expectedCheck(record.Err) {
+				t.Errorf("Unexpected audited error %v", record.Err)
+			}
+			if record.Source != "kafka:test-topic/2/42" {
+				t.Errorf("Expected kafka source, got %s", record.Source)
+			}
+			if string(record.Payload) != string(tc.value) {
+				t.Errorf("Expected raw message payload to be audited")
+			}
+		})
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_AuditError(t *testing.T) {
+	mockLogger := &MockLogger{}
+	consumer := &KafkaConsumer{
+		handler:      NewTransactionHandler(&MockTransactionUseCase{}),
+		auditUseCase: &MockAuditUseCase{recordError: fmt.Errorf("audit unavailable")},
+		logger:       mockLogger,
+	}
+
+	consumer.processMessage(context.Background(), kafka.Message{Value: []byte(`{"id": "1"}`)})
+
+	if mockLogger.errorCount != 1 {
+		t.Errorf("Expected audit error to be logged, got %d errors", mockLogger.errorCount)
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_Rollback(t *testing.T) {
+	msg := TransactionMessage{
+		ID:                    utils.GenerateUUID(),
+		UserID:                utils.GenerateUUID(),
+		TransactionType:       "rollback",
+		Amount:                100,
+		OriginalTransactionID: utils.GenerateUUID(),
+		Reason:                "game server crashed",
+	}
+	value, _ := json.Marshal(msg)
+
+	mockUseCase := &MockTransactionUseCase{}
+	mockAudit := &MockAuditUseCase{}
+	consumer := &KafkaConsumer{
+		handler:      NewTransactionHandler(mockUseCase),
+		auditUseCase: mockAudit,
+		logger:       &MockLogger{},
+	}
+
+	consumer.processMessage(context.Background(), kafka.Message{Value: value})
+
+	if mockUseCase.processCount != 0 {
+		t.Errorf("Expected rollback not to go through ProcessTransaction, got %d calls", mockUseCase.processCount)
+	}
+	if mockUseCase.cancelCount != 1 {
+		t.Fatalf("Expected 1 cancel call, got %d", mockUseCase.cancelCount)
+	}
+	if mockUseCase.lastCancel.ID != msg.ID || mockUseCase.lastCancel.OriginalTransactionID != msg.OriginalTransactionID {
+		t.Errorf("Unexpected cancel request %+v", mockUseCase.lastCancel)
+	}
+	if mockUseCase.lastCancel.Reason != msg.Reason {
+		t.Errorf("Expected reason %s, got %s", msg.Reason, mockUseCase.lastCancel.Reason)
+	}
+	if len(mockAudit.records) != 1 || mockAudit.records[0].Action != auditActionCancelTransaction {
+		t.Errorf("Expected cancel action to be audited, got %+v", mockAudit.records)
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_RollbackAlreadyCancelled(t *testing.T) {
+	value, _ := json.Marshal(TransactionMessage{
+		ID:                    utils.GenerateUUID(),
+		TransactionType:       "rollback",
+		OriginalTransactionID: utils.GenerateUUID(),
+	})
+
+	mockAudit := &MockAuditUseCase{}
+	consumer := &KafkaConsumer{
+		handler:      NewTransactionHandler(&MockTransactionUseCase{cancelError: &utils.TransactionAlreadyCancelledError{TransactionID: "tx"}}),
+		auditUseCase: mockAudit,
+		logger:       &MockLogger{},
+	}
+
+	consumer.processMessage(context.Background(), kafka.Message{Value: value})
+
+	if len(mockAudit.records) != 1 || !utils.IsTransactionAlreadyCancelled(mockAudit.records[0].Err) {
+		t.Errorf("Expected already cancelled error to be audited, got %+v", mockAudit.records)
+	}
+}
+
+type capturingTransactionUseCase struct {
+	MockTransactionUseCase
+	last *boundarydto.CreateTransactionDTO
+}
+
+func (m *capturingTransactionUseCase) ProcessTransaction(dto *boundarydto.CreateTransactionDTO) error {
+	m.last = dto
+	return nil
+}
+
+func TestKafkaConsumer_ProcessMessage_Currency(t *testing.T) {
+	mockUseCase := &capturingTransactionUseCase{}
+	consumer := &KafkaConsumer{
+		handler:      NewTransactionHandler(mockUseCase),
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+	}
+
+	value := []byte(`{"id": "tx-1", "user_id": "user-1", "transaction_type": "deposit", "amount": 9000000000000000000, "currency": "BTC"}`)
+	consumer.processMessage(context.Background(), kafka.Message{Value: value})
+
+	if mockUseCase.last == nil {
+		t.Fatal("Expected transaction to be processed")
+	}
+	if mockUseCase.last.Currency != "BTC" {
+		t.Errorf("Expected currency BTC, got %s", mockUseCase.last.Currency)
+	}
+	if mockUseCase.last.Amount != 9000000000000000000 {
+		t.Errorf("Expected amount beyond int32 to survive decoding, got %d", mockUseCase.last.Amount)
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_Envelope(t *testing.T) {
+	envelope := TransactionEnvelope{
+		SchemaVersion: CurrentSchemaVersion,
+		EventType:     EventTypeTransactionCancelled,
+		Transaction: TransactionMessage{
+			ID:                    utils.GenerateUUID(),
+			OriginalTransactionID: utils.GenerateUUID(),
+			Reason:                "duplicate",
+		},
+	}
+	value, _ := json.Marshal(envelope)
+
+	mockUseCase := &MockTransactionUseCase{}
+	consumer := &KafkaConsumer{
+		handler:      NewTransactionHandler(mockUseCase),
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+	}
+
+	consumer.processMessage(context.Background(), kafka.Message{
+		Value:   value,
+		Headers: []kafka.Header{{Key: ContentTypeHeader, Value: []byte(ContentTypeJSON)}},
+	})
+
+	if mockUseCase.cancelCount != 1 || mockUseCase.lastCancel.Reason != "duplicate" {
+		t.Errorf("Expected the cancelled event to cancel the original, got %+v", mockUseCase.lastCancel)
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_RejectedEnvelopes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		contentType string
+	}{
+		{"Unknown Event Type", `{"schema_version": 2, "event_type": "transaction.exploded", "transaction": {"id": "1"}}`, ""},
+		{"Future Schema Version", `{"schema_version": 4, "event_type": "transaction.created", "transaction": {"id": "1"}}`, ""},
+		{"Sub-Gwei ETH Amount", `{"schema_version": 2, "event_type": "transaction.created", "transaction": {"id": "1", "currency": "ETH", "amount": 1500000001}}`, ""},
+		{"Unknown Content Type", `{"id": "1"}`, "application/xml"},
+		{"Avro Without Registry", "\x00\x00\x00\x00\x01", ContentTypeAvro},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockUseCase := &MockTransactionUseCase{}
+			mockAudit := &MockAuditUseCase{}
+			consumer := &KafkaConsumer{
+				handler:      NewTransactionHandler(mockUseCase),
+				auditUseCase: mockAudit,
+				logger:       &MockLogger{},
+			}
+
+			message := kafka.Message{Value: []byte(tc.value)}
+			if tc.contentType != "" {
+				message.Headers = []kafka.Header{{Key: ContentTypeHeader, Value: []byte(tc.contentType)}}
+			}
+			consumer.processMessage(context.Background(), message)
+
+			if mockUseCase.processCount != 0 || mockUseCase.cancelCount != 0 {
+				t.Error("Expected the message not to be processed")
+			}
+			if len(mockAudit.records) != 1 || !utils.IsTransactionValidation(mockAudit.records[0].Err) {
+				t.Errorf("Expected the rejection to be audited as invalid, got %+v", mockAudit.records)
+			}
+		})
+	}
+}
+
+func TestMessageContext_RequestID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  []kafka.Header
+		expected string
+	}{
+		{
+			name: "correlation id wins",
+			headers: []kafka.Header{
+				{Key: RequestIDHeader, Value: []byte("req-1")},
+				{Key: CorrelationIDHeader, Value: []byte("corr-1")},
+			},
+			expected: "corr-1",
+		},
+		{
+			name:     "request id fallback",
+			headers:  []kafka.Header{{Key: RequestIDHeader, Value: []byte("req-1")}},
+			expected: "req-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := messageContext(context.Background(), kafka.Message{Headers: tc.headers})
+
+			if requestID := ctx.Value(utils.CtxKeyRequestID); requestID != tc.expected {
+				t.Errorf("Expected request ID %s, got %v", tc.expected, requestID)
+			}
+		})
+	}
+}
+
+func TestMessageContext_GeneratesRequestIDAndLogFields(t *testing.T) {
+	ctx := messageContext(context.Background(), kafka.Message{Topic: "casino-transactions", Partition: 2, Offset: 17})
+
+	requestID, _ := ctx.Value(utils.CtxKeyRequestID).(string)
+	if len(requestID) != 36 {
+		t.Errorf("Expected a generated UUID request ID, got %q", requestID)
+	}
+
+	if fields := utils.FormatLogFields(utils.LogFieldsFromCtx(ctx)); fields != "topic=casino-transactions partition=2 offset=17" {
+		t.Errorf("Expected topic, partition and offset log fields, got %q", fields)
+	}
+}
+
+func TestKafkaConsumer_Start_PropagatesCorrelationID(t *testing.T) {
+	value, _ := json.Marshal(TransactionMessage{ID: utils.GenerateUUID(), UserID: "user1", TransactionType: "bet", Amount: 100})
+	mockLogger := &MockLogger{}
+	consumer := &KafkaConsumer{
+		reader: &headerKafkaReader{message: kafka.Message{
+			Topic:   "casino-transactions",
+			Value:   value,
+			Headers: []kafka.Header{{Key: CorrelationIDHeader, Value: []byte("corr-42")}},
+		}},
+		handler:      NewTransactionHandler(&MockTransactionUseCase{}),
+		auditUseCase: &MockAuditUseCase{},
+		logger:       mockLogger,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	consumer.reader.(*headerKafkaReader).cancel = cancel
+	consumer.Start(ctx)
+
+	if len(mockLogger.contexts) < 2 {
+		t.Fatalf("Expected the saved transaction and the shutdown to be logged, got %v", mockLogger.messages)
+	}
+	if requestID := mockLogger.contexts[0].Value(utils.CtxKeyRequestID); requestID != "corr-42" {
+		t.Errorf("Expected request ID corr-42 in log context, got %v", requestID)
+	}
+}
+
+// headerKafkaReader returns one message and cancels the consumer once it has
+// been committed, so Start can be run synchronously.
+type headerKafkaReader struct {
+	message   kafka.Message
+	read      bool
+	committed bool
+	cancel    context.CancelFunc
+}
+
+func (r *headerKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if r.read {
+		return kafka.Message{}, ctx.Err()
+	}
+	r.read = true
+	return r.message, nil
+}
+
+func (r *headerKafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = true
+	r.cancel()
+	return nil
+}
+
+func (r *headerKafkaReader) Config() kafka.ReaderConfig {
+	return kafka.ReaderConfig{Topic: "casino-transactions-stream"}
+}
+
+func (r *headerKafkaReader) Close() error {
+	return nil
+}
+
+// commitCheckingHandler notes whether the message was committed before it
+// was handled.
+type commitCheckingHandler struct {
+	reader         *headerKafkaReader
+	committedEarly bool
+}
+
+func (h *commitCheckingHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	h.committedEarly = h.reader.committed
+	return auditActionProcessTransaction, nil
+}
+
+func TestKafkaConsumer_Start_CommitsAfterProcessing(t *testing.T) {
+	reader := &headerKafkaReader{message: kafka.Message{Topic: "casino-transactions", Value: []byte(`{}`)}}
+	handler := &commitCheckingHandler{reader: reader}
+	consumer := &KafkaConsumer{
+		reader:       reader,
+		handler:      handler,
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader.cancel = cancel
+	consumer.Start(ctx)
+
+	if handler.committedEarly {
+		t.Error("Expected the message to be committed only after it was handled")
+	}
+	if !reader.committed {
+		t.Error("Expected the handled message to be committed")
+	}
+}
+
+type flakyHandler struct {
+	errs  []error
+	calls int
+}
+
+func (h *flakyHandler) Handle(ctx context.Context, message *Message) (string, error) {
+	h.calls++
+	if len(h.errs) == 0 {
+		return auditActionProcessTransaction, nil
+	}
+	err := h.errs[0]
+	h.errs = h.errs[1:]
+	return auditActionProcessTransaction, err
+}
+
+func TestKafkaConsumer_ProcessMessage_RetryPolicy(t *testing.T) {
+	dbErr := fmt.Errorf("failed to save transaction: %w", repository.ErrUnavailable)
+
+	testCases := []struct {
+		name          string
+		errs          []error
+		maxAttempts   int
+		expectCalls   int
+		expectFailure bool
+	}{
+		{"Recovers", []error{dbErr, dbErr}, 3, 3, false},
+		{"Exhausted", []error{dbErr, dbErr, dbErr}, 2, 2, true},
+		{"Zero Attempts Tries Once", []error{dbErr}, 0, 1, true},
+		{"Validation Not Retried", []error{&utils.TransactionValidationError{Reason: "bad"}}, 3, 1, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &flakyHandler{errs: tc.errs}
+			mockAudit := &MockAuditUseCase{}
+			consumer := &KafkaConsumer{
+				handler:      handler,
+				auditUseCase: mockAudit,
+				logger:       &MockLogger{},
+				retry:        RetryPolicy{MaxAttempts: tc.maxAttempts, Backoff: time.Millisecond},
+			}
+
+			consumer.processMessage(context.Background(), kafka.Message{Value: []byte(`{}`)})
+
+			if handler.calls != tc.expectCalls {
+				t.Errorf("Expected %d attempts, got %d", tc.expectCalls, handler.calls)
+			}
+			if (mockAudit.records[0].Err != nil) != tc.expectFailure {
+				t.Errorf("Unexpected audited error %v", mockAudit.records[0].Err)
+			}
+		})
+	}
+}
+
+func TestKafkaConsumer_Start_HoldsMessageWhileDatabaseUnavailable(t *testing.T) {
+	dbErr := fmt.Errorf("failed to save transaction: %w", repository.ErrUnavailable)
+	reader := &headerKafkaReader{message: kafka.Message{Topic: "casino-transactions", Value: []byte(`{}`)}}
+	handler := &flakyHandler{errs: []error{dbErr, dbErr, dbErr}}
+	dlq := &MockKafkaWriter{}
+	consumer := &KafkaConsumer{
+		reader:       reader,
+		handler:      handler,
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+		retry:        RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+		dlq:          dlq,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reader.cancel = cancel
+	consumer.Start(ctx)
+
+	if handler.calls != 4 {
+		t.Errorf("Expected the message to be tried until the database was back, got %d attempts", handler.calls)
+	}
+	if !reader.committed {
+		t.Error("Expected the message to be committed once processed")
+	}
+	if len(dlq.written) != 0 {
+		t.Error("Expected an unavailable database not to dead-letter the message")
+	}
+}
+
+func TestKafkaConsumer_Start_StopsWithoutCommittingWhileDatabaseUnavailable(t *testing.T) {
+	dbErr := fmt.Errorf("failed to save transaction: %w", repository.ErrUnavailable)
+	reader := &headerKafkaReader{message: kafka.Message{Topic: "casino-transactions", Value: []byte(`{}`)}}
+	handler := &flakyHandler{errs: []error{dbErr, dbErr, dbErr, dbErr}}
+	consumer := &KafkaConsumer{
+		reader:       reader,
+		handler:      handler,
+		auditUseCase: &MockAuditUseCase{},
+		logger:       &MockLogger{},
+		retry:        RetryPolicy{MaxAttempts: 1, Backoff: time.Hour},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	reader.cancel = cancel
+	consumer.Start(ctx)
+
+	if reader.committed {
+		t.Error("Expected the failed message to stay uncommitted for the next consumer")
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_DeadLetter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		err       error
+		expectDLQ bool
+	}{
+		{"Success", nil, false},
+		{"Validation Failure", &utils.TransactionValidationError{Reason: "bad"}, true},
+		{"Other Error", fmt.Errorf("boom"), true},
+		{"Duplicate", &utils.TransactionAlreadyExistsError{TransactionID: "tx"}, false},
+		{"Already Cancelled", &utils.TransactionAlreadyCancelledError{TransactionID: "tx"}, false},
+		{"Database Unavailable", fmt.Errorf("failed to save transaction: %w", repository.ErrUnavailable), false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dlq := &MockKafkaWriter{}
+			consumer := &KafkaConsumer{
+				handler:      &flakyHandler{errs: []error{tc.err}},
+				auditUseCase: &MockAuditUseCase{},
+				logger:       &MockLogger{},
+				dlq:          dlq,
+			}
+
+			message := kafka.Message{
+				Topic:     "casino-rollbacks",
+				Partition: 1,
+				Offset:    7,
+				Key:       []byte("user-1"),
+				Value:     []byte(`{"id": "tx"}`),
+				Headers:   []kafka.Header{{Key: CorrelationIDHeader, Value: []byte("corr-1")}},
+			}
+			consumer.processMessage(context.Background(), message)
+
+			if (len(dlq.written) == 1) != tc.expectDLQ {
+				t.Fatalf("Expected dead-lettered=%v, got %d messages", tc.expectDLQ, len(dlq.written))
+			}
+			if !tc.expectDLQ {
+				return
+			}
+
+			dead := dlq.written[0]
+			if string(dead.Key) != "user-1" || string(dead.Value) != `{"id": "tx"}` {
+				t.Errorf("Expected the original key and value, got %s %s", dead.Key, dead.Value)
+			}
+			headers := map[string]string{}
+			for _, header := range dead.Headers {
+				headers[header.Key] = string(header.Value)
+			}
+			if headers[CorrelationIDHeader] != "corr-1" || headers[DLQTopicHeader] != "casino-rollbacks" ||
+				headers[DLQPartitionHeader] != "1" || headers[DLQOffsetHeader] != "7" || headers[DLQErrorHeader] != tc.err.Error() {
+				t.Errorf("Unexpected dead letter headers %v", headers)
+			}
+		})
+	}
+}
+
+func TestKafkaConsumer_ProcessMessage_DeadLetterWriteError(t *testing.T) {
+	mockLogger := &MockLogger{}
+	consumer := &KafkaConsumer{
+		handler:      &flakyHandler{errs: []error{fmt.Errorf("boom")}},
+		auditUseCase: &MockAuditUseCase{},
+		logger:       mockLogger,
+		dlq:          &MockKafkaWriter{writeErr: fmt.Errorf("broker down")},
+	}
+
+	consumer.processMessage(context.Background(), kafka.Message{Value: []byte(`{}`)})
+
+	if mockLogger.errorCount != 2 {
+		t.Errorf("Expected the failure and the dead letter error to be logged, got %v", mockLogger.errors)
+	}
+}
+
+func TestKafkaConsumer_Close_DeadLetterWriter(t *testing.T) {
+	dlq := &MockKafkaWriter{}
+	consumer := &KafkaConsumer{reader: &MockKafkaReader{}, dlq: dlq}
+
+	if err := consumer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !dlq.closed {
+		t.Error("Expected the dead letter writer to be closed")
+	}
+}
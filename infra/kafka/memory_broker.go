@@ -0,0 +1,366 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"casino/boundary/dto"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MemoryBroker is an in-process stand-in for a Kafka cluster, for tests and
+// for running locally without one. Topics have a fixed number of
+// partitions, keyed messages always land on the same partition, and each
+// consumer group tracks committed offsets per partition. Partitions are
+// spread over the group's open readers; whenever a reader joins or leaves,
+// every reader restarts from the committed offsets, so messages that were
+// fetched but not committed are delivered again.
+type MemoryBroker struct {
+	mu                sync.Mutex
+	defaultPartitions int
+	topics            map[string][][]kafka.Message
+	groups            map[string]*memoryGroup
+	// changed is closed and replaced whenever a message is written or a
+	// group rebalances, waking blocked readers.
+	changed chan struct{}
+	now     func() time.Time
+}
+
+type memoryGroup struct {
+	committed  map[string]map[int]int64
+	members    []*memoryReader
+	generation int
+}
+
+// NewMemoryBroker creates topics on first use with defaultPartitions
+// partitions. Use CreateTopic for a different count.
+func NewMemoryBroker(defaultPartitions int) *MemoryBroker {
+	return &MemoryBroker{
+		defaultPartitions: max(defaultPartitions, 1),
+		topics:            make(map[string][][]kafka.Message),
+		groups:            make(map[string]*memoryGroup),
+		changed:           make(chan struct{}),
+		now:               time.Now,
+	}
+}
+
+func (b *MemoryBroker) CreateTopic(topic string, partitions int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.topics[topic]; ok {
+		return fmt.Errorf("topic %s already exists", topic)
+	}
+	if partitions < 1 {
+		return fmt.Errorf("topic %s needs at least one partition", topic)
+	}
+	b.topics[topic] = make([][]kafka.Message, partitions)
+	return nil
+}
+
+// Reader joins groupID, or reads every partition from the start when
+// groupID is empty.
+func (b *MemoryBroker) Reader(topic, groupID string) KafkaReader {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.topic(topic)
+	reader := &memoryReader{broker: b, topic: topic, groupID: groupID, generation: -1}
+	if groupID != "" {
+		group := b.group(groupID)
+		group.members = append(group.members, reader)
+		b.rebalance(group)
+	}
+	return reader
+}
+
+func (b *MemoryBroker) Writer(topic string) KafkaWriter {
+	return &memoryWriter{broker: b, topic: topic}
+}
+
+// Messages returns everything written to topic, ordered by partition and
+// offset.
+func (b *MemoryBroker) Messages(topic string) []kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var messages []kafka.Message
+	for _, partition := range b.topics[topic] {
+		messages = append(messages, partition...)
+	}
+	return messages
+}
+
+// Partitions reports group progress the way brokerGroupInspector does.
+func (b *MemoryBroker) Partitions(ctx context.Context, groupID string, topics []string) (map[string][]*dto.PartitionStatusDTO, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.group(groupID)
+	result := make(map[string][]*dto.PartitionStatusDTO)
+	for _, topic := range topics {
+		assignees := make(map[int]string)
+		for i := range group.members {
+			for _, partition := range b.assignment(topic, group, i) {
+				assignees[partition] = fmt.Sprintf("memory-reader-%d", i)
+			}
+		}
+
+		for partition, messages := range b.topic(topic) {
+			committed, ok := group.committed[topic][partition]
+			if !ok {
+				committed = -1
+			}
+			result[topic] = append(result[topic], partitionStatus(partition, assignees[partition], committed, int64(len(messages))))
+		}
+	}
+	return result, nil
+}
+
+// topic returns the partitions of topic, creating it if needed. b.mu must be
+// held.
+func (b *MemoryBroker) topic(topic string) [][]kafka.Message {
+	partitions, ok := b.topics[topic]
+	if !ok {
+		partitions = make([][]kafka.Message, b.defaultPartitions)
+		b.topics[topic] = partitions
+	}
+	return partitions
+}
+
+func (b *MemoryBroker) group(groupID string) *memoryGroup {
+	group, ok := b.groups[groupID]
+	if !ok {
+		group = &memoryGroup{committed: make(map[string]map[int]int64)}
+		b.groups[groupID] = group
+	}
+	return group
+}
+
+func (b *MemoryBroker) rebalance(group *memoryGroup) {
+	group.generation++
+	b.notify()
+}
+
+func (b *MemoryBroker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// assignment is the partitions of topic owned by the member at index among
+// the group's readers of that topic.
+func (b *MemoryBroker) assignment(topic string, group *memoryGroup, index int) []int {
+	var readers []*memoryReader
+	position := -1
+	for i, member := range group.members {
+		if member.topic != topic {
+			continue
+		}
+		if i == index {
+			position = len(readers)
+		}
+		readers = append(readers, member)
+	}
+	if position < 0 {
+		return nil
+	}
+
+	var partitions []int
+	for partition := range b.topics[topic] {
+		if partition%len(readers) == position {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
+
+type memoryReader struct {
+	broker     *MemoryBroker
+	topic      string
+	groupID    string
+	closed     bool
+	generation int
+	partitions []int
+	positions  map[int]int64
+	next       int
+}
+
+// ReadMessage commits the message before returning it when the reader is in
+// a group, like kafka-go's Reader.ReadMessage.
+func (r *memoryReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	message, err := r.FetchMessage(ctx)
+	if err != nil || r.groupID == "" {
+		return message, err
+	}
+	return message, r.CommitMessages(ctx, message)
+}
+
+func (r *memoryReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	b := r.broker
+	for {
+		b.mu.Lock()
+		if r.closed {
+			b.mu.Unlock()
+			return kafka.Message{}, io.EOF
+		}
+
+		r.sync()
+		if message, ok := r.poll(); ok {
+			b.mu.Unlock()
+			return message, nil
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// sync picks up the reader's partitions after a rebalance and restarts them
+// from the committed offsets. r.broker.mu must be held.
+func (r *memoryReader) sync() {
+	b := r.broker
+	if r.groupID == "" {
+		if r.positions == nil {
+			r.positions = make(map[int]int64)
+			for partition := range b.topic(r.topic) {
+				r.partitions = append(r.partitions, partition)
+			}
+		}
+		return
+	}
+
+	group := b.group(r.groupID)
+	if r.generation == group.generation {
+		return
+	}
+
+	r.generation = group.generation
+	r.partitions = b.assignment(r.topic, group, slices.Index(group.members, r))
+	r.positions = make(map[int]int64)
+	for _, partition := range r.partitions {
+		r.positions[partition] = max(group.committed[r.topic][partition], 0)
+	}
+}
+
+// poll returns the next unread message, taking partitions in turn so that
+// one busy partition does not starve the others.
+func (r *memoryReader) poll() (kafka.Message, bool) {
+	partitions := r.broker.topic(r.topic)
+	for i := range r.partitions {
+		partition := r.partitions[(r.next+i)%len(r.partitions)]
+		position := r.positions[partition]
+		if position < int64(len(partitions[partition])) {
+			r.positions[partition] = position + 1
+			r.next = (r.next + i + 1) % len(r.partitions)
+			return partitions[partition][position], true
+		}
+	}
+	return kafka.Message{}, false
+}
+
+func (r *memoryReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if r.groupID == "" {
+		return fmt.Errorf("commit is unavailable when the reader has no group")
+	}
+
+	b := r.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	group := b.group(r.groupID)
+	for _, message := range msgs {
+		if group.committed[message.Topic] == nil {
+			group.committed[message.Topic] = make(map[int]int64)
+		}
+		if committed, ok := group.committed[message.Topic][message.Partition]; !ok || message.Offset+1 > committed {
+			group.committed[message.Topic][message.Partition] = message.Offset + 1
+		}
+	}
+	return nil
+}
+
+func (r *memoryReader) Config() kafka.ReaderConfig {
+	return kafka.ReaderConfig{Topic: r.topic, GroupID: r.groupID}
+}
+
+// Close leaves the group, which hands the reader's partitions to the
+// remaining readers from the committed offsets.
+func (r *memoryReader) Close() error {
+	b := r.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	if r.groupID != "" {
+		group := b.group(r.groupID)
+		group.members = slices.DeleteFunc(group.members, func(member *memoryReader) bool { return member == r })
+		b.rebalance(group)
+	}
+	return nil
+}
+
+type memoryWriter struct {
+	broker *MemoryBroker
+	topic  string
+	next   int
+}
+
+// WriteMessages appends to the writer's topic, or to each message's topic
+// for a writer created without one. Keyed messages are placed by key hash,
+// others round robin.
+func (w *memoryWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	b := w.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, message := range msgs {
+		topic := w.topic
+		if topic == "" {
+			topic = message.Topic
+		}
+		if topic == "" {
+			return fmt.Errorf("message has no topic")
+		}
+
+		partitions := b.topic(topic)
+		partition := w.next % len(partitions)
+		if message.Key != nil {
+			hash := fnv.New32a()
+			_, _ = hash.Write(message.Key)
+			partition = int(hash.Sum32() % uint32(len(partitions)))
+		} else {
+			w.next++
+		}
+
+		message.Topic = topic
+		message.Partition = partition
+		message.Offset = int64(len(partitions[partition]))
+		if message.Time.IsZero() {
+			message.Time = b.now()
+		}
+		message.Headers = slices.Clone(message.Headers)
+		partitions[partition] = append(partitions[partition], message)
+	}
+
+	b.notify()
+	return nil
+}
+
+func (w *memoryWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,235 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func readMessage(t *testing.T, reader KafkaReader) kafka.Message {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	message, err := reader.FetchMessage(ctx)
+	if err != nil {
+		t.Fatalf("Expected a message, got %v", err)
+	}
+	return message
+}
+
+func writeMessages(t *testing.T, writer KafkaWriter, values ...string) {
+	t.Helper()
+
+	for _, value := range values {
+		if err := writer.WriteMessages(context.Background(), kafka.Message{Key: []byte("user-1"), Value: []byte(value)}); err != nil {
+			t.Fatalf("Expected the message to be written, got %v", err)
+		}
+	}
+}
+
+func TestMemoryBroker_ReadsInOrder(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	writeMessages(t, broker.Writer("transactions"), "a", "b")
+
+	reader := broker.Reader("transactions", "casino")
+	for i, want := range []string{"a", "b"} {
+		message := readMessage(t, reader)
+		if string(message.Value) != want || message.Offset != int64(i) || message.Topic != "transactions" {
+			t.Errorf("Expected %s at offset %d, got %s at %d", want, i, message.Value, message.Offset)
+		}
+		if message.Time.IsZero() {
+			t.Error("Expected the write time to be set")
+		}
+	}
+}
+
+func TestMemoryBroker_KeyedMessagesShareAPartition(t *testing.T) {
+	broker := NewMemoryBroker(4)
+	writer := broker.Writer("transactions")
+	writeMessages(t, writer, "a", "b", "c")
+	if err := writer.WriteMessages(context.Background(), kafka.Message{Value: []byte("d")}, kafka.Message{Value: []byte("e")}); err != nil {
+		t.Fatal(err)
+	}
+
+	partitions := make(map[int]int)
+	for _, message := range broker.Messages("transactions") {
+		if message.Key != nil {
+			partitions[message.Partition]++
+		}
+	}
+	if len(partitions) != 1 {
+		t.Errorf("Expected keyed messages on one partition, got %v", partitions)
+	}
+
+	messages := broker.Messages("transactions")
+	if messages[len(messages)-2].Partition == messages[len(messages)-1].Partition {
+		t.Error("Expected unkeyed messages to be spread over partitions")
+	}
+}
+
+func TestMemoryBroker_ReadBlocksUntilWrite(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	reader := broker.Reader("transactions", "casino")
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = broker.Writer("transactions").WriteMessages(context.Background(), kafka.Message{Value: []byte("late")})
+	}()
+
+	if message := readMessage(t, reader); string(message.Value) != "late" {
+		t.Errorf("Expected the late message, got %s", message.Value)
+	}
+}
+
+func TestMemoryBroker_ReadStopsWithContext(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	reader := broker.Reader("transactions", "casino")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := reader.FetchMessage(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the context error, got %v", err)
+	}
+}
+
+func TestMemoryBroker_RedeliversUncommitted(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	writeMessages(t, broker.Writer("transactions"), "a", "b", "c")
+
+	reader := broker.Reader("transactions", "casino")
+	first := readMessage(t, reader)
+	readMessage(t, reader)
+	if err := reader.CommitMessages(context.Background(), first); err != nil {
+		t.Fatal(err)
+	}
+	reader.Close()
+
+	if _, err := reader.FetchMessage(context.Background()); err != io.EOF {
+		t.Errorf("Expected a closed reader to return io.EOF, got %v", err)
+	}
+
+	reader = broker.Reader("transactions", "casino")
+	if message := readMessage(t, reader); string(message.Value) != "b" {
+		t.Errorf("Expected the uncommitted message to be delivered again, got %s", message.Value)
+	}
+}
+
+func TestMemoryBroker_ReadMessageCommits(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	writeMessages(t, broker.Writer("transactions"), "a", "b")
+
+	reader := broker.Reader("transactions", "casino").(*memoryReader)
+	if _, err := reader.ReadMessage(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	reader.Close()
+
+	if message := readMessage(t, broker.Reader("transactions", "casino")); string(message.Value) != "b" {
+		t.Errorf("Expected ReadMessage to have committed the first message, got %s", message.Value)
+	}
+}
+
+func TestMemoryBroker_GroupsAreIndependent(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	writeMessages(t, broker.Writer("transactions"), "a")
+
+	casino := broker.Reader("transactions", "casino")
+	if err := casino.CommitMessages(context.Background(), readMessage(t, casino)); err != nil {
+		t.Fatal(err)
+	}
+
+	reporting := broker.Reader("transactions", "reporting")
+	if message := readMessage(t, reporting); string(message.Value) != "a" {
+		t.Errorf("Expected another group to start from the beginning, got %s", message.Value)
+	}
+}
+
+func TestMemoryBroker_RebalancesPartitions(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	if err := broker.CreateTopic("transactions", 2); err != nil {
+		t.Fatal(err)
+	}
+	writer := broker.Writer("transactions")
+	if err := writer.WriteMessages(context.Background(), kafka.Message{Value: []byte("a")}, kafka.Message{Value: []byte("b")}); err != nil {
+		t.Fatal(err)
+	}
+
+	first := broker.Reader("transactions", "casino")
+	second := broker.Reader("transactions", "casino")
+	if readMessage(t, first).Partition == readMessage(t, second).Partition {
+		t.Error("Expected each reader to own a different partition")
+	}
+
+	// The first reader never committed, so once the second leaves it gets
+	// both partitions from the start.
+	second.Close()
+	seen := make(map[int]bool)
+	for range 2 {
+		seen[readMessage(t, first).Partition] = true
+	}
+	if len(seen) != 2 {
+		t.Errorf("Expected the remaining reader to take over every partition, got %v", seen)
+	}
+}
+
+func TestMemoryBroker_CommitNeedsGroup(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	writeMessages(t, broker.Writer("transactions"), "a")
+
+	reader := broker.Reader("transactions", "")
+	message := readMessage(t, reader)
+	if err := reader.CommitMessages(context.Background(), message); err == nil {
+		t.Error("Expected commit without a group to fail")
+	}
+}
+
+func TestMemoryBroker_CreateTopic(t *testing.T) {
+	broker := NewMemoryBroker(1)
+	if err := broker.CreateTopic("transactions", 0); err == nil {
+		t.Error("Expected a topic without partitions to be rejected")
+	}
+	if err := broker.CreateTopic("transactions", 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := broker.CreateTopic("transactions", 3); err == nil {
+		t.Error("Expected an existing topic to be rejected")
+	}
+}
+
+func TestMemoryBroker_Partitions(t *testing.T) {
+	broker := NewMemoryBroker(2)
+	writer := broker.Writer("transactions")
+	if err := writer.WriteMessages(context.Background(), kafka.Message{Value: []byte("a")}, kafka.Message{Value: []byte("b")}, kafka.Message{Value: []byte("c")}); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := broker.Reader("transactions", "casino")
+	message := readMessage(t, reader)
+	if err := reader.CommitMessages(context.Background(), message); err != nil {
+		t.Fatal(err)
+	}
+
+	partitions, err := broker.Partitions(context.Background(), "casino", []string{"transactions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status := partitions["transactions"]
+	if len(status) != 2 {
+		t.Fatalf("Expected two partitions, got %d", len(status))
+	}
+	committed, other := status[message.Partition], status[1-message.Partition]
+	if committed.AssignedTo == "" || committed.CommittedOffset == nil || *committed.CommittedOffset != 1 || *committed.Lag != committed.HighWaterMark-1 {
+		t.Errorf("Unexpected status for the committed partition: %+v", committed)
+	}
+	if other.CommittedOffset != nil || other.Lag != nil {
+		t.Errorf("Expected no offsets for a partition without commits, got %+v", other)
+	}
+}
@@ -0,0 +1,126 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"casino/boundary/dto"
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+)
+
+// MultiTopicConsumer runs one KafkaConsumer per configured topic, all in the
+// same consumer group.
+type MultiTopicConsumer struct {
+	groupID   string
+	consumers []*KafkaConsumer
+	inspector groupInspector
+}
+
+func NewMultiTopicConsumer(config *ConsumerConfig, transport Transport, handlers map[string]MessageHandler, auditUseCase usecase.AuditUseCase, logger logging.Logger) (*MultiTopicConsumer, error) {
+	if err := config.validate(handlers); err != nil {
+		return nil, err
+	}
+
+	var avroDecoder *AvroDecoder
+	if config.AvroSchemaDir != "" {
+		avroDecoder = NewAvroDecoder(NewFileSchemaRegistry(config.AvroSchemaDir))
+	}
+
+	consumers := make([]*KafkaConsumer, 0, len(config.Topics))
+	for _, topic := range config.Topics {
+		retry, _ := topic.retryPolicy()
+
+		consumer := &KafkaConsumer{
+			reader:             transport.Reader(topic.Topic, config.GroupID),
+			handler:            handlers[topic.Handler],
+			auditUseCase:       auditUseCase,
+			logger:             logger,
+			defaultContentType: topic.ContentType,
+			retry:              retry,
+		}
+		if avroDecoder != nil {
+			consumer.RegisterDecoder(ContentTypeAvro, avroDecoder)
+		}
+		if topic.DLQTopic != "" {
+			consumer.dlq = transport.Writer(topic.DLQTopic)
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	return &MultiTopicConsumer{
+		groupID:   config.GroupID,
+		consumers: consumers,
+		inspector: transport,
+	}, nil
+}
+
+// Start consumes every topic until ctx is cancelled.
+func (m *MultiTopicConsumer) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, consumer := range m.consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			consumer.Start(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// Status combines what each topic consumer knows about itself with the
+// group's offsets as the brokers see them.
+func (m *MultiTopicConsumer) Status(ctx context.Context) (*dto.ConsumerStatusDTO, error) {
+	status := &dto.ConsumerStatusDTO{GroupID: m.groupID}
+	topics := make([]string, len(m.consumers))
+	for i, consumer := range m.consumers {
+		topicStatus := consumer.status()
+		topics[i] = topicStatus.Topic
+		status.Topics = append(status.Topics, topicStatus)
+	}
+
+	partitions, err := m.inspector.Partitions(ctx, m.groupID, topics)
+	if err != nil {
+		return nil, err
+	}
+	for _, topicStatus := range status.Topics {
+		topicStatus.Partitions = partitions[topicStatus.Topic]
+	}
+	return status, nil
+}
+
+// Pause pauses every topic at once and waits for all of them to drain.
+func (m *MultiTopicConsumer) Pause(ctx context.Context) error {
+	errs := make([]error, len(m.consumers))
+	var wg sync.WaitGroup
+	for i, consumer := range m.consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = consumer.Pause(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MultiTopicConsumer) Resume() {
+	for _, consumer := range m.consumers {
+		consumer.Resume()
+	}
+}
+
+func (m *MultiTopicConsumer) Close() error {
+	var errs []error
+	for _, consumer := range m.consumers {
+		errs = append(errs, consumer.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"casino/boundary/dto"
+)
+
+func TestNewMultiTopicConsumer(t *testing.T) {
+	config := DefaultConsumerConfig()
+	config.Topics = append(config.Topics,
+		TopicConfig{Topic: "casino-rollbacks", Handler: HandlerRollbacks, DLQTopic: "casino-rollbacks-dlq"},
+		TopicConfig{Topic: "casino-deposits", Handler: HandlerDeposits, ContentType: ContentTypeAvro},
+	)
+	handlers := NewHandlers(&MockTransactionUseCase{})
+
+	consumer, err := NewMultiTopicConsumer(config, NewBrokerTransport(config.Brokers), handlers, &MockAuditUseCase{}, &MockLogger{})
+	if err != nil {
+		t.Fatalf("Expected consumer to be created, got %v", err)
+	}
+	defer consumer.Close()
+
+	if len(consumer.consumers) != 3 {
+		t.Fatalf("Expected one consumer per topic, got %d", len(consumer.consumers))
+	}
+
+	transactions, rollbacks, deposits := consumer.consumers[0], consumer.consumers[1], consumer.consumers[2]
+	if transactions.handler != handlers[HandlerTransactions] || rollbacks.handler != handlers[HandlerRollbacks] || deposits.handler != handlers[HandlerDeposits] {
+		t.Error("Expected each topic to be bound to its handler")
+	}
+	if transactions.retry != defaultRetryPolicy {
+		t.Errorf("Expected the configured retry policy, got %+v", transactions.retry)
+	}
+	if transactions.dlq != nil || rollbacks.dlq == nil {
+		t.Error("Expected a dead letter writer only where a dlq_topic is configured")
+	}
+	if deposits.defaultContentType != ContentTypeAvro || deposits.decoders[ContentTypeAvro] == nil {
+		t.Error("Expected the deposits topic to decode Avro by default")
+	}
+}
+
+func TestNewMultiTopicConsumer_InvalidConfig(t *testing.T) {
+	config := DefaultConsumerConfig()
+	config.Topics[0].Handler = "missing"
+
+	if _, err := NewMultiTopicConsumer(config, NewBrokerTransport(config.Brokers), NewHandlers(&MockTransactionUseCase{}), &MockAuditUseCase{}, &MockLogger{}); err == nil {
+		t.Error("Expected an invalid config to be rejected")
+	}
+}
+
+func TestShippedConsumerConfig(t *testing.T) {
+	if _, err := os.Stat("../../config/kafka_consumer.json"); err != nil {
+		t.Skip("shipped config not found")
+	}
+
+	config, err := LoadConsumerConfig("../../config/kafka_consumer.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.validate(NewHandlers(&MockTransactionUseCase{})); err != nil {
+		t.Errorf("Expected the shipped config to be valid, got %v", err)
+	}
+}
+
+type fakeGroupInspector struct {
+	groupID    string
+	topics     []string
+	partitions map[string][]*dto.PartitionStatusDTO
+	err        error
+}
+
+func (i *fakeGroupInspector) Partitions(ctx context.Context, groupID string, topics []string) (map[string][]*dto.PartitionStatusDTO, error) {
+	i.groupID, i.topics = groupID, topics
+	return i.partitions, i.err
+}
+
+func TestMultiTopicConsumer_Status(t *testing.T) {
+	transactions, _, _ := newControlledConsumer()
+	inspector := &fakeGroupInspector{partitions: map[string][]*dto.PartitionStatusDTO{
+		"casino-transactions-stream": {partitionStatus(0, "casino@host-1", 40, 42)},
+	}}
+	consumer := &MultiTopicConsumer{groupID: "casino", consumers: []*KafkaConsumer{transactions}, inspector: inspector}
+
+	if err := consumer.Pause(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	status, err := consumer.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Expected status, got %v", err)
+	}
+
+	if inspector.groupID != "casino" || len(inspector.topics) != 1 || inspector.topics[0] != "casino-transactions-stream" {
+		t.Errorf("Expected the group and its topics to be inspected, got %s %v", inspector.groupID, inspector.topics)
+	}
+	topic := status.Topics[0]
+	if status.GroupID != "casino" || topic.State != dto.ConsumerStatePaused || len(topic.Partitions) != 1 || *topic.Partitions[0].Lag != 2 {
+		t.Errorf("Unexpected status %+v %+v", status, topic)
+	}
+
+	consumer.Resume()
+	if transactions.status().State != dto.ConsumerStateStopped {
+		t.Errorf("Expected the topic consumer to be resumed")
+	}
+
+	inspector.err = errors.New("broker unreachable")
+	if _, err := consumer.Status(context.Background()); err == nil {
+		t.Error("Expected the inspector error")
+	}
+}
@@ -0,0 +1,169 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"casino/boundary/logging"
+	"casino/boundary/repository"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// OutboxRelay publishes outbox events to Kafka in the order they were
+// written and marks each of them as sent. Unsent events are picked up by that
+// mark rather than by a position, so an event that commits after a later id
+// has been published is still relayed; the transaction repository locks per
+// user so that events sharing a key commit in id order. Delivery is at least
+// once: events written to Kafka but not yet marked are published again after
+// a failure, so consumers should deduplicate on the transaction ID. Only the
+// relay holding the outbox's relay lock publishes, so every instance can run
+// one and the others take over when it stops.
+//
+// Sent events are kept for retention, then deleted.
+type OutboxRelay struct {
+	writer     KafkaWriter
+	outboxRepo repository.OutboxRepository
+	logger     logging.Logger
+	batchSize  int
+	interval   time.Duration
+	retention  time.Duration
+	// purgeInterval is how often events past retention are deleted.
+	purgeInterval time.Duration
+	lastPurge     time.Time
+	lock          repository.RelayLock
+}
+
+func NewOutboxRelay(writer KafkaWriter, outboxRepo repository.OutboxRepository, logger logging.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		writer:        writer,
+		outboxRepo:    outboxRepo,
+		logger:        logger,
+		batchSize:     100,
+		interval:      time.Second,
+		retention:     7 * 24 * time.Hour,
+		purgeInterval: time.Hour,
+	}
+}
+
+func (r *OutboxRelay) Start(ctx context.Context) {
+	defer r.unlock()
+
+	for {
+		if r.holdLock(ctx) {
+			sent, err := r.relayBatch(ctx)
+			if err != nil {
+				r.logger.Error(ctx, err)
+			}
+
+			// A full batch suggests a backlog, so keep going without waiting.
+			if err == nil && sent == r.batchSize {
+				continue
+			}
+
+			if time.Since(r.lastPurge) >= r.purgeInterval {
+				if err := r.purge(ctx); err != nil {
+					r.logger.Error(ctx, err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info(ctx, "Outbox relay stopping due to context cancellation")
+			return
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// holdLock reports whether this relay may publish, taking the relay lock
+// when it is free and giving it up once it turns out to be lost.
+func (r *OutboxRelay) holdLock(ctx context.Context) bool {
+	if r.lock != nil {
+		err := r.lock.Check(ctx)
+		if err == nil {
+			return true
+		}
+		r.logger.Error(ctx, err)
+		r.unlock()
+	}
+
+	lock, err := r.outboxRepo.TryLockRelay(ctx)
+	if err != nil {
+		r.logger.Error(ctx, err)
+		return false
+	}
+	if lock == nil {
+		return false
+	}
+
+	r.logger.Info(ctx, "Outbox relay took the relay lock and is publishing")
+	r.lock = lock
+	return true
+}
+
+func (r *OutboxRelay) unlock() {
+	if r.lock != nil {
+		r.lock.Release()
+		r.lock = nil
+	}
+}
+
+func (r *OutboxRelay) relayBatch(ctx context.Context) (int, error) {
+	events, err := r.outboxRepo.GetUnsent(r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	messages := make([]kafka.Message, len(events))
+	ids := make([]uint64, len(events))
+	for i, event := range events {
+		messages[i] = kafka.Message{
+			Key:   []byte(event.Key),
+			Value: event.Payload,
+			Headers: []kafka.Header{
+				{Key: "event_type", Value: []byte(event.EventType)},
+			},
+		}
+		ids[i] = event.ID
+	}
+
+	if err := r.writer.WriteMessages(ctx, messages...); err != nil {
+		return 0, fmt.Errorf("failed to publish outbox events: %w", err)
+	}
+
+	if err := r.outboxRepo.MarkSent(ids, time.Now().UTC()); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
+
+// purge deletes the events sent longer than retention ago.
+func (r *OutboxRelay) purge(ctx context.Context) error {
+	r.lastPurge = time.Now()
+
+	deleted, err := r.outboxRepo.DeleteSentBefore(r.lastPurge.UTC().Add(-r.retention))
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		r.logger.Info(ctx, fmt.Sprintf("Deleted %d sent outbox events", deleted))
+	}
+	return nil
+}
+
+func (r *OutboxRelay) Close() error {
+	return r.writer.Close()
+}
@@ -0,0 +1,245 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type MockKafkaWriter struct {
+	written  []kafka.Message
+	writeErr error
+	closed   bool
+}
+
+func (m *MockKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if m.writeErr != nil {
+		return m.writeErr
+	}
+	m.written = append(m.written, msgs...)
+	return nil
+}
+
+func (m *MockKafkaWriter) Close() error {
+	m.closed = true
+	return nil
+}
+
+type MockOutboxRepository struct {
+	events       []*repo_model.OutboxEventModel
+	getErr       error
+	sentIDs      []uint64
+	deleteBefore []time.Time
+	// lockedElsewhere makes TryLockRelay report the lock as taken.
+	lockedElsewhere bool
+	locks           []*MockRelayLock
+}
+
+func (m *MockOutboxRepository) GetUnsent(limit int) ([]*repo_model.OutboxEventModel, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+
+	sent := make(map[uint64]bool)
+	for _, id := range m.sentIDs {
+		sent[id] = true
+	}
+
+	var unsent []*repo_model.OutboxEventModel
+	for _, event := range m.events {
+		if !sent[event.ID] && len(unsent) < limit {
+			unsent = append(unsent, event)
+		}
+	}
+	return unsent, nil
+}
+
+func (m *MockOutboxRepository) MarkSent(ids []uint64, sentAt time.Time) error {
+	m.sentIDs = append(m.sentIDs, ids...)
+	return nil
+}
+
+func (m *MockOutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	m.deleteBefore = append(m.deleteBefore, before)
+	return 0, nil
+}
+
+func (m *MockOutboxRepository) TryLockRelay(ctx context.Context) (repository.RelayLock, error) {
+	if m.lockedElsewhere {
+		return nil, nil
+	}
+	lock := &MockRelayLock{}
+	m.locks = append(m.locks, lock)
+	return lock, nil
+}
+
+type MockRelayLock struct {
+	checkErr error
+	released bool
+}
+
+func (l *MockRelayLock) Check(ctx context.Context) error {
+	return l.checkErr
+}
+
+func (l *MockRelayLock) Release() {
+	l.released = true
+}
+
+func newTestOutboxRelay(writer *MockKafkaWriter, outboxRepo *MockOutboxRepository) *OutboxRelay {
+	return &OutboxRelay{
+		writer:        writer,
+		outboxRepo:    outboxRepo,
+		logger:        &MockLogger{},
+		batchSize:     2,
+		interval:      time.Millisecond,
+		retention:     time.Hour,
+		purgeInterval: time.Hour,
+	}
+}
+
+func outboxEvents(n int) []*repo_model.OutboxEventModel {
+	events := make([]*repo_model.OutboxEventModel, n)
+	for i := range events {
+		events[i] = &repo_model.OutboxEventModel{
+			ID:        uint64(i + 1),
+			EventType: repo_model.TransactionProcessedEventType,
+			Key:       "user-1",
+			Payload:   []byte(`{"id":"tx"}`),
+		}
+	}
+	return events
+}
+
+func TestOutboxRelay_RelayBatch(t *testing.T) {
+	writer := &MockKafkaWriter{}
+	outboxRepo := &MockOutboxRepository{events: outboxEvents(3)}
+	relay := newTestOutboxRelay(writer, outboxRepo)
+
+	sent, err := relay.relayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent != 2 {
+		t.Errorf("Expected a batch of 2, got %d", sent)
+	}
+
+	message := writer.written[0]
+	if string(message.Key) != "user-1" || string(message.Value) != `{"id":"tx"}` {
+		t.Errorf("Unexpected message %+v", message)
+	}
+	if len(message.Headers) != 1 || string(message.Headers[0].Value) != repo_model.TransactionProcessedEventType {
+		t.Errorf("Expected the event type header, got %+v", message.Headers)
+	}
+
+	if sent, _ := relay.relayBatch(context.Background()); sent != 1 {
+		t.Errorf("Expected the remaining event, got %d", sent)
+	}
+	if sent, _ := relay.relayBatch(context.Background()); sent != 0 {
+		t.Errorf("Expected nothing left, got %d", sent)
+	}
+
+	if len(outboxRepo.sentIDs) != 3 || outboxRepo.sentIDs[0] != 1 || outboxRepo.sentIDs[2] != 3 {
+		t.Errorf("Expected events 1 to 3 marked in order, got %v", outboxRepo.sentIDs)
+	}
+}
+
+func TestOutboxRelay_RelayBatch_WriteError(t *testing.T) {
+	writer := &MockKafkaWriter{writeErr: errors.New("broker unavailable")}
+	outboxRepo := &MockOutboxRepository{events: outboxEvents(1)}
+	relay := newTestOutboxRelay(writer, outboxRepo)
+
+	if _, err := relay.relayBatch(context.Background()); err == nil {
+		t.Fatal("Expected error")
+	}
+
+	if len(outboxRepo.sentIDs) != 0 {
+		t.Error("Expected unpublished events to stay unsent")
+	}
+}
+
+func TestOutboxRelay_Start(t *testing.T) {
+	writer := &MockKafkaWriter{}
+	outboxRepo := &MockOutboxRepository{events: outboxEvents(5)}
+	relay := newTestOutboxRelay(writer, outboxRepo)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	relay.Start(ctx)
+
+	if len(writer.written) != 5 {
+		t.Fatalf("Expected all 5 events to be published, got %d", len(writer.written))
+	}
+	if len(outboxRepo.deleteBefore) != 1 {
+		t.Fatalf("Expected one purge within the purge interval, got %d", len(outboxRepo.deleteBefore))
+	}
+	if age := time.Since(outboxRepo.deleteBefore[0]); age < time.Hour || age > time.Hour+time.Minute {
+		t.Errorf("Expected events sent over an hour ago to be purged, got a cutoff %s ago", age)
+	}
+
+	if len(outboxRepo.locks) != 1 || !outboxRepo.locks[0].released {
+		t.Errorf("Expected the relay lock to be taken once and released on stop, got %d locks", len(outboxRepo.locks))
+	}
+
+	if err := relay.Close(); err != nil || !writer.closed {
+		t.Error("Expected the writer to be closed")
+	}
+}
+
+func TestOutboxRelay_Start_WaitsForTheRelayLock(t *testing.T) {
+	writer := &MockKafkaWriter{}
+	outboxRepo := &MockOutboxRepository{events: outboxEvents(3), lockedElsewhere: true}
+	relay := newTestOutboxRelay(writer, outboxRepo)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	relay.Start(ctx)
+
+	if len(writer.written) != 0 || len(outboxRepo.deleteBefore) != 0 {
+		t.Error("Expected nothing to be published or purged while another relay holds the lock")
+	}
+}
+
+func TestOutboxRelay_HoldLock_RetakesALostLock(t *testing.T) {
+	outboxRepo := &MockOutboxRepository{}
+	relay := newTestOutboxRelay(&MockKafkaWriter{}, outboxRepo)
+
+	if !relay.holdLock(context.Background()) || !relay.holdLock(context.Background()) {
+		t.Fatal("Expected the relay to take and keep the free lock")
+	}
+	if len(outboxRepo.locks) != 1 {
+		t.Fatalf("Expected the held lock to be kept, got %d locks", len(outboxRepo.locks))
+	}
+
+	outboxRepo.locks[0].checkErr = errors.New("connection reset")
+	outboxRepo.lockedElsewhere = true
+	if relay.holdLock(context.Background()) {
+		t.Error("Expected a lost lock taken by another relay to stop publishing")
+	}
+	if !outboxRepo.locks[0].released {
+		t.Error("Expected the lost lock to be released")
+	}
+
+	outboxRepo.lockedElsewhere = false
+	if !relay.holdLock(context.Background()) || len(outboxRepo.locks) != 2 {
+		t.Error("Expected the relay to take the lock again once it is free")
+	}
+}
+
+func TestNewOutboxRelay(t *testing.T) {
+	relay := NewOutboxRelay(NewBrokerTransport([]string{"localhost:9092"}).Writer("casino-transactions-processed"), &MockOutboxRepository{}, &MockLogger{})
+
+	writer, ok := relay.writer.(*kafka.Writer)
+	if !ok {
+		t.Fatal("Expected a kafka.Writer")
+	}
+	if writer.Topic != "casino-transactions-processed" {
+		t.Errorf("Expected topic casino-transactions-processed, got %s", writer.Topic)
+	}
+}
@@ -0,0 +1,382 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"casino/boundary/logging"
+	"casino/boundary/usecase"
+	"casino/domain/entity"
+	"casino/utils"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ReplayOptions selects the window to reprocess. It starts at FromOffset in
+// every partition or at the first message written at or after FromTime, and
+// stops before ToOffset or ToTime. Without an end bound it stops at the end
+// of each partition as it was when the replay began.
+type ReplayOptions struct {
+	Topic      string
+	FromOffset *int64
+	FromTime   *time.Time
+	ToOffset   *int64
+	ToTime     *time.Time
+	// DryRun only looks up whether each transaction is already stored.
+	DryRun bool
+}
+
+type ReplayResult struct {
+	Partitions []*ReplayPartitionResult
+	Read       int
+	New        int
+	Present    int
+	Failed     int
+}
+
+// ReplayPartitionResult is the offset range read from one partition, From
+// inclusive and To exclusive. Reached is the offset after the last message
+// read, which is short of To when the partition went idle before its end.
+type ReplayPartitionResult struct {
+	Partition int
+	From      int64
+	To        int64
+	Reached   int64
+}
+
+// Incomplete reports whether the replay stopped before the end of the range.
+func (p *ReplayPartitionResult) Incomplete() bool {
+	return p.Reached < p.To
+}
+
+// partitionSource hides the broker so the replay logic can be tested.
+type partitionSource interface {
+	Partitions(ctx context.Context, topic string) ([]int, error)
+	// Offsets returns the first offset and the offset the next message will
+	// be written at.
+	Offsets(ctx context.Context, topic string, partition int) (first, end int64, err error)
+	// OffsetAt returns the first offset written at or after t, or -1 if
+	// there is none.
+	OffsetAt(ctx context.Context, topic string, partition int, t time.Time) (int64, error)
+	Reader(topic string, partition int, offset int64) (KafkaReader, error)
+}
+
+// Replayer reprocesses a window of a configured topic through the topic's
+// handler pipeline, reading partitions directly rather than as part of the
+// consumer group so committed offsets are left alone.
+type Replayer struct {
+	source       partitionSource
+	config       *ConsumerConfig
+	handlers     map[string]MessageHandler
+	useCase      usecase.TransactionUseCase
+	auditUseCase usecase.AuditUseCase
+	logger       logging.Logger
+	// idleTimeout ends a partition early when no message arrives. That is
+	// expected when the last offsets before the end are transaction markers,
+	// but a slow broker looks the same, so the partition is reported as
+	// incomplete.
+	idleTimeout time.Duration
+}
+
+func NewReplayer(config *ConsumerConfig, handlers map[string]MessageHandler, useCase usecase.TransactionUseCase, auditUseCase usecase.AuditUseCase, logger logging.Logger) (*Replayer, error) {
+	if err := config.validate(handlers); err != nil {
+		return nil, err
+	}
+
+	return &Replayer{
+		source:       &brokerPartitionSource{brokers: config.Brokers},
+		config:       config,
+		handlers:     handlers,
+		useCase:      useCase,
+		auditUseCase: auditUseCase,
+		logger:       logger,
+		idleTimeout:  10 * time.Second,
+	}, nil
+}
+
+func (r *Replayer) Replay(ctx context.Context, options *ReplayOptions) (*ReplayResult, error) {
+	if (options.FromOffset == nil) == (options.FromTime == nil) {
+		return nil, fmt.Errorf("replay needs either a start offset or a start time")
+	}
+	if options.ToOffset != nil && options.ToTime != nil {
+		return nil, fmt.Errorf("replay takes an end offset or an end time, not both")
+	}
+
+	consumer, err := r.consumer(options.Topic)
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := r.source.Partitions(ctx, options.Topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partitions of %s: %w", options.Topic, err)
+	}
+
+	result := &ReplayResult{}
+	var incomplete []int
+	for _, partition := range partitions {
+		from, to, err := r.bounds(ctx, options, partition)
+		if err != nil {
+			return result, fmt.Errorf("partition %d: %w", partition, err)
+		}
+
+		partitionResult := &ReplayPartitionResult{Partition: partition, From: from, To: to, Reached: from}
+		result.Partitions = append(result.Partitions, partitionResult)
+		if from >= to {
+			continue
+		}
+
+		if err := r.replayPartition(ctx, consumer, options, partitionResult, result); err != nil {
+			return result, fmt.Errorf("partition %d: %w", partition, err)
+		}
+		if partitionResult.Incomplete() {
+			incomplete = append(incomplete, partition)
+		}
+	}
+
+	if len(incomplete) > 0 {
+		return result, fmt.Errorf("partitions %v went idle for %s before reaching the end of the replay", incomplete, r.idleTimeout)
+	}
+	return result, nil
+}
+
+// consumer builds the pipeline the topic is consumed with, minus the reader
+// and the dead letter topic, so failed replays are only logged and audited.
+func (r *Replayer) consumer(topic string) (*KafkaConsumer, error) {
+	for _, topicConfig := range r.config.Topics {
+		if topicConfig.Topic != topic {
+			continue
+		}
+
+		retry, _ := topicConfig.retryPolicy()
+		consumer := &KafkaConsumer{
+			handler:            r.handlers[topicConfig.Handler],
+			auditUseCase:       r.auditUseCase,
+			logger:             r.logger,
+			defaultContentType: topicConfig.ContentType,
+			retry:              retry,
+		}
+		if r.config.AvroSchemaDir != "" {
+			consumer.RegisterDecoder(ContentTypeAvro, NewAvroDecoder(NewFileSchemaRegistry(r.config.AvroSchemaDir)))
+		}
+		return consumer, nil
+	}
+	return nil, fmt.Errorf("topic %s is not configured", topic)
+}
+
+func (r *Replayer) bounds(ctx context.Context, options *ReplayOptions, partition int) (int64, int64, error) {
+	first, end, err := r.source.Offsets(ctx, options.Topic, partition)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	from := first
+	if options.FromOffset != nil {
+		from = max(*options.FromOffset, first)
+	} else {
+		from, err = r.offsetAt(ctx, options.Topic, partition, *options.FromTime, end)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+
+	to := end
+	if options.ToOffset != nil {
+		to = min(*options.ToOffset, end)
+	} else if options.ToTime != nil {
+		to, err = r.offsetAt(ctx, options.Topic, partition, *options.ToTime, end)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	return from, to, nil
+}
+
+func (r *Replayer) offsetAt(ctx context.Context, topic string, partition int, t time.Time, end int64) (int64, error) {
+	offset, err := r.source.OffsetAt(ctx, topic, partition, t)
+	if err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return end, nil
+	}
+	return offset, nil
+}
+
+// replayPartition reads partition.From up to partition.To and records in
+// partition.Reached how far it got.
+func (r *Replayer) replayPartition(ctx context.Context, consumer *KafkaConsumer, options *ReplayOptions, partition *ReplayPartitionResult, result *ReplayResult) error {
+	to := partition.To
+
+	reader, err := r.source.Reader(options.Topic, partition.Partition, partition.From)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, r.idleTimeout)
+		message, err := reader.FetchMessage(readCtx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if message.Offset >= to {
+			partition.Reached = to
+			return nil
+		}
+
+		result.Read++
+		switch outcome := r.replayMessage(messageContext(ctx, message), consumer, message, options.DryRun); {
+		case outcome == nil:
+			result.New++
+		case isDuplicate(outcome):
+			result.Present++
+		default:
+			result.Failed++
+		}
+
+		partition.Reached = message.Offset + 1
+		if message.Offset >= to-1 {
+			return nil
+		}
+	}
+}
+
+// replayMessage returns nil for a message that was, or in a dry run would
+// be, newly applied and a duplicate error for one already stored.
+func (r *Replayer) replayMessage(ctx context.Context, consumer *KafkaConsumer, message kafka.Message, dryRun bool) error {
+	if !dryRun {
+		return consumer.processMessage(ctx, message)
+	}
+
+	envelope, err := (&Message{Message: message, decode: consumer.decode}).Envelope()
+	if err != nil {
+		return err
+	}
+
+	transaction := &envelope.Transaction
+	if transaction.ID != "" {
+		_, err = r.useCase.GetTransaction(transaction.ID)
+		switch {
+		case err == nil:
+			return &utils.TransactionAlreadyExistsError{TransactionID: transaction.ID}
+		case !utils.IsTransactionNotFound(err):
+			return err
+		}
+	}
+
+	if envelope.EventType == EventTypeTransactionCancelled {
+		return r.checkCancelled(transaction.OriginalTransactionID)
+	}
+	if transaction.ID == "" {
+		return &utils.TransactionValidationError{Reason: "id is required"}
+	}
+	return nil
+}
+
+// checkCancelled reports whether cancelling the original would apply. A
+// cancellation may come without an ID of its own, so it is matched to an
+// existing rollback through the original.
+func (r *Replayer) checkCancelled(originalID string) error {
+	original, err := r.useCase.GetTransaction(originalID)
+	if err != nil {
+		return err
+	}
+
+	reversals, err := r.useCase.GetReversals(original.ID)
+	if err != nil {
+		return err
+	}
+	for _, reversal := range reversals {
+		if reversal.TransactionType == string(entity.TransactionTypeRollback) {
+			return &utils.TransactionAlreadyCancelledError{TransactionID: original.ID}
+		}
+	}
+	return nil
+}
+
+type brokerPartitionSource struct {
+	brokers []string
+}
+
+// dial connects through the first broker that answers.
+func (s *brokerPartitionSource) dial(ctx context.Context, connect func(ctx context.Context, broker string) (*kafka.Conn, error)) (*kafka.Conn, error) {
+	var errs []error
+	for _, broker := range s.brokers {
+		conn, err := connect(ctx, broker)
+		if err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", broker, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (s *brokerPartitionSource) dialLeader(ctx context.Context, topic string, partition int) (*kafka.Conn, error) {
+	return s.dial(ctx, func(ctx context.Context, broker string) (*kafka.Conn, error) {
+		return kafka.DialLeader(ctx, "tcp", broker, topic, partition)
+	})
+}
+
+func (s *brokerPartitionSource) Partitions(ctx context.Context, topic string) ([]int, error) {
+	conn, err := s.dial(ctx, func(ctx context.Context, broker string) (*kafka.Conn, error) {
+		return kafka.DialContext(ctx, "tcp", broker)
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, len(partitions))
+	for i, partition := range partitions {
+		ids[i] = partition.ID
+	}
+	return ids, nil
+}
+
+func (s *brokerPartitionSource) Offsets(ctx context.Context, topic string, partition int) (int64, int64, error) {
+	conn, err := s.dialLeader(ctx, topic, partition)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer conn.Close()
+
+	return conn.ReadOffsets()
+}
+
+func (s *brokerPartitionSource) OffsetAt(ctx context.Context, topic string, partition int, t time.Time) (int64, error) {
+	conn, err := s.dialLeader(ctx, topic, partition)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	return conn.ReadOffset(t)
+}
+
+func (s *brokerPartitionSource) Reader(topic string, partition int, offset int64) (KafkaReader, error) {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   s.brokers,
+		Topic:     topic,
+		Partition: partition,
+	})
+	if err := reader.SetOffset(offset); err != nil {
+		reader.Close()
+		return nil, err
+	}
+	return reader, nil
+}
@@ -0,0 +1,296 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	boundarydto "casino/boundary/dto"
+	"casino/utils"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type fakePartition struct {
+	first    int64
+	messages []kafka.Message
+}
+
+type fakePartitionSource struct {
+	partitions map[int]*fakePartition
+	readers    []*fakePartitionReader
+}
+
+func (s *fakePartitionSource) Partitions(ctx context.Context, topic string) ([]int, error) {
+	ids := make([]int, 0, len(s.partitions))
+	for id := 0; id < len(s.partitions); id++ {
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (s *fakePartitionSource) Offsets(ctx context.Context, topic string, partition int) (int64, int64, error) {
+	p := s.partitions[partition]
+	return p.first, p.first + int64(len(p.messages)), nil
+}
+
+func (s *fakePartitionSource) OffsetAt(ctx context.Context, topic string, partition int, t time.Time) (int64, error) {
+	for _, message := range s.partitions[partition].messages {
+		if !message.Time.Before(t) {
+			return message.Offset, nil
+		}
+	}
+	return -1, nil
+}
+
+func (s *fakePartitionSource) Reader(topic string, partition int, offset int64) (KafkaReader, error) {
+	reader := &fakePartitionReader{partition: s.partitions[partition], next: offset}
+	s.readers = append(s.readers, reader)
+	return reader, nil
+}
+
+// fakePartitionReader blocks like a real reader once it runs out of messages.
+type fakePartitionReader struct {
+	partition *fakePartition
+	next      int64
+	closed    bool
+}
+
+func (r *fakePartitionReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	index := r.next - r.partition.first
+	if index >= int64(len(r.partition.messages)) {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	r.next++
+	return r.partition.messages[index], nil
+}
+
+func (r *fakePartitionReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	return fmt.Errorf("not in a consumer group")
+}
+
+func (r *fakePartitionReader) Config() kafka.ReaderConfig {
+	return kafka.ReaderConfig{Topic: "casino-transactions-stream"}
+}
+
+func (r *fakePartitionReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+var replayStart = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newReplayPartition(partition int, first int64, ids ...string) *fakePartition {
+	p := &fakePartition{first: first}
+	for i, id := range ids {
+		p.messages = append(p.messages, kafka.Message{
+			Topic:     "casino-transactions-stream",
+			Partition: partition,
+			Offset:    first + int64(i),
+			Time:      replayStart.Add(time.Duration(i) * time.Minute),
+			Value:     []byte(fmt.Sprintf(`{"id": %q, "user_id": "u1", "transaction_type": "bet", "amount": 10}`, id)),
+		})
+	}
+	return p
+}
+
+func newTestReplayer(source partitionSource, useCase *MockTransactionUseCase, audit *MockAuditUseCase) *Replayer {
+	return &Replayer{
+		source:       source,
+		config:       DefaultConsumerConfig(),
+		handlers:     NewHandlers(useCase),
+		useCase:      useCase,
+		auditUseCase: audit,
+		logger:       &MockLogger{},
+		idleTimeout:  20 * time.Millisecond,
+	}
+}
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestReplayer_Replay_OffsetWindow(t *testing.T) {
+	source := &fakePartitionSource{partitions: map[int]*fakePartition{
+		0: newReplayPartition(0, 0, "a", "b", "c", "d"),
+		1: newReplayPartition(1, 5, "e", "f"),
+	}}
+	useCase := &MockTransactionUseCase{}
+	audit := &MockAuditUseCase{}
+
+	result, err := newTestReplayer(source, useCase, audit).Replay(context.Background(), &ReplayOptions{
+		Topic:      "casino-transactions-stream",
+		FromOffset: int64Ptr(1),
+		ToOffset:   int64Ptr(3),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Partition 0 replays offsets 1 and 2; partition 1 starts at 5, past the end bound.
+	if result.Read != 2 || result.New != 2 || useCase.processCount != 2 {
+		t.Errorf("Expected 2 messages to be replayed, got %+v and %d processed", result, useCase.processCount)
+	}
+	if len(result.Partitions) != 2 || result.Partitions[0].From != 1 || result.Partitions[0].To != 3 || result.Partitions[1].From != 5 || result.Partitions[1].To != 3 {
+		t.Errorf("Unexpected partition ranges %+v %+v", result.Partitions[0], result.Partitions[1])
+	}
+	if len(source.readers) != 1 || !source.readers[0].closed {
+		t.Error("Expected only partition 0 to be read and its reader closed")
+	}
+	if len(audit.records) != 2 {
+		t.Errorf("Expected replayed messages to be audited, got %d records", len(audit.records))
+	}
+}
+
+func TestReplayer_Replay_TimeWindowCountsDuplicates(t *testing.T) {
+	source := &fakePartitionSource{partitions: map[int]*fakePartition{
+		0: newReplayPartition(0, 0, "a", "b", "c", "d"),
+	}}
+	useCase := &MockTransactionUseCase{processError: &utils.TransactionAlreadyExistsError{TransactionID: "b"}}
+	from := replayStart.Add(time.Minute)
+
+	result, err := newTestReplayer(source, useCase, &MockAuditUseCase{}).Replay(context.Background(), &ReplayOptions{
+		Topic:    "casino-transactions-stream",
+		FromTime: &from,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Read != 3 || result.Present != 3 || result.New != 0 {
+		t.Errorf("Expected 3 already present messages, got %+v", result)
+	}
+	if result.Partitions[0].From != 1 || result.Partitions[0].To != 4 {
+		t.Errorf("Expected offsets 1 to 4, got %+v", result.Partitions[0])
+	}
+}
+
+func TestReplayer_Replay_DryRun(t *testing.T) {
+	source := &fakePartitionSource{partitions: map[int]*fakePartition{
+		0: newReplayPartition(0, 0, "a", "b", "c"),
+	}}
+	source.partitions[0].messages = append(source.partitions[0].messages, kafka.Message{Offset: 3, Value: []byte(`not json`)})
+	useCase := &MockTransactionUseCase{stored: map[string]bool{"b": true}}
+	audit := &MockAuditUseCase{}
+
+	result, err := newTestReplayer(source, useCase, audit).Replay(context.Background(), &ReplayOptions{
+		Topic:      "casino-transactions-stream",
+		FromOffset: int64Ptr(0),
+		DryRun:     true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Read != 4 || result.New != 2 || result.Present != 1 || result.Failed != 1 {
+		t.Errorf("Expected 2 new, 1 present and 1 failed, got %+v", result)
+	}
+	if useCase.processCount != 0 || len(audit.records) != 0 {
+		t.Error("Expected a dry run not to process or audit anything")
+	}
+}
+
+func TestReplayer_Replay_DryRunMatchesCancellationsByOriginal(t *testing.T) {
+	partition := &fakePartition{}
+	for i, originalID := range []string{"a", "b", "missing"} {
+		partition.messages = append(partition.messages, kafka.Message{
+			Offset: int64(i),
+			Value:  []byte(fmt.Sprintf(`{"schema_version": 2, "event_type": "transaction.cancelled", "transaction": {"original_transaction_id": %q}}`, originalID)),
+		})
+	}
+	source := &fakePartitionSource{partitions: map[int]*fakePartition{0: partition}}
+	useCase := &MockTransactionUseCase{
+		stored: map[string]bool{"a": true, "b": true},
+		reversals: []*boundarydto.TransactionDTO{
+			{ID: "r1", TransactionType: "rollback", OriginalTransactionID: "a"},
+			{ID: "r2", TransactionType: "refund", OriginalTransactionID: "b"},
+		},
+	}
+
+	result, err := newTestReplayer(source, useCase, &MockAuditUseCase{}).Replay(context.Background(), &ReplayOptions{
+		Topic:      "casino-transactions-stream",
+		FromOffset: int64Ptr(0),
+		DryRun:     true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Read != 3 || result.Present != 1 || result.New != 1 || result.Failed != 1 {
+		t.Errorf("Expected a cancelled, an uncancelled and a missing original, got %+v", result)
+	}
+	if useCase.cancelCount != 0 {
+		t.Error("Expected a dry run not to cancel anything")
+	}
+}
+
+func TestReplayer_Replay_ReportsIdlePartitionAsIncomplete(t *testing.T) {
+	source := &fakePartitionSource{partitions: map[int]*fakePartition{
+		0: newReplayPartition(0, 0, "a", "b"),
+		1: newReplayPartition(1, 0, "c"),
+	}}
+	replayer := newTestReplayer(source, &MockTransactionUseCase{}, &MockAuditUseCase{})
+
+	// Offset 2 of partition 0 never arrives, as with a slow broker or a
+	// trailing transaction marker.
+	replayer.source = &markerPartitionSource{fakePartitionSource: source, partition: 0}
+
+	result, err := replayer.Replay(context.Background(), &ReplayOptions{Topic: "casino-transactions-stream", FromOffset: int64Ptr(0)})
+	if err == nil || !strings.Contains(err.Error(), "partitions [0] went idle") {
+		t.Fatalf("Expected the idle partition to be reported, got %v", err)
+	}
+	if result.Read != 3 {
+		t.Errorf("Expected the other partition to be replayed too, got %+v", result)
+	}
+
+	idle, done := result.Partitions[0], result.Partitions[1]
+	if !idle.Incomplete() || idle.Reached != 2 || idle.To != 3 {
+		t.Errorf("Expected partition 0 to stop at offset 2 of 3, got %+v", idle)
+	}
+	if done.Incomplete() || done.Reached != 1 {
+		t.Errorf("Expected partition 1 to be complete, got %+v", done)
+	}
+}
+
+// markerPartitionSource reports one more offset in partition than the
+// reader returns.
+type markerPartitionSource struct {
+	*fakePartitionSource
+	partition int
+}
+
+func (s *markerPartitionSource) Offsets(ctx context.Context, topic string, partition int) (int64, int64, error) {
+	first, end, err := s.fakePartitionSource.Offsets(ctx, topic, partition)
+	if partition == s.partition {
+		end++
+	}
+	return first, end, err
+}
+
+func TestReplayer_Replay_InvalidOptions(t *testing.T) {
+	from := replayStart
+	testCases := []struct {
+		name     string
+		options  *ReplayOptions
+		expected string
+	}{
+		{"No Start", &ReplayOptions{Topic: "casino-transactions-stream"}, "start offset or a start time"},
+		{"Two Starts", &ReplayOptions{Topic: "casino-transactions-stream", FromOffset: int64Ptr(0), FromTime: &from}, "start offset or a start time"},
+		{"Two Ends", &ReplayOptions{Topic: "casino-transactions-stream", FromOffset: int64Ptr(0), ToOffset: int64Ptr(1), ToTime: &from}, "not both"},
+		{"Unknown Topic", &ReplayOptions{Topic: "casino-bonuses", FromOffset: int64Ptr(0)}, "not configured"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			replayer := newTestReplayer(&fakePartitionSource{}, &MockTransactionUseCase{}, &MockAuditUseCase{})
+
+			_, err := replayer.Replay(context.Background(), tc.options)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Expected error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
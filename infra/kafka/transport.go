@@ -0,0 +1,46 @@
+package kafka
+
+import (
+	"github.com/segmentio/kafka-go"
+)
+
+// Transport opens the readers and writers the consumers and the outbox relay
+// use, and reports consumer group progress. BrokerTransport talks to a real
+// cluster, MemoryBroker keeps everything in process.
+type Transport interface {
+	groupInspector
+	Reader(topic, groupID string) KafkaReader
+	Writer(topic string) KafkaWriter
+}
+
+type BrokerTransport struct {
+	*brokerGroupInspector
+	brokers []string
+}
+
+func NewBrokerTransport(brokers []string) *BrokerTransport {
+	return &BrokerTransport{
+		brokerGroupInspector: newBrokerGroupInspector(brokers),
+		brokers:              brokers,
+	}
+}
+
+func (t *BrokerTransport) Reader(topic, groupID string) KafkaReader {
+	return kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        t.brokers,
+		Topic:          topic,
+		GroupID:        groupID,
+		CommitInterval: 0,
+	})
+}
+
+// Writer partitions by message key so that messages for one user stay in
+// order, and waits for all in-sync replicas.
+func (t *BrokerTransport) Writer(topic string) KafkaWriter {
+	return &kafka.Writer{
+		Addr:         kafka.TCP(t.brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+}
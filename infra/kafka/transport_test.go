@@ -0,0 +1,25 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestBrokerTransport(t *testing.T) {
+	transport := NewBrokerTransport([]string{"localhost:9092"})
+
+	reader := transport.Reader("casino-transactions-stream", "casino")
+	defer reader.Close()
+	if config := reader.Config(); config.Topic != "casino-transactions-stream" || config.GroupID != "casino" {
+		t.Errorf("Unexpected reader config %+v", config)
+	}
+
+	writer, ok := transport.Writer("casino-transactions-processed").(*kafka.Writer)
+	if !ok {
+		t.Fatal("Expected a kafka.Writer")
+	}
+	if writer.Topic != "casino-transactions-processed" || writer.RequiredAcks != kafka.RequireAll {
+		t.Errorf("Unexpected writer %+v", writer)
+	}
+}
@@ -0,0 +1,279 @@
+package logging
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+type RotatingFileLoggerConfig struct {
+	Path         string
+	MaxSize      int64
+	MaxAge       time.Duration
+	MaxBackups   int
+	MaxBackupAge time.Duration
+	Compress     bool
+}
+
+// RotatingFileLogger writes log lines to a file and rotates it once it grows
+// past MaxSize bytes or gets older than MaxAge. Rotated files are optionally
+// gzipped. Only the newest MaxBackups of them are kept, and none that was
+// rotated more than MaxBackupAge ago; a zero limit does not apply.
+type RotatingFileLogger struct {
+	cfg      RotatingFileLoggerConfig
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+	compress func(path string) error
+}
+
+func NewRotatingFileLogger(cfg RotatingFileLoggerConfig) (*RotatingFileLogger, error) {
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("log file path is required")
+	}
+
+	l := &RotatingFileLogger{
+		cfg:      cfg,
+		now:      time.Now,
+		compress: compressFile,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *RotatingFileLogger) Error(ctx context.Context, errs ...error) {
+	l.write(formatLine(ctx, "ERROR", errs))
+}
+
+func (l *RotatingFileLogger) Info(ctx context.Context, msgs ...string) {
+	l.write(formatLine(ctx, "INFO ", msgs))
+}
+
+func (l *RotatingFileLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+func (l *RotatingFileLogger) write(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return
+	}
+
+	if l.shouldRotate(int64(len(line))) {
+		if err := l.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to rotate log file %s: %v\n", l.cfg.Path, err)
+		}
+	}
+
+	n, err := l.file.WriteString(line)
+	l.size += int64(n)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write log file %s: %v\n", l.cfg.Path, err)
+	}
+}
+
+func (l *RotatingFileLogger) shouldRotate(nextWrite int64) bool {
+	if l.size == 0 {
+		return false
+	}
+	if l.cfg.MaxSize > 0 && l.size+nextWrite > l.cfg.MaxSize {
+		return true
+	}
+	return l.cfg.MaxAge > 0 && l.now().Sub(l.openedAt) >= l.cfg.MaxAge
+}
+
+func (l *RotatingFileLogger) open() error {
+	if err := os.MkdirAll(filepath.Dir(l.cfg.Path), 0o755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(l.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	l.file = file
+	l.size = info.Size()
+	l.openedAt = l.now()
+	if l.size > 0 {
+		l.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+func (l *RotatingFileLogger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	l.file = nil
+
+	backup := l.backupName(l.now())
+	if err := os.Rename(l.cfg.Path, backup); err != nil {
+		if openErr := l.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+
+	if err := l.open(); err != nil {
+		return err
+	}
+
+	// A backup that fails to compress is kept as it is and still pruned.
+	var err error
+	if l.cfg.Compress {
+		err = l.compress(backup)
+	}
+	return errors.Join(err, l.prune())
+}
+
+// backupName returns the name for a backup rotated at t. Names only resolve
+// milliseconds, so t is moved forward past any backup that already exists
+// rather than overwriting it.
+func (l *RotatingFileLogger) backupName(t time.Time) string {
+	dir, prefix, ext := l.nameParts()
+	t = t.UTC().Truncate(time.Millisecond)
+	for {
+		name := filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func (l *RotatingFileLogger) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(l.cfg.Path)
+	base := filepath.Base(l.cfg.Path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+func (l *RotatingFileLogger) backups() ([]string, error) {
+	dir, prefix, _ := l.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		if _, ok := l.rotatedAt(entry.Name()); !ok {
+			continue
+		}
+		names = append(names, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// rotatedAt reads the rotation time from the name of a backup file.
+func (l *RotatingFileLogger) rotatedAt(name string) (time.Time, bool) {
+	_, prefix, ext := l.nameParts()
+	stamp := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(name), ".gz"), ext)
+	t, err := time.Parse(backupTimeFormat, strings.TrimPrefix(stamp, prefix))
+	return t, err == nil
+}
+
+func (l *RotatingFileLogger) prune() error {
+	if l.cfg.MaxBackups <= 0 && l.cfg.MaxBackupAge <= 0 {
+		return nil
+	}
+
+	names, err := l.backups()
+	if err != nil {
+		return err
+	}
+
+	// names are oldest first, so both limits remove a prefix of them.
+	expired := 0
+	if l.cfg.MaxBackups > 0 && len(names) > l.cfg.MaxBackups {
+		expired = len(names) - l.cfg.MaxBackups
+	}
+	if l.cfg.MaxBackupAge > 0 {
+		cutoff := l.now().Add(-l.cfg.MaxBackupAge)
+		for expired < len(names) {
+			if rotatedAt, _ := l.rotatedAt(names[expired]); !rotatedAt.Before(cutoff) {
+				break
+			}
+			expired++
+		}
+	}
+
+	for _, name := range names[:expired] {
+		if err := os.Remove(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,311 @@
+package logging
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewRotatingFileLogger_EmptyPath(t *testing.T) {
+	_, err := NewRotatingFileLogger(RotatingFileLoggerConfig{})
+	if err == nil {
+		t.Error("Expected error for empty path")
+	}
+}
+
+func TestRotatingFileLogger_WritesLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ctx := withAppName(context.Background(), "test-app")
+	logger.Info(ctx, "test message")
+	logger.Error(ctx, fmt.Errorf("test error"))
+	logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected log file to exist, got %v", err)
+	}
+
+	content := string(data)
+	if !strings.Contains(content, "INFO") || !strings.Contains(content, "test message") {
+		t.Errorf("Expected info line in log file, got %s", content)
+	}
+	if !strings.Contains(content, "ERROR") || !strings.Contains(content, "test error") {
+		t.Errorf("Expected error line in log file, got %s", content)
+	}
+	if !strings.Contains(content, "[test-app]") {
+		t.Errorf("Expected app name in log file, got %s", content)
+	}
+}
+
+func TestRotatingFileLogger_RotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{Path: path, MaxSize: 100})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer logger.Close()
+
+	current := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger.now = func() time.Time {
+		current = current.Add(time.Second)
+		return current
+	}
+
+	for i := 0; i < 5; i++ {
+		logger.Info(context.Background(), strings.Repeat("x", 60))
+	}
+
+	backups, err := logger.backups()
+	if err != nil {
+		t.Fatalf("Expected no error listing backups, got %v", err)
+	}
+	if len(backups) != 4 {
+		t.Errorf("Expected 4 backups, got %d", len(backups))
+	}
+}
+
+func TestRotatingFileLogger_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{Path: path, MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer logger.Close()
+
+	current := time.Now()
+	logger.now = func() time.Time { return current }
+	logger.openedAt = current
+
+	logger.Info(context.Background(), "first")
+	logger.Info(context.Background(), "second")
+
+	backups, _ := logger.backups()
+	if len(backups) != 0 {
+		t.Errorf("Expected no backups before max age, got %d", len(backups))
+	}
+
+	current = current.Add(2 * time.Hour)
+	logger.Info(context.Background(), "third")
+
+	backups, _ = logger.backups()
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup after max age, got %d", len(backups))
+	}
+
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "first") || !strings.Contains(string(data), "third") {
+		t.Errorf("Expected only new lines in current file, got %s", string(data))
+	}
+}
+
+func TestRotatingFileLogger_CompressesAndPrunes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{
+		Path:         path,
+		MaxSize:      50,
+		MaxBackupAge: 90 * time.Second,
+		Compress:     true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer logger.Close()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := start
+	logger.now = func() time.Time { return current }
+
+	// Every line rotates the one before it, a minute after the last rotation.
+	for i := 0; i < 6; i++ {
+		current = start.Add(time.Duration(i) * time.Minute)
+		logger.Info(context.Background(), fmt.Sprintf("line-%d %s", i, strings.Repeat("y", 40)))
+	}
+
+	backups, err := logger.backups()
+	if err != nil {
+		t.Fatalf("Expected no error listing backups, got %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected the 2 backups rotated within 90 seconds to be kept, got %d", len(backups))
+	}
+
+	for _, backup := range backups {
+		if !strings.HasSuffix(backup, ".gz") {
+			t.Errorf("Expected compressed backup, got %s", backup)
+		}
+	}
+
+	file, err := os.Open(backups[len(backups)-1])
+	if err != nil {
+		t.Fatalf("Expected backup to open, got %v", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Expected valid gzip, got %v", err)
+	}
+	data, _ := io.ReadAll(gz)
+	if !strings.Contains(string(data), "line-4") {
+		t.Errorf("Expected newest backup to contain line-4, got %s", string(data))
+	}
+}
+
+func TestRotatingFileLogger_PrunesByCount(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{
+		Path:         path,
+		MaxSize:      50,
+		MaxBackups:   2,
+		MaxBackupAge: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer logger.Close()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := start
+	logger.now = func() time.Time { return current }
+
+	// All five rotations are within the age limit, so only the count applies.
+	for i := 0; i < 6; i++ {
+		current = start.Add(time.Duration(i) * time.Minute)
+		logger.Info(context.Background(), fmt.Sprintf("line-%d %s", i, strings.Repeat("y", 40)))
+	}
+
+	backups, err := logger.backups()
+	if err != nil {
+		t.Fatalf("Expected no error listing backups, got %v", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected the newest 2 backups to be kept, got %v", backups)
+	}
+	data, _ := os.ReadFile(backups[0])
+	if !strings.Contains(string(data), "line-3") {
+		t.Errorf("Expected the oldest kept backup to contain line-3, got %s", data)
+	}
+}
+
+func TestRotatingFileLogger_RotationsWithinAMillisecond(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{Path: path, MaxSize: 50, Compress: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer logger.Close()
+
+	current := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger.now = func() time.Time { return current }
+
+	for i := 0; i < 4; i++ {
+		logger.Info(context.Background(), fmt.Sprintf("line-%d %s", i, strings.Repeat("z", 40)))
+	}
+
+	backups, err := logger.backups()
+	if err != nil {
+		t.Fatalf("Expected no error listing backups, got %v", err)
+	}
+	if len(backups) != 3 {
+		t.Fatalf("Expected every rotation to keep its own backup, got %v", backups)
+	}
+	for i, backup := range backups {
+		file, err := os.Open(backup)
+		if err != nil {
+			t.Fatalf("Expected backup to open, got %v", err)
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatalf("Expected valid gzip, got %v", err)
+		}
+		data, _ := io.ReadAll(gz)
+		file.Close()
+		if !strings.Contains(string(data), fmt.Sprintf("line-%d", i)) {
+			t.Errorf("Expected backup %s to contain line-%d, got %s", backup, i, data)
+		}
+	}
+}
+
+func TestRotatingFileLogger_PrunesWhenCompressionFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{
+		Path:         path,
+		MaxSize:      50,
+		MaxBackupAge: time.Hour,
+		Compress:     true,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer logger.Close()
+
+	current := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	logger.now = func() time.Time { return current }
+
+	logger.compress = func(path string) error { return fmt.Errorf("disk full") }
+
+	expired := logger.backupName(current.Add(-2 * time.Hour))
+	if err := os.WriteFile(expired, []byte("old"), 0o644); err != nil {
+		t.Fatalf("Failed to write old backup: %v", err)
+	}
+
+	logger.Info(context.Background(), strings.Repeat("x", 60))
+	if err := logger.rotate(); err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Errorf("Expected the compression error to be returned, got %v", err)
+	}
+
+	if _, err := os.Stat(expired); !os.IsNotExist(err) {
+		t.Error("Expected the expired backup to be pruned despite the compression error")
+	}
+	backups, _ := logger.backups()
+	if len(backups) != 1 || strings.HasSuffix(backups[0], ".gz") {
+		t.Errorf("Expected the uncompressed backup to be kept, got %v", backups)
+	}
+}
+
+func TestRotatingFileLogger_WriteAfterClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	logger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := logger.Close(); err != nil {
+		t.Errorf("Expected no error on close, got %v", err)
+	}
+	logger.Info(context.Background(), "dropped")
+
+	if err := logger.Close(); err != nil {
+		t.Errorf("Expected no error on second close, got %v", err)
+	}
+}
+
+func TestAsyncLogger_RegisterRotatingFileLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "casino.log")
+	fileLogger, err := NewRotatingFileLogger(RotatingFileLoggerConfig{Path: path})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer fileLogger.Close()
+
+	logger := NewAsyncLogger("test-app")
+	logger.Register(fileLogger)
+	defer logger.Close()
+
+	if len(logger.loggers) != 1 {
+		t.Errorf("Expected 1 logger, got %d", len(logger.loggers))
+	}
+}
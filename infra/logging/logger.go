@@ -26,6 +26,7 @@ type logMessage struct {
 type AsyncLogger struct {
 	appName string
 	ch      chan logMessage
+	done    chan struct{}
 	mu      sync.RWMutex
 	loggers []logging.Logger
 }
@@ -33,6 +34,7 @@ type AsyncLogger struct {
 func NewAsyncLogger(appName string) *AsyncLogger {
 	f := &AsyncLogger{
 		ch:      make(chan logMessage, 2),
+		done:    make(chan struct{}),
 		appName: appName,
 	}
 	go f.run()
@@ -53,11 +55,15 @@ func (f *AsyncLogger) Info(ctx context.Context, msgs ...string) {
 	f.ch <- logMessage{ctx: ctx, logType: logTypeInfo, msgs: msgs}
 }
 
+// Close stops accepting messages and returns once the queued ones have been
+// handed to every registered logger, so those can be closed afterwards.
 func (f *AsyncLogger) Close() {
 	close(f.ch)
+	<-f.done
 }
 
 func (f *AsyncLogger) run() {
+	defer close(f.done)
 	for m := range f.ch {
 		f.mu.RLock()
 		for _, l := range f.loggers {
@@ -74,11 +80,19 @@ func (f *AsyncLogger) run() {
 type SimpleLogger struct{}
 
 func (s *SimpleLogger) Error(ctx context.Context, errs ...error) {
-	fmt.Printf("[%s] ERROR [%s] [%s] %v\n", appNameFromCtx(ctx), requestIDFromCtx(ctx), time.Now().Format(time.RFC3339), errs)
+	fmt.Print(formatLine(ctx, "ERROR", errs))
 }
 
 func (s *SimpleLogger) Info(ctx context.Context, msgs ...string) {
-	fmt.Printf("[%s] INFO  [%s] [%s] %v\n", appNameFromCtx(ctx), requestIDFromCtx(ctx), time.Now().Format(time.RFC3339), msgs)
+	fmt.Print(formatLine(ctx, "INFO ", msgs))
+}
+
+func formatLine(ctx context.Context, level string, payload any) string {
+	line := fmt.Sprintf("[%s] %s [%s] [%s] %v", appNameFromCtx(ctx), level, requestIDFromCtx(ctx), time.Now().Format(time.RFC3339), payload)
+	if fields := utils.LogFieldsFromCtx(ctx); len(fields) > 0 {
+		line += " " + utils.FormatLogFields(fields)
+	}
+	return line + "\n"
 }
 
 type ctxKey string
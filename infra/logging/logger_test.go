@@ -3,7 +3,10 @@ package logging
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"casino/utils"
 )
@@ -115,3 +118,53 @@ func TestRequestIDFromCtx(t *testing.T) {
 		t.Errorf("Expected requestID %s, got %s", requestID, retrievedRequestID)
 	}
 }
+
+func TestFormatLine_LogFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), utils.CtxKeyRequestID, "req-1")
+	ctx = utils.WithLogFields(ctx, utils.LogField{Key: "topic", Value: "transactions"}, utils.LogField{Key: "offset", Value: "42"})
+
+	line := formatLine(ctx, "INFO ", []string{"saved"})
+
+	if !strings.Contains(line, "[req-1]") {
+		t.Errorf("Expected request ID in line, got %q", line)
+	}
+	if !strings.HasSuffix(line, "[saved] topic=transactions offset=42\n") {
+		t.Errorf("Expected log fields after payload, got %q", line)
+	}
+}
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) Error(ctx context.Context, errs ...error) {
+	time.Sleep(time.Millisecond)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprint(errs))
+}
+
+func (r *recordingLogger) Info(ctx context.Context, msgs ...string) {
+	time.Sleep(time.Millisecond)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprint(msgs))
+}
+
+func TestAsyncLogger_CloseDrainsQueue(t *testing.T) {
+	logger := NewAsyncLogger("test-app")
+	recorder := &recordingLogger{}
+	logger.Register(recorder)
+
+	for i := 0; i < 10; i++ {
+		logger.Info(context.Background(), fmt.Sprintf("message %d", i))
+	}
+	logger.Close()
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if len(recorder.lines) != 10 {
+		t.Errorf("Expected all 10 messages to be delivered before Close returned, got %d", len(recorder.lines))
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"casino/boundary/logging"
+	"casino/utils"
+)
+
+// Authenticator returns whom a bearer token was issued to.
+type Authenticator interface {
+	AuthenticateUser(token string) (string, error)
+}
+
+// AuthMiddleware rejects requests without a valid bearer token and stores
+// the token's subject in the context under utils.CtxKeyActor, so handlers
+// never take the actor from anything the caller can simply claim.
+func AuthMiddleware(handler http.HandlerFunc, authenticator Authenticator, logger logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			logger.Error(r.Context(), fmt.Errorf("missing admin token"))
+			http.Error(w, "admin token is required", http.StatusUnauthorized)
+			return
+		}
+
+		actor, err := authenticator.AuthenticateUser(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			logger.Error(r.Context(), err)
+			http.Error(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), utils.CtxKeyActor, actor)))
+	}
+}
+
+// UserMiddleware rejects requests unless they carry a user token issued for
+// the user in the {id} path parameter, so players only read their own data.
+func UserMiddleware(handler http.HandlerFunc, authenticator Authenticator, logger logging.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Bearer ") {
+			logger.Error(r.Context(), fmt.Errorf("missing user token"))
+			http.Error(w, "user token is required", http.StatusUnauthorized)
+			return
+		}
+
+		userID, err := authenticator.AuthenticateUser(strings.TrimPrefix(header, "Bearer "))
+		if err != nil {
+			logger.Error(r.Context(), err)
+			http.Error(w, "invalid user token", http.StatusUnauthorized)
+			return
+		}
+
+		if userID != r.PathValue("id") {
+			logger.Error(r.Context(), fmt.Errorf("token for user %s used for user %s", userID, r.PathValue("id")))
+			http.Error(w, "token does not belong to this user", http.StatusForbidden)
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	}
+}
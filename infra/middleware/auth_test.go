@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"casino/utils"
+)
+
+type MockAuthenticator struct{}
+
+func (m *MockAuthenticator) AuthenticateUser(token string) (string, error) {
+	if token != "valid" {
+		return "", &utils.UnauthorizedError{Reason: "invalid token signature"}
+	}
+	return "ops@casino", nil
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		authorization string
+		expectStatus  int
+	}{
+		{"Valid Token", "Bearer valid", http.StatusOK},
+		{"Invalid Token", "Bearer forged", http.StatusUnauthorized},
+		{"Missing Token", "", http.StatusUnauthorized},
+		{"Not Bearer", "Basic valid", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var actor any
+			handler := AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+				actor = r.Context().Value(utils.CtxKeyActor)
+				w.WriteHeader(http.StatusOK)
+			}, &MockAuthenticator{}, &MockLogger{})
+
+			req := httptest.NewRequest("POST", "/admin/kafka/pause", nil)
+			req.Header.Set("X-Actor", "someone-else")
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != tc.expectStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectStatus, rr.Code)
+			}
+			if tc.expectStatus == http.StatusOK && actor != "ops@casino" {
+				t.Errorf("Expected the actor from the token, got %v", actor)
+			}
+			if tc.expectStatus != http.StatusOK && actor != nil {
+				t.Error("Expected the handler not to run")
+			}
+		})
+	}
+}
+
+func TestUserMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		authorization string
+		userID        string
+		expectStatus  int
+	}{
+		{"Own Data", "Bearer valid", "ops@casino", http.StatusOK},
+		{"Another User", "Bearer valid", "user-2", http.StatusForbidden},
+		{"Invalid Token", "Bearer forged", "ops@casino", http.StatusUnauthorized},
+		{"Missing Token", "", "ops@casino", http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			called := false
+			handler := UserMiddleware(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			}, &MockAuthenticator{}, &MockLogger{})
+
+			req := httptest.NewRequest("GET", "/users/"+tc.userID+"/balances", nil)
+			req.SetPathValue("id", tc.userID)
+			if tc.authorization != "" {
+				req.Header.Set("Authorization", tc.authorization)
+			}
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			if rr.Code != tc.expectStatus {
+				t.Fatalf("Expected status %d, got %d", tc.expectStatus, rr.Code)
+			}
+			if called != (tc.expectStatus == http.StatusOK) {
+				t.Errorf("Expected the handler to run only for the token's own user, ran: %v", called)
+			}
+		})
+	}
+}
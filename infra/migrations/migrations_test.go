@@ -0,0 +1,96 @@
+package migrations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad_Embedded(t *testing.T) {
+	migrations, err := Load(FS())
+	if err != nil {
+		t.Fatalf("Expected the embedded migrations to load, got %v", err)
+	}
+
+	if len(migrations) < 11 {
+		t.Fatalf("Expected at least 11 migrations, got %d", len(migrations))
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("Expected version %d, got %d", i+1, migration.Version)
+		}
+		if migration.Up == "" || migration.Down == "" {
+			t.Errorf("Expected migration %d to have up and down SQL", migration.Version)
+		}
+	}
+	if migrations[0].Name != "init" {
+		t.Errorf("Expected the first migration to be init, got %s", migrations[0].Name)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"Bad Name", fstest.MapFS{"001-init.up.sql": {}}},
+		{"Missing Down", fstest.MapFS{"001_init.up.sql": {Data: []byte("SELECT 1")}}},
+		{"Missing Up", fstest.MapFS{"001_init.down.sql": {Data: []byte("SELECT 1")}}},
+		{"Conflicting Names", fstest.MapFS{"001_init.up.sql": {}, "001_other.down.sql": {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.files); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestLoad_Order(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"010_later.up.sql":     {Data: []byte("up 10")},
+		"010_later.down.sql":   {Data: []byte("down 10")},
+		"002_earlier.up.sql":   {Data: []byte("up 2")},
+		"002_earlier.down.sql": {Data: []byte("down 2")},
+		"README.md":            {},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 {
+		t.Fatalf("Expected versions 2 and 10 in order, got %+v", migrations)
+	}
+	if migrations[1].Up != "up 10" || migrations[1].Down != "down 10" {
+		t.Errorf("Unexpected SQL %+v", migrations[1])
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"001_init.up.sql", "001_init.down.sql", "002_audit.up.sql", "002_audit.down.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	up, down, err := Create(dir, "add_bonus")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if filepath.Base(up) != "003_add_bonus.up.sql" || filepath.Base(down) != "003_add_bonus.down.sql" {
+		t.Errorf("Unexpected files %s and %s", up, down)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil || len(migrations) != 3 {
+		t.Errorf("Expected the new migration to load, got %d, %v", len(migrations), err)
+	}
+
+	if _, _, err := Create(dir, "Bad Name"); err == nil {
+		t.Error("Expected an invalid name to be rejected")
+	}
+}
@@ -0,0 +1,176 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestMigrator(t *testing.T, migrations []*Migration) (*Migrator, *sql.DB) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("Skipping test: failed to connect to database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: opens its own database.
+	sqlDB.SetMaxOpenConns(1)
+
+	migrator := NewMigrator(sqlDB, migrations)
+	migrator.lock = func(ctx context.Context, conn *sql.Conn) (func(), error) {
+		return func() {}, nil
+	}
+	migrator.now = func() time.Time {
+		return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return migrator, sqlDB
+}
+
+func testMigrations() []*Migration {
+	return []*Migration{
+		{Version: 1, Name: "players", Up: "CREATE TABLE players (id TEXT PRIMARY KEY);", Down: "DROP TABLE players;"},
+		{Version: 2, Name: "bonuses", Up: "CREATE TABLE bonuses (id TEXT PRIMARY KEY); CREATE INDEX idx_bonuses ON bonuses (id);", Down: "DROP TABLE bonuses;"},
+		{Version: 3, Name: "wallets", Up: "CREATE TABLE wallets (id TEXT PRIMARY KEY);", Down: "DROP TABLE wallets;"},
+	}
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count > 0
+}
+
+func versions(migrations []*Migration) []int64 {
+	result := make([]int64, len(migrations))
+	for i, migration := range migrations {
+		result[i] = migration.Version
+	}
+	return result
+}
+
+func TestMigrator_Up(t *testing.T) {
+	migrator, db := setupTestMigrator(t, testMigrations())
+	ctx := context.Background()
+
+	applied, err := migrator.Up(ctx, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := versions(applied); len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("Expected versions 1 and 2 to be applied, got %v", got)
+	}
+	if !tableExists(t, db, "bonuses") || tableExists(t, db, "wallets") {
+		t.Error("Expected only the first two migrations to run")
+	}
+
+	applied, err = migrator.Up(ctx, 0)
+	if err != nil || len(applied) != 1 || applied[0].Version != 3 {
+		t.Errorf("Expected the remaining migration to be applied, got %v, %v", versions(applied), err)
+	}
+
+	applied, err = migrator.Up(ctx, 0)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Expected nothing left to apply, got %v, %v", versions(applied), err)
+	}
+}
+
+func TestMigrator_UpFailureRollsBack(t *testing.T) {
+	migrations := testMigrations()
+	migrations[1].Up = "CREATE TABLE bonuses (id TEXT PRIMARY KEY); NOT SQL;"
+	migrator, db := setupTestMigrator(t, migrations)
+
+	applied, err := migrator.Up(context.Background(), 0)
+	if err == nil {
+		t.Fatal("Expected the broken migration to fail")
+	}
+	if len(applied) != 1 {
+		t.Errorf("Expected only the first migration to be applied, got %v", versions(applied))
+	}
+	if tableExists(t, db, "bonuses") {
+		t.Error("Expected the failed migration to be rolled back")
+	}
+
+	pending, err := migrator.Pending(context.Background())
+	if err != nil || pending != 2 {
+		t.Errorf("Expected 2 pending migrations, got %d, %v", pending, err)
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	migrator, db := setupTestMigrator(t, testMigrations())
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, err := migrator.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := versions(reverted); len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Errorf("Expected versions 3 and 2 to be reverted, got %v", got)
+	}
+	if !tableExists(t, db, "players") || tableExists(t, db, "bonuses") {
+		t.Error("Expected only the last two migrations to be reverted")
+	}
+
+	reverted, err = migrator.Down(ctx, 5)
+	if err != nil || len(reverted) != 1 {
+		t.Errorf("Expected the last applied migration to be reverted, got %v, %v", versions(reverted), err)
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	migrator, db := setupTestMigrator(t, testMigrations())
+	ctx := context.Background()
+	if _, err := migrator.Up(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO schema_migrations (version, name, applied_at) VALUES (7, 'future', ?)", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statuses) != 4 {
+		t.Fatalf("Expected 4 statuses, got %d", len(statuses))
+	}
+	if statuses[0].AppliedAt == nil || !statuses[0].AppliedAt.Equal(migrator.now()) {
+		t.Errorf("Expected the first migration to be applied, got %+v", statuses[0])
+	}
+	if statuses[1].AppliedAt != nil || statuses[2].AppliedAt != nil {
+		t.Error("Expected the other migrations to be pending")
+	}
+	if !statuses[3].Missing || statuses[3].Version != 7 {
+		t.Errorf("Expected the unknown applied version to be reported as missing, got %+v", statuses[3])
+	}
+
+	if _, err := migrator.Down(ctx, 1); err == nil {
+		t.Error("Expected reverting a migration without files to fail")
+	}
+}
+
+func TestMigrator_LockError(t *testing.T) {
+	migrator, _ := setupTestMigrator(t, testMigrations())
+	locked := errors.New("locked")
+	migrator.lock = func(ctx context.Context, conn *sql.Conn) (func(), error) {
+		return nil, locked
+	}
+
+	if _, err := migrator.Up(context.Background(), 0); !errors.Is(err, locked) {
+		t.Errorf("Expected the lock error, got %v", err)
+	}
+}
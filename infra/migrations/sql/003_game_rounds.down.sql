@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_round_id;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS round_id,
+    DROP COLUMN IF EXISTS game_id,
+    DROP COLUMN IF EXISTS provider_id;
@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS round_id VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS game_id VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS provider_id VARCHAR(100) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_round_id ON transactions (round_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_transactions_original_transaction_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS original_transaction_id;
+
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+
+ALTER TABLE transactions ALTER COLUMN transaction_type TYPE VARCHAR(10);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('bet', 'win'));
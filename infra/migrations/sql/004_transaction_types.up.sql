@@ -0,0 +1,12 @@
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_transaction_type_check;
+
+ALTER TABLE transactions ALTER COLUMN transaction_type TYPE VARCHAR(20);
+
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_transaction_type_check
+    CHECK (transaction_type IN ('bet', 'win', 'deposit', 'withdrawal', 'refund', 'rollback', 'bonus_credit'));
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS original_transaction_id UUID REFERENCES transactions (id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_original_transaction_id ON transactions (original_transaction_id);
@@ -0,0 +1,6 @@
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reason VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_single_rollback
+    ON transactions (original_transaction_id)
+    WHERE transaction_type = 'rollback';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_user_id_currency;
+DROP INDEX IF EXISTS idx_transactions_currency;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE transactions ALTER COLUMN amount TYPE INTEGER;
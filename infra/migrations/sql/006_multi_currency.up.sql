@@ -0,0 +1,7 @@
+ALTER TABLE transactions ALTER COLUMN amount TYPE BIGINT;
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(10) NOT NULL DEFAULT 'EUR';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_currency ON transactions (currency);
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id_currency ON transactions (user_id, currency);
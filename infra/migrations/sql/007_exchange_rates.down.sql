@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS reporting_amount,
+    DROP COLUMN IF EXISTS exchange_rate;
+
+DROP TABLE IF EXISTS exchange_rates;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id BIGSERIAL PRIMARY KEY,
+    currency VARCHAR(10) NOT NULL,
+    rate NUMERIC(36, 18) NOT NULL CHECK (rate > 0),
+    effective_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchange_rates_currency_effective_at ON exchange_rates (currency, effective_at);
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS reporting_amount BIGINT,
+    ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(36, 18);
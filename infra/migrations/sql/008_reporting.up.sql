@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_timestamp ON transactions (timestamp);
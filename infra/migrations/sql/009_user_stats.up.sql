@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_transactions_user_id_timestamp ON transactions (user_id, timestamp);
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS daily_user_aggregates (
+    day DATE NOT NULL,
+    user_id UUID NOT NULL,
+    currency VARCHAR(10) NOT NULL,
+    transaction_type VARCHAR(20) NOT NULL,
+    original_transaction_type VARCHAR(20) NOT NULL DEFAULT '',
+    amount_sum BIGINT NOT NULL DEFAULT 0,
+    reporting_amount_sum BIGINT NOT NULL DEFAULT 0,
+    count BIGINT NOT NULL DEFAULT 0,
+    unconverted_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, user_id, currency, transaction_type, original_transaction_type)
+);
+
+-- Transactions saved before this migration are aggregated here, so reports
+-- served from the table include the whole history. This is the grouping the
+-- backfill-aggregates command uses.
+INSERT INTO daily_user_aggregates (day, user_id, currency, transaction_type, original_transaction_type, amount_sum, reporting_amount_sum, count, unconverted_count)
+SELECT
+    DATE(t.timestamp),
+    t.user_id,
+    t.currency,
+    t.transaction_type,
+    COALESCE(o.transaction_type, ''),
+    SUM(t.amount),
+    COALESCE(SUM(t.reporting_amount), 0),
+    COUNT(*),
+    COALESCE(SUM(CASE WHEN t.reporting_amount IS NULL THEN 1 ELSE 0 END), 0)
+FROM transactions AS t
+LEFT JOIN transactions AS o ON o.id = t.original_transaction_id
+GROUP BY DATE(t.timestamp), t.user_id, t.currency, t.transaction_type, o.transaction_type;
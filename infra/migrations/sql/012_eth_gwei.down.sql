@@ -0,0 +1,2 @@
+-- 012 changes no rows, so there is nothing to undo.
+SELECT 1;
@@ -0,0 +1,9 @@
+-- ETH amounts are counted in gwei from here on. Transactions are never
+-- rewritten, so rather than converting ETH rows recorded in wei this stops
+-- the upgrade while there are any, to be settled by an operator first.
+DO $$
+BEGIN
+	IF EXISTS (SELECT 1 FROM transactions WHERE currency = 'ETH') THEN
+		RAISE EXCEPTION 'ETH transactions are recorded in wei; settle them before amounts change to gwei';
+	END IF;
+END $$;
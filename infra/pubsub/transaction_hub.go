@@ -0,0 +1,100 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+
+	"casino/boundary/dto"
+)
+
+type subscriber struct {
+	filter *dto.TransactionStreamFilterDTO
+	events chan *dto.TransactionEventDTO
+}
+
+// TransactionHub fans published transactions out to in-process subscribers.
+// It keeps the most recent events so that a reconnecting client can resume
+// from the last event it received.
+type TransactionHub struct {
+	mu          sync.Mutex
+	lastID      uint64
+	history     []*dto.TransactionEventDTO
+	historySize int
+	bufferSize  int
+	subscribers map[*subscriber]struct{}
+}
+
+func NewTransactionHub(historySize, bufferSize int) *TransactionHub {
+	return &TransactionHub{
+		// IDs start from the clock so that they keep increasing across
+		// restarts and a client resuming against a new process gets
+		// everything that process has published.
+		lastID:      uint64(time.Now().UnixNano()),
+		historySize: historySize,
+		bufferSize:  bufferSize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func (h *TransactionHub) Publish(transaction *dto.TransactionDTO) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	event := &dto.TransactionEventDTO{ID: h.lastID, Transaction: transaction}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for sub := range h.subscribers {
+		if !sub.filter.Matches(transaction) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			// A subscriber that cannot keep up is dropped rather than
+			// blocking ingestion. It can reconnect and resume from history.
+			h.remove(sub)
+		}
+	}
+}
+
+func (h *TransactionHub) Subscribe(filter *dto.TransactionStreamFilterDTO, lastEventID uint64) (<-chan *dto.TransactionEventDTO, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []*dto.TransactionEventDTO
+	if lastEventID > 0 {
+		for _, event := range h.history {
+			if event.ID > lastEventID && filter.Matches(event.Transaction) {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	sub := &subscriber{
+		filter: filter,
+		events: make(chan *dto.TransactionEventDTO, h.bufferSize+len(replay)),
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub.events, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(sub)
+	}
+}
+
+func (h *TransactionHub) remove(sub *subscriber) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	close(sub.events)
+}
@@ -0,0 +1,108 @@
+package pubsub
+
+import (
+	"testing"
+
+	"casino/boundary/dto"
+)
+
+func receive(t *testing.T, events <-chan *dto.TransactionEventDTO) *dto.TransactionEventDTO {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	default:
+		t.Fatal("Expected an event")
+		return nil
+	}
+}
+
+func expectNoEvent(t *testing.T, events <-chan *dto.TransactionEventDTO) {
+	t.Helper()
+	select {
+	case event := <-events:
+		t.Fatalf("Expected no event, got %+v", event)
+	default:
+	}
+}
+
+func TestTransactionHub_PublishFiltersSubscribers(t *testing.T) {
+	hub := NewTransactionHub(10, 10)
+
+	userID := "user-1"
+	minAmount := uint(100)
+	filtered, _ := hub.Subscribe(&dto.TransactionStreamFilterDTO{UserID: &userID, MinAmount: &minAmount}, 0)
+	all, _ := hub.Subscribe(nil, 0)
+
+	hub.Publish(&dto.TransactionDTO{ID: "tx-1", UserID: "user-1", Amount: 50})
+	hub.Publish(&dto.TransactionDTO{ID: "tx-2", UserID: "user-2", Amount: 500})
+	hub.Publish(&dto.TransactionDTO{ID: "tx-3", UserID: "user-1", Amount: 500})
+
+	if event := receive(t, filtered); event.Transaction.ID != "tx-3" {
+		t.Errorf("Expected tx-3, got %s", event.Transaction.ID)
+	}
+	expectNoEvent(t, filtered)
+
+	first, second, third := receive(t, all), receive(t, all), receive(t, all)
+	if first.Transaction.ID != "tx-1" || third.Transaction.ID != "tx-3" {
+		t.Errorf("Expected events in publish order, got %s and %s", first.Transaction.ID, third.Transaction.ID)
+	}
+	if second.ID != first.ID+1 || third.ID != second.ID+1 {
+		t.Errorf("Expected consecutive IDs, got %d, %d, %d", first.ID, second.ID, third.ID)
+	}
+}
+
+func TestTransactionHub_ResumeFromLastEventID(t *testing.T) {
+	hub := NewTransactionHub(2, 10)
+
+	events, unsubscribe := hub.Subscribe(nil, 0)
+	for _, id := range []string{"tx-1", "tx-2", "tx-3"} {
+		hub.Publish(&dto.TransactionDTO{ID: id})
+	}
+	first := receive(t, events)
+	second := receive(t, events)
+	unsubscribe()
+
+	resumed, _ := hub.Subscribe(nil, second.ID)
+	if event := receive(t, resumed); event.Transaction.ID != "tx-3" {
+		t.Errorf("Expected tx-3 to be replayed, got %s", event.Transaction.ID)
+	}
+	expectNoEvent(t, resumed)
+
+	// tx-1 has already left the history, so only what is kept is replayed.
+	resumed, _ = hub.Subscribe(nil, first.ID-1)
+	if event := receive(t, resumed); event.Transaction.ID != "tx-2" {
+		t.Errorf("Expected replay to start at the oldest kept event, got %s", event.Transaction.ID)
+	}
+}
+
+func TestTransactionHub_DropsSlowSubscriber(t *testing.T) {
+	hub := NewTransactionHub(10, 1)
+
+	events, unsubscribe := hub.Subscribe(nil, 0)
+	hub.Publish(&dto.TransactionDTO{ID: "tx-1"})
+	hub.Publish(&dto.TransactionDTO{ID: "tx-2"})
+
+	if event := receive(t, events); event.Transaction.ID != "tx-1" {
+		t.Errorf("Expected tx-1, got %s", event.Transaction.ID)
+	}
+	if _, ok := <-events; ok {
+		t.Error("Expected the channel of a slow subscriber to be closed")
+	}
+
+	// Unsubscribing after being dropped must not close the channel twice.
+	unsubscribe()
+}
+
+func TestTransactionHub_Unsubscribe(t *testing.T) {
+	hub := NewTransactionHub(10, 10)
+
+	events, unsubscribe := hub.Subscribe(nil, 0)
+	unsubscribe()
+	unsubscribe()
+
+	hub.Publish(&dto.TransactionDTO{ID: "tx-1"})
+	if _, ok := <-events; ok {
+		t.Error("Expected no events after unsubscribing")
+	}
+}
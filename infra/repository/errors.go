@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"casino/boundary/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// classifiedError keeps the database error and its message while also
+// matching one of the repository sentinel errors.
+type classifiedError struct {
+	kind error
+	err  error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// classify tags err with the repository error it corresponds to, from gorm's
+// own errors, Postgres SQLSTATE codes or connection failures. Errors that
+// fit none of them are returned unchanged.
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	if kind := kindOf(err); kind != nil {
+		return &classifiedError{kind: kind, err: err}
+	}
+	return err
+}
+
+func kindOf(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505":
+			return repository.ErrDuplicate
+		// Class 08 is connection exceptions, 57P01 to 57P03 a server that is
+		// shutting down or starting up.
+		case strings.HasPrefix(pgErr.Code, "08"), pgErr.Code == "57P01", pgErr.Code == "57P02", pgErr.Code == "57P03":
+			return repository.ErrUnavailable
+		case strings.HasPrefix(pgErr.Code, "23"):
+			return repository.ErrConstraint
+		}
+		return nil
+	}
+
+	var netErr *net.OpError
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return repository.ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return repository.ErrDuplicate
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return repository.ErrConstraint
+	case errors.As(err, &netErr), errors.Is(err, driver.ErrBadConn), pgconn.SafeToRetry(err):
+		return repository.ErrUnavailable
+	}
+	return nil
+}
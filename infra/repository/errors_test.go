@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"casino/boundary/repository"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"Unique Violation", &pgconn.PgError{Code: "23505"}, repository.ErrDuplicate},
+		{"Foreign Key Violation", &pgconn.PgError{Code: "23503"}, repository.ErrConstraint},
+		{"Check Violation", &pgconn.PgError{Code: "23514"}, repository.ErrConstraint},
+		{"Connection Failure", &pgconn.PgError{Code: "08006"}, repository.ErrUnavailable},
+		{"Admin Shutdown", &pgconn.PgError{Code: "57P01"}, repository.ErrUnavailable},
+		{"Undefined Table", &pgconn.PgError{Code: "42P01"}, nil},
+		{"Wrapped Pg Error", fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505"}), repository.ErrDuplicate},
+		{"Record Not Found", gorm.ErrRecordNotFound, repository.ErrNotFound},
+		{"Translated Duplicate", gorm.ErrDuplicatedKey, repository.ErrDuplicate},
+		{"Translated Foreign Key", gorm.ErrForeignKeyViolated, repository.ErrConstraint},
+		{"Dial Error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, repository.ErrUnavailable},
+		{"Bad Connection", driver.ErrBadConn, repository.ErrUnavailable},
+		{"Unknown", errors.New("failed to connect to database"), nil},
+	}
+
+	kinds := []error{repository.ErrNotFound, repository.ErrDuplicate, repository.ErrUnavailable, repository.ErrConstraint}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classify(tc.err)
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected the original error to be kept, got %v", err)
+			}
+			if err.Error() != tc.err.Error() {
+				t.Errorf("Expected message %q, got %q", tc.err.Error(), err.Error())
+			}
+			for _, kind := range kinds {
+				if errors.Is(err, kind) != (kind == tc.expected) {
+					t.Errorf("Expected errors.Is(%v) to be %v", kind, kind == tc.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestClassify_Nil(t *testing.T) {
+	if err := classify(nil); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+}
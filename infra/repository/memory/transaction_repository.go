@@ -0,0 +1,248 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/domain/entity"
+)
+
+// TransactionRepository keeps transactions in memory for tests and local
+// runs. It enforces the same keys as the transactions table, a unique ID and
+// at most one rollback per original, but keeps no daily aggregates or outbox
+// events. Models are copied in and out, so callers cannot change stored rows.
+type TransactionRepository struct {
+	mu           sync.RWMutex
+	transactions map[string]*repo_model.TransactionModel
+}
+
+func NewTransactionRepository() repository.TransactionRepository {
+	return &TransactionRepository{transactions: make(map[string]*repo_model.TransactionModel)}
+}
+
+func (r *TransactionRepository) Save(transaction *repo_model.TransactionModel) error {
+	if transaction == nil {
+		return fmt.Errorf("transaction cannot be nil")
+	}
+
+	return r.SaveBatch([]*repo_model.TransactionModel{transaction})
+}
+
+// SaveChecked holds the write lock from reading the totals and linked
+// transactions to the insert.
+func (r *TransactionRepository) SaveChecked(transaction *repo_model.TransactionModel, check func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error) error {
+	if transaction == nil {
+		return fmt.Errorf("transaction cannot be nil")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var linked []*repo_model.TransactionModel
+	if transaction.OriginalTransactionID != nil {
+		for _, stored := range r.transactions {
+			if stored.OriginalTransactionID != nil && *stored.OriginalTransactionID == *transaction.OriginalTransactionID {
+				linked = append(linked, clone(stored))
+			}
+		}
+		slices.SortFunc(linked, oldestFirst)
+	}
+
+	if err := check(r.totals(transaction.UserID), linked); err != nil {
+		return err
+	}
+	return r.saveBatch([]*repo_model.TransactionModel{transaction})
+}
+
+// SaveBatch stores all transactions or, if any of them conflicts, none.
+func (r *TransactionRepository) SaveBatch(transactions []*repo_model.TransactionModel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.saveBatch(transactions)
+}
+
+func (r *TransactionRepository) saveBatch(transactions []*repo_model.TransactionModel) error {
+	ids := make(map[string]bool)
+	rollbacks := make(map[string]bool)
+	for _, transaction := range transactions {
+		if _, ok := r.transactions[transaction.ID]; ok || ids[transaction.ID] {
+			return fmt.Errorf("failed to save transaction: id %s: %w", transaction.ID, repository.ErrDuplicate)
+		}
+		ids[transaction.ID] = true
+
+		if transaction.TransactionType == string(entity.TransactionTypeRollback) && transaction.OriginalTransactionID != nil {
+			originalID := *transaction.OriginalTransactionID
+			if rollbacks[originalID] || r.rolledBack(originalID) {
+				return fmt.Errorf("failed to save transaction: rollback of %s: %w", originalID, repository.ErrDuplicate)
+			}
+			rollbacks[originalID] = true
+		}
+	}
+
+	for _, transaction := range transactions {
+		stored := clone(transaction)
+		if stored.Currency == "" {
+			stored.Currency = string(entity.DefaultCurrency)
+		}
+		r.transactions[stored.ID] = stored
+	}
+	return nil
+}
+
+func (r *TransactionRepository) rolledBack(originalID string) bool {
+	for _, transaction := range r.transactions {
+		if transaction.TransactionType == string(entity.TransactionTypeRollback) && transaction.OriginalTransactionID != nil && *transaction.OriginalTransactionID == originalID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *TransactionRepository) GetByID(id string) (*repo_model.TransactionModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	transaction, ok := r.transactions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return clone(transaction), nil
+}
+
+func (r *TransactionRepository) GetByUserID(userID string, transactionType, currency *string) ([]*repo_model.TransactionModel, error) {
+	return r.find(newestFirst, func(transaction *repo_model.TransactionModel) bool {
+		return transaction.UserID == userID && matches(transaction, transactionType, currency)
+	}), nil
+}
+
+func (r *TransactionRepository) GetAll(transactionType, currency *string) ([]*repo_model.TransactionModel, error) {
+	return r.find(newestFirst, func(transaction *repo_model.TransactionModel) bool {
+		return matches(transaction, transactionType, currency)
+	}), nil
+}
+
+func (r *TransactionRepository) GetByRoundID(roundID string) ([]*repo_model.TransactionModel, error) {
+	return r.find(oldestFirst, func(transaction *repo_model.TransactionModel) bool {
+		return transaction.RoundID == roundID
+	}), nil
+}
+
+func (r *TransactionRepository) GetByOriginalID(originalID string) ([]*repo_model.TransactionModel, error) {
+	return r.find(oldestFirst, func(transaction *repo_model.TransactionModel) bool {
+		return transaction.OriginalTransactionID != nil && *transaction.OriginalTransactionID == originalID
+	}), nil
+}
+
+func (r *TransactionRepository) GetTotals(userID string) ([]*repo_model.TransactionTotalModel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.totals(userID), nil
+}
+
+func (r *TransactionRepository) totals(userID string) []*repo_model.TransactionTotalModel {
+	type totalKey struct {
+		currency                string
+		transactionType         string
+		originalTransactionType string
+	}
+
+	var totals []*repo_model.TransactionTotalModel
+	byKey := make(map[totalKey]*repo_model.TransactionTotalModel)
+	for _, transaction := range r.transactions {
+		if transaction.UserID != userID {
+			continue
+		}
+
+		key := totalKey{currency: transaction.Currency, transactionType: transaction.TransactionType}
+		if transaction.OriginalTransactionID != nil {
+			if original, ok := r.transactions[*transaction.OriginalTransactionID]; ok {
+				key.originalTransactionType = original.TransactionType
+			}
+		}
+
+		total, ok := byKey[key]
+		if !ok {
+			total = &repo_model.TransactionTotalModel{
+				Currency:                key.currency,
+				TransactionType:         key.transactionType,
+				OriginalTransactionType: key.originalTransactionType,
+			}
+			byKey[key] = total
+			totals = append(totals, total)
+		}
+		total.Total += int64(transaction.Amount)
+		total.Count++
+	}
+	return totals
+}
+
+// Stream works on a snapshot taken when it starts, so fn may use the
+// repository without deadlocking.
+func (r *TransactionRepository) Stream(ctx context.Context, userID, transactionType, currency *string, fn func(*repo_model.TransactionModel) error) error {
+	transactions := r.find(oldestFirst, func(transaction *repo_model.TransactionModel) bool {
+		return (userID == nil || transaction.UserID == *userID) && matches(transaction, transactionType, currency)
+	})
+
+	for _, transaction := range transactions {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("failed to stream transactions: %w", err)
+		}
+		if err := fn(transaction); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *TransactionRepository) find(order func(a, b *repo_model.TransactionModel) int, keep func(*repo_model.TransactionModel) bool) []*repo_model.TransactionModel {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*repo_model.TransactionModel
+	for _, transaction := range r.transactions {
+		if keep(transaction) {
+			result = append(result, clone(transaction))
+		}
+	}
+	slices.SortFunc(result, order)
+	return result
+}
+
+func matches(transaction *repo_model.TransactionModel, transactionType, currency *string) bool {
+	return (transactionType == nil || transaction.TransactionType == *transactionType) &&
+		(currency == nil || transaction.Currency == *currency)
+}
+
+// Ties on the timestamp are broken by ID so results do not depend on map
+// iteration order.
+func oldestFirst(a, b *repo_model.TransactionModel) int {
+	return cmp.Or(a.Timestamp.Compare(b.Timestamp), cmp.Compare(a.ID, b.ID))
+}
+
+func newestFirst(a, b *repo_model.TransactionModel) int {
+	return oldestFirst(b, a)
+}
+
+func clone(transaction *repo_model.TransactionModel) *repo_model.TransactionModel {
+	copied := *transaction
+	if transaction.OriginalTransactionID != nil {
+		originalID := *transaction.OriginalTransactionID
+		copied.OriginalTransactionID = &originalID
+	}
+	if transaction.ReportingAmount != nil {
+		amount := *transaction.ReportingAmount
+		copied.ReportingAmount = &amount
+	}
+	if transaction.ExchangeRate != nil {
+		rate := *transaction.ExchangeRate
+		copied.ExchangeRate = &rate
+	}
+	return &copied
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/infra/repository/repositorytest"
+	"casino/utils"
+)
+
+func TestTransactionRepository_Contract(t *testing.T) {
+	repositorytest.TransactionRepository(t, func(t *testing.T) repository.TransactionRepository {
+		return NewTransactionRepository()
+	})
+}
+
+func TestTransactionRepository_ReturnsCopies(t *testing.T) {
+	repo := NewTransactionRepository()
+	model := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: utils.GenerateUUID(), TransactionType: "bet", Amount: 100, Timestamp: time.Now()}
+	if err := repo.Save(model); err != nil {
+		t.Fatal(err)
+	}
+
+	model.Amount = 200
+	saved, _ := repo.GetByID(model.ID)
+	saved.Amount = 300
+
+	saved, _ = repo.GetByID(model.ID)
+	if saved.Amount != 100 {
+		t.Errorf("Expected stored transactions to be unaffected by callers, got amount %d", saved.Amount)
+	}
+	if model.Currency != "" {
+		t.Errorf("Expected the saved model to be left as it was, got currency %q", model.Currency)
+	}
+}
+
+func TestTransactionRepository_Concurrent(t *testing.T) {
+	repo := NewTransactionRepository()
+	userID := utils.GenerateUUID()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			model := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 1, Timestamp: time.Now()}
+			if err := repo.Save(model); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := repo.GetByUserID(userID, nil, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	models, _ := repo.GetByUserID(userID, nil, nil)
+	if len(models) != 50 {
+		t.Errorf("Expected 50 transactions, got %d", len(models))
+	}
+}
+
+func TestTransactionRepository_SaveCheckedSerialisesUser(t *testing.T) {
+	repo := NewTransactionRepository()
+	userID := utils.GenerateUUID()
+	deposit := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "deposit", Amount: 100, Timestamp: time.Now()}
+	if err := repo.Save(deposit); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each withdrawal of 30 only goes through while the balance covers it.
+	check := func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		var balance int64
+		for _, total := range totals {
+			if total.TransactionType == "withdrawal" {
+				balance -= total.Total
+			} else {
+				balance += total.Total
+			}
+		}
+		if balance < 30 {
+			return errors.New("insufficient balance")
+		}
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			model := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "withdrawal", Amount: 30, Timestamp: time.Now()}
+			repo.SaveChecked(model, check)
+		}()
+	}
+	wg.Wait()
+
+	withdrawal := "withdrawal"
+	models, _ := repo.GetByUserID(userID, &withdrawal, nil)
+	if len(models) != 3 {
+		t.Errorf("Expected 3 withdrawals to fit the balance, got %d", len(models))
+	}
+}
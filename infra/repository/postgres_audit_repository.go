@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+
+	"gorm.io/gorm"
+)
+
+// auditAppendLockKey is the pg advisory lock id ("audi") serialising appends.
+const auditAppendLockKey = 0x61756469
+
+type PostgresAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresAuditRepository(db *gorm.DB) repository.AuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+func (r *PostgresAuditRepository) Append(build func(last *repo_model.AuditRecordModel) (*repo_model.AuditRecordModel, error)) error {
+	if r.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditAppendLockKey).Error; err != nil {
+				return fmt.Errorf("failed to lock audit log: %w", classify(err))
+			}
+		}
+
+		var last *repo_model.AuditRecordModel
+		var models []*repo_model.AuditRecordModel
+		if err := tx.Order("sequence DESC").Limit(1).Find(&models).Error; err != nil {
+			return fmt.Errorf("failed to get last audit record: %w", classify(err))
+		}
+		if len(models) > 0 {
+			last = models[0]
+		}
+
+		record, err := build(last)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Create(record).Error; err != nil {
+			return fmt.Errorf("failed to save audit record: %w", classify(err))
+		}
+		return nil
+	})
+}
+
+func (r *PostgresAuditRepository) GetRange(from, to time.Time) ([]*repo_model.AuditRecordModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var models []*repo_model.AuditRecordModel
+	err := r.db.Where("timestamp >= ? AND timestamp <= ?", from, to).
+		Order("sequence ASC").
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit records: %w", classify(err))
+	}
+
+	return models, nil
+}
+
+func (r *PostgresAuditRepository) GetBefore(sequence uint64) (*repo_model.AuditRecordModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var models []*repo_model.AuditRecordModel
+	err := r.db.Where("sequence < ?", sequence).
+		Order("sequence DESC").
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previous audit record: %w", classify(err))
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	return models[0], nil
+}
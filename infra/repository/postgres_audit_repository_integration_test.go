@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/utils"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupAuditTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("Skipping test: failed to connect to database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&repo_model.AuditRecordModel{}); err != nil {
+		t.Skipf("Skipping test: failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func appendAuditRecord(t *testing.T, repo *PostgresAuditRepository, timestamp time.Time) *repo_model.AuditRecordModel {
+	var appended *repo_model.AuditRecordModel
+	err := repo.Append(func(last *repo_model.AuditRecordModel) (*repo_model.AuditRecordModel, error) {
+		prevHash := ""
+		if last != nil {
+			prevHash = last.Hash
+		}
+		appended = &repo_model.AuditRecordModel{
+			ID:          utils.GenerateUUID(),
+			Timestamp:   timestamp,
+			Actor:       "kafka-consumer",
+			Action:      "transaction.process",
+			Source:      "kafka:test/0/1",
+			PayloadHash: "payload",
+			Outcome:     "accepted",
+			PrevHash:    prevHash,
+			Hash:        utils.GenerateUUID(),
+		}
+		return appended, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error appending audit record, got %v", err)
+	}
+	return appended
+}
+
+func TestPostgresAuditRepository_Integration_AppendLinksLastRecord(t *testing.T) {
+	db := setupAuditTestDB(t)
+	repo := NewPostgresAuditRepository(db).(*PostgresAuditRepository)
+
+	now := time.Now().UTC()
+	first := appendAuditRecord(t, repo, now)
+	second := appendAuditRecord(t, repo, now.Add(time.Second))
+
+	if first.PrevHash != "" {
+		t.Errorf("Expected first record to have empty prev hash, got %s", first.PrevHash)
+	}
+
+	if second.PrevHash != first.Hash {
+		t.Errorf("Expected second record to link to first, got %s", second.PrevHash)
+	}
+
+	if second.Sequence <= first.Sequence {
+		t.Errorf("Expected increasing sequence, got %d then %d", first.Sequence, second.Sequence)
+	}
+}
+
+func TestPostgresAuditRepository_Integration_GetRangeAndBefore(t *testing.T) {
+	db := setupAuditTestDB(t)
+	repo := NewPostgresAuditRepository(db).(*PostgresAuditRepository)
+
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	first := appendAuditRecord(t, repo, base)
+	second := appendAuditRecord(t, repo, base.Add(time.Hour))
+	third := appendAuditRecord(t, repo, base.Add(2*time.Hour))
+
+	models, err := repo.GetRange(base.Add(30*time.Minute), base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 records in range, got %d", len(models))
+	}
+
+	if models[0].ID != second.ID || models[1].ID != third.ID {
+		t.Error("Expected records ordered by sequence")
+	}
+
+	previous, err := repo.GetBefore(models[0].Sequence)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if previous == nil || previous.ID != first.ID {
+		t.Error("Expected previous record to be the first one")
+	}
+
+	none, err := repo.GetBefore(first.Sequence)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if none != nil {
+		t.Error("Expected no record before the first one")
+	}
+}
+
+func TestPostgresAuditRepository_NilDB(t *testing.T) {
+	repo := NewPostgresAuditRepository(nil)
+
+	if err := repo.Append(nil); err == nil {
+		t.Error("Expected error for nil DB on Append")
+	}
+
+	if _, err := repo.GetRange(time.Time{}, time.Now()); err == nil {
+		t.Error("Expected error for nil DB on GetRange")
+	}
+
+	if _, err := repo.GetBefore(1); err == nil {
+		t.Error("Expected error for nil DB on GetBefore")
+	}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PostgresExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresExchangeRateRepository(db *gorm.DB) repository.ExchangeRateRepository {
+	return &PostgresExchangeRateRepository{db: db}
+}
+
+// SaveAll stores a batch of rates atomically. Loading a rate for a currency
+// and effective time that already exists corrects it; transactions converted
+// earlier keep the rate they were stored with.
+func (r *PostgresExchangeRateRepository) SaveAll(rates []*repo_model.ExchangeRateModel) error {
+	if r.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	if len(rates) == 0 {
+		return nil
+	}
+
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}, {Name: "effective_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate"}),
+	}).Create(&rates).Error
+	if err != nil {
+		return fmt.Errorf("failed to save exchange rates: %w", classify(err))
+	}
+
+	return nil
+}
+
+func (r *PostgresExchangeRateRepository) GetEffective(currency string, at time.Time) (*repo_model.ExchangeRateModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var models []*repo_model.ExchangeRateModel
+	err := r.db.Where("currency = ? AND effective_at <= ?", currency, at).
+		Order("effective_at DESC").
+		Limit(1).
+		Find(&models).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exchange rate: %w", classify(err))
+	}
+
+	if len(models) == 0 {
+		return nil, nil
+	}
+	return models[0], nil
+}
+
+func (r *PostgresExchangeRateRepository) GetAll(currency *string) ([]*repo_model.ExchangeRateModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var models []*repo_model.ExchangeRateModel
+	query := r.db
+
+	if currency != nil {
+		query = query.Where("currency = ?", *currency)
+	}
+
+	if err := query.Order("currency ASC, effective_at DESC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get exchange rates: %w", classify(err))
+	}
+
+	return models, nil
+}
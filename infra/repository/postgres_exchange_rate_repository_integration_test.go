@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupExchangeRateTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Skipf("Skipping test: failed to connect to database: %v", err)
+	}
+
+	if err := db.AutoMigrate(&repo_model.ExchangeRateModel{}); err != nil {
+		t.Skipf("Skipping test: failed to migrate database: %v", err)
+	}
+
+	return db
+}
+
+func TestPostgresExchangeRateRepository_Integration_GetEffective(t *testing.T) {
+	db := setupExchangeRateTestDB(t)
+	repo := NewPostgresExchangeRateRepository(db)
+
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	err := repo.SaveAll([]*repo_model.ExchangeRateModel{
+		{Currency: "USD", Rate: "0.92", EffectiveAt: march},
+		{Currency: "USD", Rate: "0.95", EffectiveAt: april},
+		{Currency: "BTC", Rate: "60000", EffectiveAt: march},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	rate, err := repo.GetEffective("USD", march.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rate == nil || rate.Rate != "0.92" {
+		t.Errorf("Expected March rate, got %+v", rate)
+	}
+
+	rate, _ = repo.GetEffective("USD", april)
+	if rate == nil || rate.Rate != "0.95" {
+		t.Errorf("Expected April rate from its effective time on, got %+v", rate)
+	}
+
+	rate, err = repo.GetEffective("USD", march.Add(-time.Second))
+	if err != nil || rate != nil {
+		t.Errorf("Expected no rate before the first one, got %+v, %v", rate, err)
+	}
+}
+
+func TestPostgresExchangeRateRepository_Integration_SaveAllCorrects(t *testing.T) {
+	db := setupExchangeRateTestDB(t)
+	repo := NewPostgresExchangeRateRepository(db)
+
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.SaveAll([]*repo_model.ExchangeRateModel{{Currency: "USD", Rate: "0.29", EffectiveAt: march}}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := repo.SaveAll([]*repo_model.ExchangeRateModel{{Currency: "USD", Rate: "0.92", EffectiveAt: march}}); err != nil {
+		t.Fatalf("Expected correction to succeed, got %v", err)
+	}
+
+	currency := "USD"
+	rates, err := repo.GetAll(&currency)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rates) != 1 || rates[0].Rate != "0.92" {
+		t.Errorf("Expected a single corrected rate, got %+v", rates)
+	}
+}
+
+func TestPostgresExchangeRateRepository_NilDB(t *testing.T) {
+	repo := &PostgresExchangeRateRepository{db: nil}
+
+	if err := repo.SaveAll([]*repo_model.ExchangeRateModel{{Currency: "USD"}}); err == nil {
+		t.Error("Expected error for nil database")
+	}
+	if _, err := repo.GetEffective("USD", time.Now()); err == nil {
+		t.Error("Expected error for nil database")
+	}
+	if _, err := repo.GetAll(nil); err == nil {
+		t.Error("Expected error for nil database")
+	}
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+
+	"gorm.io/gorm"
+)
+
+// outboxRelayLockKey identifies the advisory lock held by the relay that is
+// publishing, so instances started side by side do not publish twice.
+const outboxRelayLockKey = 6_581_042_913
+
+type PostgresOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresOutboxRepository(db *gorm.DB) repository.OutboxRepository {
+	return &PostgresOutboxRepository{db: db}
+}
+
+func (r *PostgresOutboxRepository) GetUnsent(limit int) ([]*repo_model.OutboxEventModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var events []*repo_model.OutboxEventModel
+	if err := r.db.Where("sent_at IS NULL").Order("id ASC").Limit(limit).Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("failed to get unsent outbox events: %w", classify(err))
+	}
+
+	return events, nil
+}
+
+func (r *PostgresOutboxRepository) MarkSent(ids []uint64, sentAt time.Time) error {
+	if r.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.db.Model(&repo_model.OutboxEventModel{}).Where("id IN ?", ids).Update("sent_at", sentAt).Error
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox events as sent: %w", classify(err))
+	}
+
+	return nil
+}
+
+func (r *PostgresOutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("database connection is nil")
+	}
+
+	result := r.db.Where("sent_at IS NOT NULL AND sent_at < ?", before).Delete(&repo_model.OutboxEventModel{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete sent outbox events: %w", classify(result.Error))
+	}
+
+	return result.RowsAffected, nil
+}
+
+// TryLockRelay takes a session lock on a connection set aside for it. The
+// lock ends with that session, so a relay that dies frees it for another
+// instance. Databases other than Postgres have a single process and always
+// get the lock.
+func (r *PostgresOutboxRepository) TryLockRelay(ctx context.Context) (repository.RelayLock, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	if r.db.Dialector.Name() != "postgres" {
+		return unsharedRelayLock{}, nil
+	}
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take the outbox relay lock: %w", classify(err))
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", outboxRelayLockKey).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to take the outbox relay lock: %w", classify(err))
+	}
+	if !locked {
+		conn.Close()
+		return nil, nil
+	}
+
+	return &advisoryRelayLock{conn: conn}, nil
+}
+
+type advisoryRelayLock struct {
+	conn *sql.Conn
+}
+
+func (l *advisoryRelayLock) Check(ctx context.Context) error {
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("lost the outbox relay lock: %w", classify(err))
+	}
+	return nil
+}
+
+func (l *advisoryRelayLock) Release() {
+	_, _ = l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", outboxRelayLockKey)
+	l.conn.Close()
+}
+
+type unsharedRelayLock struct{}
+
+func (unsharedRelayLock) Check(ctx context.Context) error { return nil }
+
+func (unsharedRelayLock) Release() {}
+
+type transactionProcessedPayload struct {
+	ID                    string  `json:"id"`
+	UserID                string  `json:"user_id"`
+	TransactionType       string  `json:"transaction_type"`
+	Amount                uint    `json:"amount"`
+	Currency              string  `json:"currency"`
+	Timestamp             string  `json:"timestamp"`
+	RoundID               string  `json:"round_id,omitempty"`
+	GameID                string  `json:"game_id,omitempty"`
+	ProviderID            string  `json:"provider_id,omitempty"`
+	OriginalTransactionID *string `json:"original_transaction_id,omitempty"`
+	Reason                string  `json:"reason,omitempty"`
+	ReportingAmount       *int64  `json:"reporting_amount,omitempty"`
+	ExchangeRate          *string `json:"exchange_rate,omitempty"`
+}
+
+// insertTransactionProcessedEvents writes a transaction.processed outbox
+// event per transaction. Events are keyed by user so that a user's events
+// keep their order on the topic.
+func insertTransactionProcessedEvents(tx *gorm.DB, transactions ...*repo_model.TransactionModel) error {
+	now := time.Now().UTC()
+	events := make([]*repo_model.OutboxEventModel, len(transactions))
+
+	for i, transaction := range transactions {
+		payload, err := json.Marshal(&transactionProcessedPayload{
+			ID:                    transaction.ID,
+			UserID:                transaction.UserID,
+			TransactionType:       transaction.TransactionType,
+			Amount:                transaction.Amount,
+			Currency:              transaction.Currency,
+			Timestamp:             transaction.Timestamp.UTC().Format(time.RFC3339Nano),
+			RoundID:               transaction.RoundID,
+			GameID:                transaction.GameID,
+			ProviderID:            transaction.ProviderID,
+			OriginalTransactionID: transaction.OriginalTransactionID,
+			Reason:                transaction.Reason,
+			ReportingAmount:       transaction.ReportingAmount,
+			ExchangeRate:          transaction.ExchangeRate,
+		})
+		if err != nil {
+			return err
+		}
+
+		events[i] = &repo_model.OutboxEventModel{
+			EventType: repo_model.TransactionProcessedEventType,
+			Key:       transaction.UserID,
+			Payload:   payload,
+			CreatedAt: now,
+		}
+	}
+
+	return tx.Create(events).Error
+}
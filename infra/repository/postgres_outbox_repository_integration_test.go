@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/utils"
+)
+
+func TestPostgresOutboxRepository_Integration(t *testing.T) {
+	db := setupTestDB(t)
+	transactionRepo := NewPostgresTransactionRepository(db)
+	outboxRepo := NewPostgresOutboxRepository(db)
+
+	userID := utils.GenerateUUID()
+	var ids []string
+	for i := 0; i < 3; i++ {
+		model := &repo_model.TransactionModel{
+			ID:              utils.GenerateUUID(),
+			UserID:          userID,
+			TransactionType: "deposit",
+			Amount:          uint(100 * (i + 1)),
+			Currency:        "EUR",
+			Timestamp:       time.Now(),
+		}
+		if err := transactionRepo.Save(model); err != nil {
+			t.Fatalf("Failed to save transaction: %v", err)
+		}
+		ids = append(ids, model.ID)
+	}
+
+	events, err := outboxRepo.GetUnsent(2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(events[0].Payload, &payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	if payload["id"] != ids[0] || payload["amount"] != float64(100) {
+		t.Errorf("Expected the first transaction first, got %v", payload)
+	}
+	if events[0].Key != userID || events[0].EventType != repo_model.TransactionProcessedEventType {
+		t.Errorf("Unexpected event %+v", events[0])
+	}
+
+	if err := outboxRepo.MarkSent([]uint64{events[0].ID, events[1].ID}, time.Now()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	events, err = outboxRepo.GetUnsent(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 unsent event, got %d", len(events))
+	}
+
+	var remaining map[string]any
+	json.Unmarshal(events[0].Payload, &remaining)
+	if remaining["id"] != ids[2] {
+		t.Errorf("Expected the third transaction to remain, got %v", remaining["id"])
+	}
+}
+
+func TestPostgresOutboxRepository_Integration_DeleteSentBefore(t *testing.T) {
+	db := setupTestDB(t)
+	transactionRepo := NewPostgresTransactionRepository(db)
+	outboxRepo := NewPostgresOutboxRepository(db)
+
+	for i := 0; i < 3; i++ {
+		err := transactionRepo.Save(&repo_model.TransactionModel{
+			ID:              utils.GenerateUUID(),
+			UserID:          utils.GenerateUUID(),
+			TransactionType: "deposit",
+			Amount:          100,
+			Currency:        "EUR",
+			Timestamp:       time.Now(),
+		})
+		if err != nil {
+			t.Fatalf("Failed to save transaction: %v", err)
+		}
+	}
+
+	events, _ := outboxRepo.GetUnsent(10)
+	now := time.Now().UTC()
+	outboxRepo.MarkSent([]uint64{events[0].ID}, now.Add(-48*time.Hour))
+	outboxRepo.MarkSent([]uint64{events[1].ID}, now)
+
+	deleted, err := outboxRepo.DeleteSentBefore(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected only the old sent event to be deleted, got %d", deleted)
+	}
+
+	var count int64
+	db.Model(&repo_model.OutboxEventModel{}).Count(&count)
+	if count != 2 {
+		t.Errorf("Expected the recent and the unsent event to remain, got %d events", count)
+	}
+}
+
+func TestPostgresOutboxRepository_Integration_SaveBatchWritesNoEvents(t *testing.T) {
+	db := setupTestDB(t)
+	transactionRepo := NewPostgresTransactionRepository(db)
+	outboxRepo := NewPostgresOutboxRepository(db)
+
+	err := transactionRepo.SaveBatch([]*repo_model.TransactionModel{{
+		ID:              utils.GenerateUUID(),
+		UserID:          utils.GenerateUUID(),
+		TransactionType: "deposit",
+		Amount:          100,
+		Currency:        "EUR",
+		Timestamp:       time.Now(),
+	}})
+	if err != nil {
+		t.Fatalf("Failed to save batch: %v", err)
+	}
+
+	events, err := outboxRepo.GetUnsent(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("Expected no events for imported transactions, got %d", len(events))
+	}
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostgresOutboxRepository_TryLockRelay(t *testing.T) {
+	db, mock, cleanup := setupMockTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresOutboxRepository(db)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(outboxRelayLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(outboxRelayLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	lock, err := repo.TryLockRelay(context.Background())
+	if err != nil || lock == nil {
+		t.Fatalf("Expected the free lock to be taken, got %v, %v", lock, err)
+	}
+	lock.Release()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestPostgresOutboxRepository_TryLockRelay_HeldElsewhere(t *testing.T) {
+	db, mock, cleanup := setupMockTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresOutboxRepository(db)
+
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(outboxRelayLockKey).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	lock, err := repo.TryLockRelay(context.Background())
+	if err != nil || lock != nil {
+		t.Errorf("Expected no lock while another relay holds it, got %v, %v", lock, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %s", err)
+	}
+}
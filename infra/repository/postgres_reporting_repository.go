@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+
+	"gorm.io/gorm"
+)
+
+// ggrGroupColumns maps the supported groupings to the expression used as the
+// group key. Days are cast to text so that every driver scans them the same way.
+var ggrGroupColumns = map[string]string{
+	"day":  "CAST(DATE(t.timestamp) AS TEXT)",
+	"user": "t.user_id",
+	"game": "t.game_id",
+}
+
+// netBets and netWins sum reporting currency amounts. Rollbacks are netted
+// against the type of the transaction they reverse and refunds against the bet
+// they return, so voided play does not count.
+const (
+	netBets = `COALESCE(SUM(CASE
+		WHEN t.transaction_type = 'bet' THEN t.reporting_amount
+		WHEN t.transaction_type IN ('refund', 'rollback') AND o.transaction_type = 'bet' THEN -t.reporting_amount
+		ELSE 0 END), 0)`
+	netWins = `COALESCE(SUM(CASE
+		WHEN t.transaction_type = 'win' THEN t.reporting_amount
+		WHEN t.transaction_type = 'rollback' AND o.transaction_type = 'win' THEN -t.reporting_amount
+		ELSE 0 END), 0)`
+	netBetCount = `COALESCE(SUM(CASE
+		WHEN t.transaction_type = 'bet' THEN 1
+		WHEN t.transaction_type = 'rollback' AND o.transaction_type = 'bet' THEN -1
+		ELSE 0 END), 0)`
+	netWinCount = `COALESCE(SUM(CASE
+		WHEN t.transaction_type = 'win' THEN 1
+		WHEN t.transaction_type = 'rollback' AND o.transaction_type = 'win' THEN -1
+		ELSE 0 END), 0)`
+	unconvertedCount = `COALESCE(SUM(CASE WHEN t.reporting_amount IS NULL THEN 1 ELSE 0 END), 0)`
+)
+
+var ggrSelect = netBets + ` AS total_bets, ` +
+	netWins + ` AS total_wins, ` +
+	netBetCount + ` AS bet_count, ` +
+	`COUNT(DISTINCT CASE WHEN t.transaction_type = 'bet' THEN t.user_id END) AS unique_players, ` +
+	unconvertedCount + ` AS unconverted_count`
+
+// dailyGGRSelect mirrors ggrSelect over daily_user_aggregates.
+const dailyGGRSelect = `COALESCE(SUM(CASE
+		WHEN a.transaction_type = 'bet' THEN a.reporting_amount_sum
+		WHEN a.transaction_type IN ('refund', 'rollback') AND a.original_transaction_type = 'bet' THEN -a.reporting_amount_sum
+		ELSE 0 END), 0) AS total_bets,
+	COALESCE(SUM(CASE
+		WHEN a.transaction_type = 'win' THEN a.reporting_amount_sum
+		WHEN a.transaction_type = 'rollback' AND a.original_transaction_type = 'win' THEN -a.reporting_amount_sum
+		ELSE 0 END), 0) AS total_wins,
+	COALESCE(SUM(CASE
+		WHEN a.transaction_type = 'bet' THEN a.count
+		WHEN a.transaction_type = 'rollback' AND a.original_transaction_type = 'bet' THEN -a.count
+		ELSE 0 END), 0) AS bet_count,
+	COUNT(DISTINCT CASE WHEN a.transaction_type = 'bet' THEN a.user_id END) AS unique_players,
+	COALESCE(SUM(a.unconverted_count), 0) AS unconverted_count`
+
+var dailyGGRGroupColumns = map[string]string{
+	"day":  "CAST(DATE(a.day) AS TEXT)",
+	"user": "a.user_id",
+}
+
+var userStatsSelect = netBets + ` AS total_bets, ` +
+	netWins + ` AS total_wins, ` +
+	netBetCount + ` AS bet_count, ` +
+	netWinCount + ` AS win_count, ` +
+	`COUNT(*) AS transaction_count, ` +
+	`COALESCE(MAX(CASE WHEN t.transaction_type = 'win' THEN t.reporting_amount END), 0) AS biggest_win, ` +
+	unconvertedCount + ` AS unconverted_count`
+
+// dailyAggregateRow is a daily_user_aggregates row as computed from the raw
+// transactions, with the day still in its textual form.
+type dailyAggregateRow struct {
+	Day                     string
+	UserID                  string
+	Currency                string
+	TransactionType         string
+	OriginalTransactionType string
+	AmountSum               int64
+	ReportingAmountSum      int64
+	Count                   int64
+	UnconvertedCount        int64
+}
+
+type PostgresReportingRepository struct {
+	db *gorm.DB
+}
+
+func NewPostgresReportingRepository(db *gorm.DB) repository.ReportingRepository {
+	return &PostgresReportingRepository{db: db}
+}
+
+func (r *PostgresReportingRepository) GetGGR(from, to time.Time, groupBy string) ([]*repo_model.GGRRowModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	groupColumn := "''"
+	if groupBy != "" {
+		column, ok := ggrGroupColumns[groupBy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported grouping %q", groupBy)
+		}
+		groupColumn = column
+	}
+
+	query := r.db.Table("transactions AS t").
+		Select(groupColumn+" AS group_key, "+ggrSelect).
+		Joins("LEFT JOIN transactions AS o ON o.id = t.original_transaction_id").
+		Where("t.timestamp >= ? AND t.timestamp < ?", from, to).
+		Where("t.transaction_type IN ?", []string{"bet", "win", "refund", "rollback"})
+
+	if groupBy != "" {
+		query = query.Group(groupColumn).Order("group_key ASC")
+	}
+
+	var rows []*repo_model.GGRRowModel
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get ggr report: %w", classify(err))
+	}
+
+	return rows, nil
+}
+
+// GetDailyGGR is GetGGR over daily_user_aggregates. from and to are expected to
+// fall on UTC day boundaries; grouping by game is not supported.
+func (r *PostgresReportingRepository) GetDailyGGR(from, to time.Time, groupBy string) ([]*repo_model.GGRRowModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	groupColumn := "''"
+	if groupBy != "" {
+		column, ok := dailyGGRGroupColumns[groupBy]
+		if !ok {
+			return nil, fmt.Errorf("unsupported grouping %q", groupBy)
+		}
+		groupColumn = column
+	}
+
+	query := r.db.Table("daily_user_aggregates AS a").
+		Select(groupColumn+" AS group_key, "+dailyGGRSelect).
+		Where("a.day >= ? AND a.day < ?", from, to).
+		Where("a.transaction_type IN ?", []string{"bet", "win", "refund", "rollback"})
+
+	if groupBy != "" {
+		query = query.Group(groupColumn).Order("group_key ASC")
+	}
+
+	var rows []*repo_model.GGRRowModel
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get daily ggr report: %w", classify(err))
+	}
+
+	return rows, nil
+}
+
+// RebuildDailyAggregates recomputes daily_user_aggregates for the days in
+// [from, to) from the raw transactions and returns the number of aggregate
+// rows written.
+func (r *PostgresReportingRepository) RebuildDailyAggregates(from, to time.Time) (int64, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("database connection is nil")
+	}
+
+	var written int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("day >= ? AND day < ?", from, to).
+			Delete(&repo_model.DailyUserAggregateModel{}).Error
+		if err != nil {
+			return err
+		}
+
+		var rows []*dailyAggregateRow
+		err = tx.Table("transactions AS t").
+			Select(`CAST(DATE(t.timestamp) AS TEXT) AS day,
+				t.user_id AS user_id,
+				t.currency AS currency,
+				t.transaction_type AS transaction_type,
+				COALESCE(o.transaction_type, '') AS original_transaction_type,
+				SUM(t.amount) AS amount_sum,
+				COALESCE(SUM(t.reporting_amount), 0) AS reporting_amount_sum,
+				COUNT(*) AS count,
+				`+unconvertedCount+` AS unconverted_count`).
+			Joins("LEFT JOIN transactions AS o ON o.id = t.original_transaction_id").
+			Where("t.timestamp >= ? AND t.timestamp < ?", from, to).
+			Group("DATE(t.timestamp), t.user_id, t.currency, t.transaction_type, o.transaction_type").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			return nil
+		}
+
+		aggregates := make([]*repo_model.DailyUserAggregateModel, len(rows))
+		for i, row := range rows {
+			day, err := time.Parse(time.DateOnly, row.Day)
+			if err != nil {
+				return fmt.Errorf("unexpected aggregate day %q: %w", row.Day, err)
+			}
+			aggregates[i] = &repo_model.DailyUserAggregateModel{
+				Day:                     day,
+				UserID:                  row.UserID,
+				Currency:                row.Currency,
+				TransactionType:         row.TransactionType,
+				OriginalTransactionType: row.OriginalTransactionType,
+				AmountSum:               row.AmountSum,
+				ReportingAmountSum:      row.ReportingAmountSum,
+				Count:                   row.Count,
+				UnconvertedCount:        row.UnconvertedCount,
+			}
+		}
+
+		if err := tx.CreateInBatches(aggregates, 500).Error; err != nil {
+			return err
+		}
+		written = int64(len(aggregates))
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild daily aggregates: %w", classify(err))
+	}
+
+	return written, nil
+}
+
+// GetUserStats aggregates all transactions of a user at or after since. A nil
+// since covers the whole history.
+func (r *PostgresReportingRepository) GetUserStats(userID string, since *time.Time) (*repo_model.UserStatsModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	query := r.db.Table("transactions AS t").
+		Joins("LEFT JOIN transactions AS o ON o.id = t.original_transaction_id").
+		Where("t.user_id = ?", userID)
+
+	if since != nil {
+		query = query.Where("t.timestamp >= ?", *since)
+	}
+
+	stats := &repo_model.UserStatsModel{}
+	if err := query.Session(&gorm.Session{}).Select(userStatsSelect).Scan(stats).Error; err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", classify(err))
+	}
+
+	if stats.TransactionCount == 0 {
+		return stats, nil
+	}
+
+	var first, last repo_model.TransactionModel
+	if err := query.Session(&gorm.Session{}).Select("t.timestamp").Order("t.timestamp ASC").Limit(1).Scan(&first).Error; err != nil {
+		return nil, fmt.Errorf("failed to get first activity: %w", classify(err))
+	}
+	if err := query.Session(&gorm.Session{}).Select("t.timestamp").Order("t.timestamp DESC").Limit(1).Scan(&last).Error; err != nil {
+		return nil, fmt.Errorf("failed to get last activity: %w", classify(err))
+	}
+	stats.FirstActivity = &first.Timestamp
+	stats.LastActivity = &last.Timestamp
+
+	return stats, nil
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/utils"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func seedGGRTransactions(t *testing.T, repo repository.TransactionRepository, day time.Time) {
+	betID := utils.GenerateUUID()
+	voidedBetID := utils.GenerateUUID()
+	winID := utils.GenerateUUID()
+	original := func(id string) *string { return &id }
+
+	models := []*repo_model.TransactionModel{
+		{ID: betID, UserID: "user-1", TransactionType: "bet", Amount: 1000, Currency: "EUR", GameID: "slots", ReportingAmount: int64Ptr(1000), Timestamp: day.Add(time.Hour)},
+		{ID: winID, UserID: "user-1", TransactionType: "win", Amount: 400, Currency: "EUR", GameID: "slots", ReportingAmount: int64Ptr(400), Timestamp: day.Add(2 * time.Hour)},
+		{ID: voidedBetID, UserID: "user-2", TransactionType: "bet", Amount: 500, Currency: "EUR", GameID: "roulette", ReportingAmount: int64Ptr(500), Timestamp: day.Add(3 * time.Hour)},
+		{ID: utils.GenerateUUID(), UserID: "user-2", TransactionType: "rollback", Amount: 500, Currency: "EUR", GameID: "roulette", OriginalTransactionID: original(voidedBetID), ReportingAmount: int64Ptr(500), Timestamp: day.Add(4 * time.Hour)},
+		{ID: utils.GenerateUUID(), UserID: "user-3", TransactionType: "bet", Amount: 2000, Currency: "USD", GameID: "slots", Timestamp: day.Add(25 * time.Hour)},
+		{ID: utils.GenerateUUID(), UserID: "user-3", TransactionType: "bet", Amount: 300, Currency: "EUR", GameID: "slots", ReportingAmount: int64Ptr(300), Timestamp: day.Add(26 * time.Hour)},
+		{ID: utils.GenerateUUID(), UserID: "user-3", TransactionType: "deposit", Amount: 9000, Currency: "EUR", ReportingAmount: int64Ptr(9000), Timestamp: day.Add(27 * time.Hour)},
+	}
+
+	for _, model := range models {
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestPostgresReportingRepository_Integration_GetGGR(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedGGRTransactions(t, NewPostgresTransactionRepository(db), day)
+	repo := NewPostgresReportingRepository(db)
+
+	rows, err := repo.GetGGR(day, day.Add(48*time.Hour), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("Expected a single total row, got %d", len(rows))
+	}
+
+	total := rows[0]
+	if total.TotalBets != 1300 || total.TotalWins != 400 {
+		t.Errorf("Expected bets 1300 and wins 400, got %+v", total)
+	}
+	if total.BetCount != 3 {
+		t.Errorf("Expected the voided bet to be excluded from the count, got %d", total.BetCount)
+	}
+	if total.UniquePlayers != 3 {
+		t.Errorf("Expected 3 unique players, got %d", total.UniquePlayers)
+	}
+	if total.UnconvertedCount != 1 {
+		t.Errorf("Expected 1 unconverted transaction, got %d", total.UnconvertedCount)
+	}
+}
+
+func TestPostgresReportingRepository_Integration_GetGGRGrouped(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedGGRTransactions(t, NewPostgresTransactionRepository(db), day)
+	repo := NewPostgresReportingRepository(db)
+
+	rows, err := repo.GetGGR(day, day.Add(48*time.Hour), "day")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(rows))
+	}
+	if rows[0].GroupKey != "2026-03-01" || rows[0].TotalBets != 1000 || rows[0].TotalWins != 400 {
+		t.Errorf("Unexpected first day: %+v", rows[0])
+	}
+	if rows[1].GroupKey != "2026-03-02" || rows[1].TotalBets != 300 || rows[1].BetCount != 2 {
+		t.Errorf("Unexpected second day: %+v", rows[1])
+	}
+
+	rows, err = repo.GetGGR(day, day.Add(24*time.Hour), "game")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 2 || rows[0].GroupKey != "roulette" || rows[1].GroupKey != "slots" {
+		t.Fatalf("Expected roulette and slots, got %+v", rows)
+	}
+	if rows[0].TotalBets != 0 || rows[0].BetCount != 0 {
+		t.Errorf("Expected the voided roulette bet to net out, got %+v", rows[0])
+	}
+
+	rows, err = repo.GetGGR(day, day.Add(48*time.Hour), "user")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rows) != 3 || rows[0].GroupKey != "user-1" {
+		t.Errorf("Expected one row per user, got %+v", rows)
+	}
+}
+
+func TestPostgresReportingRepository_GetGGR_UnsupportedGrouping(t *testing.T) {
+	repo := NewPostgresReportingRepository(setupTestDB(t))
+
+	if _, err := repo.GetGGR(time.Time{}, time.Now(), "month"); err == nil {
+		t.Error("Expected error for unsupported grouping")
+	}
+}
+
+func TestPostgresReportingRepository_Integration_GetUserStats(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedGGRTransactions(t, NewPostgresTransactionRepository(db), day)
+	repo := NewPostgresReportingRepository(db)
+
+	stats, err := repo.GetUserStats("user-3", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TransactionCount != 3 || stats.BetCount != 2 || stats.TotalBets != 300 {
+		t.Errorf("Unexpected lifetime stats %+v", stats)
+	}
+	if stats.UnconvertedCount != 1 {
+		t.Errorf("Expected 1 unconverted transaction, got %d", stats.UnconvertedCount)
+	}
+	if stats.FirstActivity == nil || !stats.FirstActivity.Equal(day.Add(25*time.Hour)) {
+		t.Errorf("Unexpected first activity %v", stats.FirstActivity)
+	}
+	if stats.LastActivity == nil || !stats.LastActivity.Equal(day.Add(27*time.Hour)) {
+		t.Errorf("Unexpected last activity %v", stats.LastActivity)
+	}
+
+	stats, err = repo.GetUserStats("user-1", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TotalWins != 400 || stats.WinCount != 1 || stats.BiggestWin != 400 {
+		t.Errorf("Unexpected win stats %+v", stats)
+	}
+
+	stats, err = repo.GetUserStats("user-2", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TotalBets != 0 || stats.BetCount != 0 || stats.TransactionCount != 2 {
+		t.Errorf("Expected the rolled back bet to net out, got %+v", stats)
+	}
+
+	since := day.Add(26 * time.Hour)
+	stats, err = repo.GetUserStats("user-3", &since)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TransactionCount != 2 || stats.UnconvertedCount != 0 {
+		t.Errorf("Unexpected windowed stats %+v", stats)
+	}
+}
+
+func TestPostgresReportingRepository_Integration_GetUserStatsUnknownUser(t *testing.T) {
+	repo := NewPostgresReportingRepository(setupTestDB(t))
+
+	stats, err := repo.GetUserStats("nobody", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stats.TransactionCount != 0 || stats.FirstActivity != nil || stats.LastActivity != nil {
+		t.Errorf("Expected empty stats, got %+v", stats)
+	}
+}
+
+func TestPostgresReportingRepository_Integration_GetDailyGGRMatchesRaw(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedGGRTransactions(t, NewPostgresTransactionRepository(db), day)
+	repo := NewPostgresReportingRepository(db)
+
+	for _, groupBy := range []string{"", "day", "user"} {
+		raw, err := repo.GetGGR(day, day.Add(48*time.Hour), groupBy)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		daily, err := repo.GetDailyGGR(day, day.Add(48*time.Hour), groupBy)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(raw) != len(daily) {
+			t.Fatalf("Expected %d rows for grouping %q, got %d", len(raw), groupBy, len(daily))
+		}
+		for i := range raw {
+			if *raw[i] != *daily[i] {
+				t.Errorf("Grouping %q row %d: raw %+v, daily %+v", groupBy, i, raw[i], daily[i])
+			}
+		}
+	}
+
+	if _, err := repo.GetDailyGGR(day, day.Add(24*time.Hour), "game"); err == nil {
+		t.Error("Expected error for grouping by game")
+	}
+}
+
+func TestPostgresReportingRepository_Integration_RebuildDailyAggregates(t *testing.T) {
+	db := setupTestDB(t)
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	seedGGRTransactions(t, NewPostgresTransactionRepository(db), day)
+	repo := NewPostgresReportingRepository(db)
+
+	var maintained []*repo_model.DailyUserAggregateModel
+	if err := db.Order("day, user_id, transaction_type").Find(&maintained).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := db.Where("1 = 1").Delete(&repo_model.DailyUserAggregateModel{}).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	written, err := repo.RebuildDailyAggregates(day, day.Add(24*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if written != 4 {
+		t.Errorf("Expected 4 aggregate rows for the first day, got %d", written)
+	}
+
+	written, err = repo.RebuildDailyAggregates(day, day.Add(48*time.Hour))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if written != 7 {
+		t.Errorf("Expected 7 aggregate rows after rebuilding both days, got %d", written)
+	}
+
+	var rebuilt []*repo_model.DailyUserAggregateModel
+	if err := db.Order("day, user_id, transaction_type").Find(&rebuilt).Error; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(rebuilt) != len(maintained) {
+		t.Fatalf("Expected %d aggregates, got %d", len(maintained), len(rebuilt))
+	}
+	for i := range maintained {
+		if !maintained[i].Day.Equal(rebuilt[i].Day) {
+			t.Errorf("Row %d: expected day %s, got %s", i, maintained[i].Day, rebuilt[i].Day)
+		}
+		maintained[i].Day, rebuilt[i].Day = time.Time{}, time.Time{}
+		if *maintained[i] != *rebuilt[i] {
+			t.Errorf("Row %d: maintained %+v, rebuilt %+v", i, maintained[i], rebuilt[i])
+		}
+	}
+
+	var rollback repo_model.DailyUserAggregateModel
+	if err := db.Where("transaction_type = ?", "rollback").First(&rollback).Error; err != nil {
+		t.Fatalf("Expected rollback aggregate, got %v", err)
+	}
+	if rollback.OriginalTransactionType != "bet" || rollback.ReportingAmountSum != 500 {
+		t.Errorf("Unexpected rollback aggregate %+v", rollback)
+	}
+}
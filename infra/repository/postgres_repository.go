@@ -1,14 +1,21 @@
 package repository
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"casino/boundary/repo_model"
 	"casino/boundary/repository"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// userLockClass is the first key ("user") of the pg advisory locks taken per
+// user by Save and SaveChecked; the second is a hash of the user id.
+const userLockClass = 0x75736572
+
 type PostgresTransactionRepository struct {
 	db *gorm.DB
 }
@@ -26,13 +33,197 @@ func (r *PostgresTransactionRepository) Save(transaction *repo_model.Transaction
 		return fmt.Errorf("database connection is nil")
 	}
 
-	if err := r.db.Create(transaction).Error; err != nil {
-		return fmt.Errorf("failed to save transaction: %w", err)
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, transaction.UserID); err != nil {
+			return err
+		}
+		return saveTransaction(tx, transaction)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save transaction: %w", classify(err))
+	}
+
+	return nil
+}
+
+func (r *PostgresTransactionRepository) SaveChecked(transaction *repo_model.TransactionModel, check func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error) error {
+	if transaction == nil {
+		return fmt.Errorf("transaction cannot be nil")
+	}
+
+	if r.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	var checkErr error
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUser(tx, transaction.UserID); err != nil {
+			return err
+		}
+
+		totals, err := transactionTotals(tx, transaction.UserID)
+		if err != nil {
+			return err
+		}
+		linked, err := linkedTransactions(tx, transaction)
+		if err != nil {
+			return err
+		}
+		if checkErr = check(totals, linked); checkErr != nil {
+			return checkErr
+		}
+		return saveTransaction(tx, transaction)
+	})
+	if checkErr != nil {
+		return checkErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save transaction: %w", classify(err))
 	}
 
 	return nil
 }
 
+// linkedTransactions returns the transactions that reference the same
+// original as transaction, oldest first.
+func linkedTransactions(tx *gorm.DB, transaction *repo_model.TransactionModel) ([]*repo_model.TransactionModel, error) {
+	if transaction.OriginalTransactionID == nil {
+		return nil, nil
+	}
+
+	var models []*repo_model.TransactionModel
+	if err := tx.Where("original_transaction_id = ?", *transaction.OriginalTransactionID).Order("timestamp ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// lockUser serialises the transactions saving rows for a user until they
+// end. Besides guarding checked saves, it makes a user's outbox events take
+// their ids in commit order, so the relay, which follows ids, never publishes
+// a user's events out of order.
+func lockUser(tx *gorm.DB, userID string) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", userLockClass, userID).Error; err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+// saveTransaction inserts a transaction with its aggregates and outbox event.
+func saveTransaction(tx *gorm.DB, transaction *repo_model.TransactionModel) error {
+	if err := tx.Create(transaction).Error; err != nil {
+		return err
+	}
+	if err := upsertDailyAggregates(tx, transaction); err != nil {
+		return err
+	}
+	return insertTransactionProcessedEvents(tx, transaction)
+}
+
+// SaveBatch stores transactions in a single database transaction, so either
+// all of them are saved or none is. Unlike Save it writes no outbox events:
+// batches are historical imports that downstream systems must not react to.
+func (r *PostgresTransactionRepository) SaveBatch(transactions []*repo_model.TransactionModel) error {
+	if r.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(transactions, 500).Error; err != nil {
+			return err
+		}
+		return upsertDailyAggregates(tx, transactions...)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save transactions: %w", classify(err))
+	}
+
+	return nil
+}
+
+// upsertDailyAggregates adds freshly saved transactions to their daily user
+// aggregates. It runs in the transaction that saved the rows so that the
+// aggregates never drift from the raw data.
+func upsertDailyAggregates(tx *gorm.DB, transactions ...*repo_model.TransactionModel) error {
+	type aggregateKey struct {
+		day                     time.Time
+		userID                  string
+		currency                string
+		transactionType         string
+		originalTransactionType string
+	}
+
+	var aggregates []*repo_model.DailyUserAggregateModel
+	byKey := make(map[aggregateKey]*repo_model.DailyUserAggregateModel)
+
+	for _, transaction := range transactions {
+		key := aggregateKey{
+			day:             repo_model.AggregateDay(transaction.Timestamp),
+			userID:          transaction.UserID,
+			currency:        transaction.Currency,
+			transactionType: transaction.TransactionType,
+		}
+
+		if transaction.OriginalTransactionID != nil {
+			var originalTypes []string
+			err := tx.Model(&repo_model.TransactionModel{}).
+				Where("id = ?", *transaction.OriginalTransactionID).
+				Pluck("transaction_type", &originalTypes).Error
+			if err != nil {
+				return err
+			}
+			if len(originalTypes) > 0 {
+				key.originalTransactionType = originalTypes[0]
+			}
+		}
+
+		aggregate, ok := byKey[key]
+		if !ok {
+			aggregate = &repo_model.DailyUserAggregateModel{
+				Day:                     key.day,
+				UserID:                  key.userID,
+				Currency:                key.currency,
+				TransactionType:         key.transactionType,
+				OriginalTransactionType: key.originalTransactionType,
+			}
+			byKey[key] = aggregate
+			aggregates = append(aggregates, aggregate)
+		}
+
+		aggregate.AmountSum += int64(transaction.Amount)
+		aggregate.Count++
+		if transaction.ReportingAmount != nil {
+			aggregate.ReportingAmountSum += *transaction.ReportingAmount
+		} else {
+			aggregate.UnconvertedCount++
+		}
+	}
+
+	if len(aggregates) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "day"}, {Name: "user_id"}, {Name: "currency"},
+			{Name: "transaction_type"}, {Name: "original_transaction_type"},
+		},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"amount_sum":           gorm.Expr("daily_user_aggregates.amount_sum + excluded.amount_sum"),
+			"reporting_amount_sum": gorm.Expr("daily_user_aggregates.reporting_amount_sum + excluded.reporting_amount_sum"),
+			"count":                gorm.Expr("daily_user_aggregates.count + excluded.count"),
+			"unconverted_count":    gorm.Expr("daily_user_aggregates.unconverted_count + excluded.unconverted_count"),
+		}),
+	}).Create(&aggregates).Error
+}
+
 func (r *PostgresTransactionRepository) GetByID(id string) (*repo_model.TransactionModel, error) {
 	if r.db == nil {
 		return nil, fmt.Errorf("database connection is nil")
@@ -40,16 +231,13 @@ func (r *PostgresTransactionRepository) GetByID(id string) (*repo_model.Transact
 
 	var model repo_model.TransactionModel
 	if err := r.db.Where("id = ?", id).First(&model).Error; err != nil {
-		if err.Error() == "record not found" {
-			return nil, nil
-		}
-		return nil, err
+		return nil, classify(err)
 	}
 
 	return &model, nil
 }
 
-func (r *PostgresTransactionRepository) GetByUserID(userID string, transactionType *string) ([]*repo_model.TransactionModel, error) {
+func (r *PostgresTransactionRepository) GetByUserID(userID string, transactionType, currency *string) ([]*repo_model.TransactionModel, error) {
 	if r.db == nil {
 		return nil, fmt.Errorf("database connection is nil")
 	}
@@ -61,14 +249,18 @@ func (r *PostgresTransactionRepository) GetByUserID(userID string, transactionTy
 		query = query.Where("transaction_type = ?", *transactionType)
 	}
 
+	if currency != nil {
+		query = query.Where("currency = ?", *currency)
+	}
+
 	if err := query.Order("timestamp DESC").Find(&models).Error; err != nil {
-		return nil, fmt.Errorf("failed to get transactions by user_id: %w", err)
+		return nil, fmt.Errorf("failed to get transactions by user_id: %w", classify(err))
 	}
 
 	return models, nil
 }
 
-func (r *PostgresTransactionRepository) GetAll(transactionType *string) ([]*repo_model.TransactionModel, error) {
+func (r *PostgresTransactionRepository) GetAll(transactionType, currency *string) ([]*repo_model.TransactionModel, error) {
 	if r.db == nil {
 		return nil, fmt.Errorf("database connection is nil")
 	}
@@ -80,9 +272,105 @@ func (r *PostgresTransactionRepository) GetAll(transactionType *string) ([]*repo
 		query = query.Where("transaction_type = ?", *transactionType)
 	}
 
+	if currency != nil {
+		query = query.Where("currency = ?", *currency)
+	}
+
 	if err := query.Order("timestamp DESC").Find(&models).Error; err != nil {
-		return nil, fmt.Errorf("failed to get all transactions: %w", err)
+		return nil, fmt.Errorf("failed to get all transactions: %w", classify(err))
+	}
+
+	return models, nil
+}
+
+func (r *PostgresTransactionRepository) GetByRoundID(roundID string) ([]*repo_model.TransactionModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var models []*repo_model.TransactionModel
+	if err := r.db.Where("round_id = ?", roundID).Order("timestamp ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transactions by round_id: %w", classify(err))
 	}
 
 	return models, nil
 }
+
+func (r *PostgresTransactionRepository) GetTotals(userID string) ([]*repo_model.TransactionTotalModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	totals, err := transactionTotals(r.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction totals: %w", classify(err))
+	}
+
+	return totals, nil
+}
+
+func transactionTotals(db *gorm.DB, userID string) ([]*repo_model.TransactionTotalModel, error) {
+	var totals []*repo_model.TransactionTotalModel
+	err := db.Table("transactions AS t").
+		Select("t.currency AS currency, t.transaction_type AS transaction_type, COALESCE(o.transaction_type, '') AS original_transaction_type, SUM(t.amount) AS total, COUNT(*) AS count").
+		Joins("LEFT JOIN transactions AS o ON o.id = t.original_transaction_id").
+		Where("t.user_id = ?", userID).
+		Group("t.currency, t.transaction_type, o.transaction_type").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *PostgresTransactionRepository) GetByOriginalID(originalID string) ([]*repo_model.TransactionModel, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("database connection is nil")
+	}
+
+	var models []*repo_model.TransactionModel
+	if err := r.db.Where("original_transaction_id = ?", originalID).Order("timestamp ASC").Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("failed to get transactions by original_transaction_id: %w", classify(err))
+	}
+
+	return models, nil
+}
+
+func (r *PostgresTransactionRepository) Stream(ctx context.Context, userID, transactionType, currency *string, fn func(*repo_model.TransactionModel) error) error {
+	if r.db == nil {
+		return fmt.Errorf("database connection is nil")
+	}
+
+	query := r.db.WithContext(ctx).Model(&repo_model.TransactionModel{})
+
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	if transactionType != nil {
+		query = query.Where("transaction_type = ?", *transactionType)
+	}
+
+	if currency != nil {
+		query = query.Where("currency = ?", *currency)
+	}
+
+	rows, err := query.Order("timestamp ASC, id ASC").Rows()
+	if err != nil {
+		return fmt.Errorf("failed to stream transactions: %w", classify(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var model repo_model.TransactionModel
+		if err := r.db.ScanRows(rows, &model); err != nil {
+			return fmt.Errorf("failed to scan transaction: %w", classify(err))
+		}
+		if err := fn(&model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to stream transactions: %w", classify(err))
+	}
+
+	return nil
+}
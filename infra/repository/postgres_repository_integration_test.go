@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -12,12 +14,12 @@ import (
 )
 
 func setupTestDB(t *testing.T) *gorm.DB {
-	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{TranslateError: true})
 	if err != nil {
 		t.Skipf("Skipping test: failed to connect to database: %v", err)
 	}
 
-	if err := db.AutoMigrate(&repo_model.TransactionModel{}); err != nil {
+	if err := db.AutoMigrate(&repo_model.TransactionModel{}, &repo_model.DailyUserAggregateModel{}, &repo_model.OutboxEventModel{}); err != nil {
 		t.Skipf("Skipping test: failed to migrate database: %v", err)
 	}
 
@@ -86,7 +88,7 @@ func TestPostgresTransactionRepository_Integration_GetByUserID(t *testing.T) {
 	repo.Save(model1)
 	repo.Save(model2)
 
-	models, err := repo.GetByUserID(userID, nil)
+	models, err := repo.GetByUserID(userID, nil, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -96,7 +98,7 @@ func TestPostgresTransactionRepository_Integration_GetByUserID(t *testing.T) {
 	}
 
 	transactionType := "bet"
-	models, err = repo.GetByUserID(userID, &transactionType)
+	models, err = repo.GetByUserID(userID, &transactionType, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -140,7 +142,7 @@ func TestPostgresTransactionRepository_Integration_GetAll(t *testing.T) {
 	repo.Save(model2)
 	repo.Save(model3)
 
-	models, err := repo.GetAll(nil)
+	models, err := repo.GetAll(nil, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -150,7 +152,7 @@ func TestPostgresTransactionRepository_Integration_GetAll(t *testing.T) {
 	}
 
 	transactionType := "win"
-	models, err = repo.GetAll(&transactionType)
+	models, err = repo.GetAll(&transactionType, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -163,3 +165,333 @@ func TestPostgresTransactionRepository_Integration_GetAll(t *testing.T) {
 		t.Errorf("Expected transaction type 'win', got %s", models[0].TransactionType)
 	}
 }
+
+func TestPostgresTransactionRepository_Integration_GetByRoundID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	userID := utils.GenerateUUID()
+	bet := &repo_model.TransactionModel{
+		ID:              utils.GenerateUUID(),
+		UserID:          userID,
+		TransactionType: "bet",
+		Amount:          100,
+		Timestamp:       time.Now().Add(-time.Minute),
+		RoundID:         "round-1",
+		GameID:          "starburst",
+		ProviderID:      "netent",
+	}
+	win := &repo_model.TransactionModel{
+		ID:              utils.GenerateUUID(),
+		UserID:          userID,
+		TransactionType: "win",
+		Amount:          300,
+		Timestamp:       time.Now(),
+		RoundID:         "round-1",
+		GameID:          "starburst",
+		ProviderID:      "netent",
+	}
+	other := &repo_model.TransactionModel{
+		ID:              utils.GenerateUUID(),
+		UserID:          userID,
+		TransactionType: "bet",
+		Amount:          50,
+		Timestamp:       time.Now(),
+		RoundID:         "round-2",
+	}
+
+	repo.Save(win)
+	repo.Save(bet)
+	repo.Save(other)
+
+	models, err := repo.GetByRoundID("round-1")
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(models))
+	}
+
+	if models[0].ID != bet.ID || models[1].ID != win.ID {
+		t.Error("Expected round transactions ordered by timestamp ascending")
+	}
+
+	if models[0].GameID != "starburst" || models[0].ProviderID != "netent" {
+		t.Errorf("Expected game and provider to be stored, got %s/%s", models[0].GameID, models[0].ProviderID)
+	}
+}
+
+func TestPostgresTransactionRepository_Integration_GetTotals(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	userID := utils.GenerateUUID()
+	bet := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 100, Timestamp: time.Now()}
+	secondBet := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 50, Timestamp: time.Now()}
+	deposit := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "deposit", Amount: 1000, Timestamp: time.Now()}
+	rollback := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "rollback", Amount: 50, Timestamp: time.Now(), OriginalTransactionID: &secondBet.ID}
+	otherUser := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: utils.GenerateUUID(), TransactionType: "deposit", Amount: 999, Timestamp: time.Now()}
+
+	for _, model := range []*repo_model.TransactionModel{bet, secondBet, deposit, rollback, otherUser} {
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Expected no error saving, got %v", err)
+		}
+	}
+
+	totals, err := repo.GetTotals(userID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	byType := map[string]*repo_model.TransactionTotalModel{}
+	for _, total := range totals {
+		byType[total.TransactionType] = total
+	}
+
+	if len(byType) != 3 {
+		t.Fatalf("Expected 3 transaction types, got %d", len(byType))
+	}
+
+	if byType["bet"].Total != 150 || byType["bet"].Count != 2 {
+		t.Errorf("Expected bet total 150 over 2 rows, got %d over %d", byType["bet"].Total, byType["bet"].Count)
+	}
+
+	if byType["deposit"].Total != 1000 {
+		t.Errorf("Expected deposit total 1000, got %d", byType["deposit"].Total)
+	}
+
+	if byType["rollback"].OriginalTransactionType != "bet" {
+		t.Errorf("Expected rollback to carry original type bet, got %s", byType["rollback"].OriginalTransactionType)
+	}
+}
+
+func TestPostgresTransactionRepository_Integration_GetByOriginalID(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	userID := utils.GenerateUUID()
+	bet := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 100, Timestamp: time.Now()}
+	refund := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "refund", Amount: 40, Timestamp: time.Now(), OriginalTransactionID: &bet.ID}
+	rollback := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "rollback", Amount: 100, Timestamp: time.Now().Add(time.Second), OriginalTransactionID: &bet.ID, Reason: "provider outage"}
+
+	for _, model := range []*repo_model.TransactionModel{bet, refund, rollback} {
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Expected no error saving, got %v", err)
+		}
+	}
+
+	models, err := repo.GetByOriginalID(bet.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(models) != 2 {
+		t.Fatalf("Expected 2 linked transactions, got %d", len(models))
+	}
+
+	if models[1].ID != rollback.ID || models[1].Reason != "provider outage" {
+		t.Errorf("Expected rollback with reason last, got %+v", models[1])
+	}
+
+	secondRollback := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "rollback", Amount: 100, Timestamp: time.Now(), OriginalTransactionID: &bet.ID}
+	if err := repo.Save(secondRollback); err == nil {
+		t.Error("Expected second rollback of the same transaction to be rejected")
+	}
+}
+
+func TestPostgresTransactionRepository_Integration_Currency(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	userID := utils.GenerateUUID()
+	eurDeposit := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "deposit", Amount: 5000, Currency: "EUR", Timestamp: time.Now()}
+	eurBet := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 1200, Currency: "EUR", Timestamp: time.Now()}
+	btcDeposit := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "deposit", Amount: 9_000_000_000_000_000_000, Currency: "BTC", Timestamp: time.Now()}
+	legacy := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "deposit", Amount: 1, Timestamp: time.Now()}
+
+	for _, model := range []*repo_model.TransactionModel{eurDeposit, eurBet, btcDeposit, legacy} {
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Expected no error saving, got %v", err)
+		}
+	}
+
+	currency := "BTC"
+	models, err := repo.GetByUserID(userID, nil, &currency)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(models) != 1 || models[0].Amount != btcDeposit.Amount {
+		t.Fatalf("Expected the BTC deposit with its full amount, got %+v", models)
+	}
+
+	transactionType := "deposit"
+	currency = "EUR"
+	models, err = repo.GetAll(&transactionType, &currency)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(models) != 2 {
+		t.Errorf("Expected EUR deposit and legacy deposit defaulted to EUR, got %d", len(models))
+	}
+
+	totals, err := repo.GetTotals(userID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	byKey := map[string]int64{}
+	for _, total := range totals {
+		byKey[total.Currency+"/"+total.TransactionType] = total.Total
+	}
+	if byKey["EUR/deposit"] != 5001 || byKey["EUR/bet"] != 1200 || byKey["BTC/deposit"] != 9_000_000_000_000_000_000 {
+		t.Errorf("Expected totals grouped by currency, got %v", byKey)
+	}
+}
+
+func TestPostgresTransactionRepository_Integration_ReportingAmount(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	reportingAmount := int64(9200)
+	rate := "0.92"
+	converted := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: utils.GenerateUUID(), TransactionType: "deposit", Amount: 10000, Currency: "USD", Timestamp: time.Now(), ReportingAmount: &reportingAmount, ExchangeRate: &rate}
+	unconverted := &repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: utils.GenerateUUID(), TransactionType: "deposit", Amount: 1, Currency: "BTC", Timestamp: time.Now()}
+
+	for _, model := range []*repo_model.TransactionModel{converted, unconverted} {
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Expected no error saving, got %v", err)
+		}
+	}
+
+	saved, err := repo.GetByID(converted.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.ReportingAmount == nil || *saved.ReportingAmount != 9200 || saved.ExchangeRate == nil {
+		t.Errorf("Expected reporting amount and rate to be stored, got %+v", saved)
+	}
+
+	saved, err = repo.GetByID(unconverted.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.ReportingAmount != nil || saved.ExchangeRate != nil {
+		t.Errorf("Expected unconverted transaction to have no reporting amount, got %+v", saved)
+	}
+}
+
+func TestPostgresTransactionRepository_Integration_Stream(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	userID := utils.GenerateUUID()
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		currency := "EUR"
+		if i%2 == 1 {
+			currency = "USD"
+		}
+		model := &repo_model.TransactionModel{
+			ID:              utils.GenerateUUID(),
+			UserID:          userID,
+			TransactionType: "bet",
+			Amount:          uint(100 * (i + 1)),
+			Currency:        currency,
+			Timestamp:       start.Add(time.Duration(4-i) * time.Hour),
+		}
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	otherUser := utils.GenerateUUID()
+	if err := repo.Save(&repo_model.TransactionModel{ID: utils.GenerateUUID(), UserID: otherUser, TransactionType: "bet", Amount: 1, Timestamp: start}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var streamed []*repo_model.TransactionModel
+	err := repo.Stream(context.Background(), &userID, nil, nil, func(model *repo_model.TransactionModel) error {
+		streamed = append(streamed, model)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(streamed) != 5 {
+		t.Fatalf("Expected 5 transactions, got %d", len(streamed))
+	}
+	if streamed[0].Amount != 500 || streamed[4].Amount != 100 {
+		t.Errorf("Expected oldest first, got %d first and %d last", streamed[0].Amount, streamed[4].Amount)
+	}
+
+	currency := "USD"
+	count := 0
+	err = repo.Stream(context.Background(), &userID, nil, &currency, func(model *repo_model.TransactionModel) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 2 {
+		t.Errorf("Expected 2 USD transactions, got %d, %v", count, err)
+	}
+
+	stop := errors.New("stop")
+	count = 0
+	err = repo.Stream(context.Background(), nil, nil, nil, func(model *repo_model.TransactionModel) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Errorf("Expected the callback error to stop the stream, got %d, %v", count, err)
+	}
+}
+
+func TestPostgresTransactionRepository_Integration_SaveBatch(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewPostgresTransactionRepository(db)
+
+	userID := utils.GenerateUUID()
+	betID := utils.GenerateUUID()
+	day := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	amount := int64(100)
+	batch := []*repo_model.TransactionModel{
+		{ID: betID, UserID: userID, TransactionType: "bet", Amount: 100, Currency: "EUR", ReportingAmount: &amount, Timestamp: day},
+		{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 200, Currency: "EUR", Timestamp: day.Add(time.Minute)},
+		{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "rollback", Amount: 100, Currency: "EUR", OriginalTransactionID: &betID, ReportingAmount: &amount, Timestamp: day.Add(time.Hour)},
+	}
+
+	if err := repo.SaveBatch(batch); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var count int64
+	db.Model(&repo_model.TransactionModel{}).Where("user_id = ?", userID).Count(&count)
+	if count != 3 {
+		t.Errorf("Expected 3 transactions, got %d", count)
+	}
+
+	var aggregates []*repo_model.DailyUserAggregateModel
+	db.Where("user_id = ?", userID).Order("transaction_type").Find(&aggregates)
+	if len(aggregates) != 2 {
+		t.Fatalf("Expected 2 aggregates, got %d", len(aggregates))
+	}
+	if aggregates[0].TransactionType != "bet" || aggregates[0].Count != 2 || aggregates[0].AmountSum != 300 || aggregates[0].ReportingAmountSum != 100 || aggregates[0].UnconvertedCount != 1 {
+		t.Errorf("Unexpected bet aggregate %+v", aggregates[0])
+	}
+	if aggregates[1].OriginalTransactionType != "bet" {
+		t.Errorf("Expected the rollback to resolve an original from the same batch, got %+v", aggregates[1])
+	}
+
+	duplicate := []*repo_model.TransactionModel{
+		{ID: utils.GenerateUUID(), UserID: userID, TransactionType: "bet", Amount: 1, Currency: "EUR", Timestamp: day},
+		{ID: betID, UserID: userID, TransactionType: "bet", Amount: 1, Currency: "EUR", Timestamp: day},
+	}
+	if err := repo.SaveBatch(duplicate); err == nil {
+		t.Fatal("Expected error for a duplicate id")
+	}
+
+	db.Model(&repo_model.TransactionModel{}).Where("user_id = ?", userID).Count(&count)
+	if count != 3 {
+		t.Errorf("Expected a failed batch to save nothing, got %d transactions", count)
+	}
+}
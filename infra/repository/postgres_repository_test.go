@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"context"
+	"database/sql/driver"
 	"errors"
 	"testing"
 	"time"
@@ -38,6 +40,42 @@ func setupMockTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	return db, mock, cleanup
 }
 
+func transactionInsertArgs(model *repo_model.TransactionModel) []driver.Value {
+	// gorm substitutes the column default for an empty currency.
+	currency := model.Currency
+	if currency == "" {
+		currency = "EUR"
+	}
+
+	return []driver.Value{
+		model.ID,
+		model.UserID,
+		model.TransactionType,
+		model.Amount,
+		currency,
+		model.Timestamp,
+		model.RoundID,
+		model.GameID,
+		model.ProviderID,
+		model.OriginalTransactionID,
+		model.Reason,
+		model.ReportingAmount,
+		model.ExchangeRate,
+	}
+}
+
+func expectDailyAggregateUpsert(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`INSERT INTO "daily_user_aggregates" (.+) ON CONFLICT`).WillReturnResult(sqlmock.NewResult(1, 1))
+}
+
+func expectUserLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectOutboxInsert(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery(`INSERT INTO "outbox_events" (.+) RETURNING "id"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
 func TestNewPostgresTransactionRepository(t *testing.T) {
 	db, _, cleanup := setupMockTestDB(t)
 	defer cleanup()
@@ -68,13 +106,10 @@ func TestPostgresTransactionRepository_Save_Success(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO (.+)").WithArgs(
-		model.ID,
-		model.UserID,
-		model.TransactionType,
-		model.Amount,
-		model.Timestamp,
-	).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectUserLock(mock)
+	mock.ExpectExec("INSERT INTO (.+)").WithArgs(transactionInsertArgs(model)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectDailyAggregateUpsert(mock)
+	expectOutboxInsert(mock)
 	mock.ExpectCommit()
 
 	err := repo.Save(model)
@@ -87,6 +122,44 @@ func TestPostgresTransactionRepository_Save_Success(t *testing.T) {
 	}
 }
 
+func TestPostgresTransactionRepository_SaveChecked_LocksUser(t *testing.T) {
+	db, mock, cleanup := setupMockTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresTransactionRepository(db)
+
+	model := &repo_model.TransactionModel{
+		ID:              utils.GenerateUUID(),
+		UserID:          utils.GenerateUUID(),
+		TransactionType: "withdrawal",
+		Amount:          100,
+		Timestamp:       time.Now(),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").WithArgs(userLockClass, model.UserID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT (.+) FROM transactions AS t (.+) WHERE t.user_id = (.+)").
+		WithArgs(model.UserID).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "transaction_type", "original_transaction_type", "total", "count"}).
+			AddRow("EUR", "deposit", "", 50, 1))
+	mock.ExpectRollback()
+
+	rejected := errors.New("insufficient balance")
+	err := repo.SaveChecked(model, func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		if len(totals) != 1 || totals[0].Total != 50 {
+			t.Errorf("Unexpected totals %+v", totals)
+		}
+		return rejected
+	})
+	if err != rejected {
+		t.Errorf("Expected the check error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %s", err)
+	}
+}
+
 func TestPostgresTransactionRepository_Save_Error(t *testing.T) {
 	db, mock, cleanup := setupMockTestDB(t)
 	defer cleanup()
@@ -102,6 +175,7 @@ func TestPostgresTransactionRepository_Save_Error(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
+	expectUserLock(mock)
 	mock.ExpectExec("INSERT INTO (.+)").WillReturnError(errors.New("database error"))
 	mock.ExpectRollback()
 
@@ -163,7 +237,7 @@ func TestPostgresTransactionRepository_GetByUserID_Success(t *testing.T) {
 		WithArgs(userID).
 		WillReturnRows(expectedRows)
 
-	models, err := repo.GetByUserID(userID, nil)
+	models, err := repo.GetByUserID(userID, nil, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -192,7 +266,7 @@ func TestPostgresTransactionRepository_GetByUserID_WithTransactionType(t *testin
 		WithArgs(userID, "bet").
 		WillReturnRows(expectedRows)
 
-	models, err := repo.GetByUserID(userID, &transactionType)
+	models, err := repo.GetByUserID(userID, &transactionType, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -223,7 +297,7 @@ func TestPostgresTransactionRepository_GetByUserID_EmptyResult(t *testing.T) {
 		WithArgs(userID).
 		WillReturnRows(expectedRows)
 
-	models, err := repo.GetByUserID(userID, nil)
+	models, err := repo.GetByUserID(userID, nil, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -249,7 +323,7 @@ func TestPostgresTransactionRepository_GetByUserID_DatabaseError(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(errors.New("database connection error"))
 
-	models, err := repo.GetByUserID(userID, nil)
+	models, err := repo.GetByUserID(userID, nil, nil)
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
@@ -272,7 +346,7 @@ func TestPostgresTransactionRepository_GetByUserID_NilDB(t *testing.T) {
 
 	userID := utils.GenerateUUID()
 
-	models, err := repo.GetByUserID(userID, nil)
+	models, err := repo.GetByUserID(userID, nil, nil)
 	if err == nil {
 		t.Error("Expected error for nil DB")
 	}
@@ -296,7 +370,7 @@ func TestPostgresTransactionRepository_GetAll_Success(t *testing.T) {
 	mock.ExpectQuery("SELECT (.+) FROM (.+) ORDER BY timestamp DESC").
 		WillReturnRows(expectedRows)
 
-	models, err := repo.GetAll(nil)
+	models, err := repo.GetAll(nil, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -325,7 +399,7 @@ func TestPostgresTransactionRepository_GetAll_WithTransactionType(t *testing.T)
 		WithArgs("win").
 		WillReturnRows(expectedRows)
 
-	models, err := repo.GetAll(&transactionType)
+	models, err := repo.GetAll(&transactionType, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -356,7 +430,7 @@ func TestPostgresTransactionRepository_GetAll_EmptyResult(t *testing.T) {
 	mock.ExpectQuery("SELECT (.+) FROM (.+) ORDER BY timestamp DESC").
 		WillReturnRows(expectedRows)
 
-	models, err := repo.GetAll(nil)
+	models, err := repo.GetAll(nil, nil)
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -379,7 +453,7 @@ func TestPostgresTransactionRepository_GetAll_DatabaseError(t *testing.T) {
 	mock.ExpectQuery("SELECT (.+) FROM (.+) ORDER BY timestamp DESC").
 		WillReturnError(errors.New("database query error"))
 
-	models, err := repo.GetAll(nil)
+	models, err := repo.GetAll(nil, nil)
 	if err == nil {
 		t.Error("Expected error, got nil")
 	}
@@ -400,7 +474,7 @@ func TestPostgresTransactionRepository_GetAll_DatabaseError(t *testing.T) {
 func TestPostgresTransactionRepository_GetAll_NilDB(t *testing.T) {
 	repo := NewPostgresTransactionRepository(nil)
 
-	models, err := repo.GetAll(nil)
+	models, err := repo.GetAll(nil, nil)
 	if err == nil {
 		t.Error("Expected error for nil DB")
 	}
@@ -410,6 +484,39 @@ func TestPostgresTransactionRepository_GetAll_NilDB(t *testing.T) {
 	}
 }
 
+func TestPostgresTransactionRepository_Stream_QueryError(t *testing.T) {
+	db, mock, cleanup := setupMockTestDB(t)
+	defer cleanup()
+
+	repo := NewPostgresTransactionRepository(db)
+
+	mock.ExpectQuery(`SELECT (.+) FROM "transactions"`).WillReturnError(errors.New("database error"))
+
+	called := false
+	err := repo.Stream(context.Background(), nil, nil, nil, func(model *repo_model.TransactionModel) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error, got nil")
+	}
+
+	if called {
+		t.Error("Expected no rows to be passed on")
+	}
+}
+
+func TestPostgresTransactionRepository_Stream_NilDB(t *testing.T) {
+	repo := NewPostgresTransactionRepository(nil)
+
+	err := repo.Stream(context.Background(), nil, nil, nil, func(model *repo_model.TransactionModel) error {
+		return nil
+	})
+	if err == nil {
+		t.Error("Expected error for nil DB")
+	}
+}
+
 func TestPostgresTransactionRepository_DataConversion(t *testing.T) {
 	userID := utils.GenerateUUID()
 	transactionType := entity.TransactionTypeBet
@@ -501,13 +608,10 @@ func TestPostgresTransactionRepository_EdgeCases(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO (.+)").WithArgs(
-		transaction.ID,
-		transaction.UserID,
-		transaction.TransactionType,
-		transaction.Amount,
-		transaction.Timestamp,
-	).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectUserLock(mock)
+	mock.ExpectExec("INSERT INTO (.+)").WithArgs(transactionInsertArgs(transaction)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectDailyAggregateUpsert(mock)
+	expectOutboxInsert(mock)
 	mock.ExpectCommit()
 
 	err := repo.Save(transaction)
@@ -524,13 +628,10 @@ func TestPostgresTransactionRepository_EdgeCases(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO (.+)").WithArgs(
-		largeTransaction.ID,
-		largeTransaction.UserID,
-		largeTransaction.TransactionType,
-		largeTransaction.Amount,
-		largeTransaction.Timestamp,
-	).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectUserLock(mock)
+	mock.ExpectExec("INSERT INTO (.+)").WithArgs(transactionInsertArgs(largeTransaction)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectDailyAggregateUpsert(mock)
+	expectOutboxInsert(mock)
 	mock.ExpectCommit()
 
 	err = repo.Save(largeTransaction)
@@ -625,6 +726,7 @@ func TestPostgresTransactionRepository_ErrorHandling(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
+	expectUserLock(mock)
 	mock.ExpectExec("INSERT INTO (.+)").WillReturnError(errors.New("constraint violation"))
 	mock.ExpectRollback()
 
@@ -642,7 +744,7 @@ func TestPostgresTransactionRepository_ErrorHandling(t *testing.T) {
 		WithArgs(userID).
 		WillReturnError(errors.New("connection timeout"))
 
-	_, err = repo.GetByUserID(userID, nil)
+	_, err = repo.GetByUserID(userID, nil, nil)
 	if err == nil {
 		t.Error("Expected error for connection timeout")
 	}
@@ -654,7 +756,7 @@ func TestPostgresTransactionRepository_ErrorHandling(t *testing.T) {
 	mock.ExpectQuery("SELECT (.+) FROM (.+) ORDER BY timestamp DESC").
 		WillReturnError(errors.New("table not found"))
 
-	_, err = repo.GetAll(nil)
+	_, err = repo.GetAll(nil, nil)
 	if err == nil {
 		t.Error("Expected error for table not found")
 	}
@@ -683,13 +785,10 @@ func TestPostgresTransactionRepository_BoundaryValues(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO (.+)").WithArgs(
-		minTransaction.ID,
-		minTransaction.UserID,
-		minTransaction.TransactionType,
-		minTransaction.Amount,
-		minTransaction.Timestamp,
-	).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectUserLock(mock)
+	mock.ExpectExec("INSERT INTO (.+)").WithArgs(transactionInsertArgs(minTransaction)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectDailyAggregateUpsert(mock)
+	expectOutboxInsert(mock)
 	mock.ExpectCommit()
 
 	err := repo.Save(minTransaction)
@@ -706,13 +805,10 @@ func TestPostgresTransactionRepository_BoundaryValues(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
-	mock.ExpectExec("INSERT INTO (.+)").WithArgs(
-		maxTransaction.ID,
-		maxTransaction.UserID,
-		maxTransaction.TransactionType,
-		maxTransaction.Amount,
-		maxTransaction.Timestamp,
-	).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectUserLock(mock)
+	mock.ExpectExec("INSERT INTO (.+)").WithArgs(transactionInsertArgs(maxTransaction)...).WillReturnResult(sqlmock.NewResult(1, 1))
+	expectDailyAggregateUpsert(mock)
+	expectOutboxInsert(mock)
 	mock.ExpectCommit()
 
 	err = repo.Save(maxTransaction)
@@ -787,11 +883,11 @@ func TestPostgresTransactionRepository_GetByID_NotFound(t *testing.T) {
 
 	mock.ExpectQuery("SELECT (.+) FROM (.+) WHERE id = (.+) ORDER BY (.+) LIMIT (.+)").
 		WithArgs(transactionID, 1).
-		WillReturnError(errors.New("record not found"))
+		WillReturnError(gorm.ErrRecordNotFound)
 
 	model, err := repo.GetByID(transactionID)
-	if err != nil {
-		t.Errorf("Expected no error for not found, got %v", err)
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
 	}
 
 	if model != nil {
@@ -862,11 +958,11 @@ func TestPostgresTransactionRepository_GetByID_EmptyID(t *testing.T) {
 
 	mock.ExpectQuery("SELECT (.+) FROM (.+) WHERE id = (.+) ORDER BY (.+) LIMIT (.+)").
 		WithArgs(emptyID, 1).
-		WillReturnError(errors.New("record not found"))
+		WillReturnError(gorm.ErrRecordNotFound)
 
 	model, err := repo.GetByID(emptyID)
-	if err != nil {
-		t.Errorf("Expected no error for empty ID, got %v", err)
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for empty ID, got %v", err)
 	}
 
 	if model != nil {
@@ -0,0 +1,453 @@
+// Package repositorytest holds contract tests that every repository
+// implementation has to pass, so the in-memory and database versions behave
+// the same.
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"casino/boundary/repo_model"
+	"casino/boundary/repository"
+	"casino/utils"
+)
+
+// TransactionRepository runs the contract against repositories built by
+// newRepository, which must return an empty repository on every call.
+func TransactionRepository(t *testing.T, newRepository func(t *testing.T) repository.TransactionRepository) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, repo repository.TransactionRepository)
+	}{
+		{"SaveAndGetByID", testSaveAndGetByID},
+		{"GetByIDMissing", testGetByIDMissing},
+		{"SaveNil", testSaveNil},
+		{"SaveDefaultsCurrency", testSaveDefaultsCurrency},
+		{"SaveDuplicateID", testSaveDuplicateID},
+		{"SingleRollbackPerOriginal", testSingleRollbackPerOriginal},
+		{"SaveBatch", testSaveBatch},
+		{"SaveBatchIsAtomic", testSaveBatchIsAtomic},
+		{"SaveChecked", testSaveChecked},
+		{"SaveCheckedSeesLinked", testSaveCheckedSeesLinked},
+		{"GetByUserID", testGetByUserID},
+		{"GetAll", testGetAll},
+		{"GetByRoundID", testGetByRoundID},
+		{"GetByOriginalID", testGetByOriginalID},
+		{"GetTotals", testGetTotals},
+		{"Stream", testStream},
+		{"StreamStops", testStreamStops},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepository(t))
+		})
+	}
+}
+
+var start = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func transaction(userID, transactionType string, amount uint, at time.Time) *repo_model.TransactionModel {
+	return &repo_model.TransactionModel{
+		ID:              utils.GenerateUUID(),
+		UserID:          userID,
+		TransactionType: transactionType,
+		Amount:          amount,
+		Currency:        "EUR",
+		Timestamp:       at,
+	}
+}
+
+func save(t *testing.T, repo repository.TransactionRepository, transactions ...*repo_model.TransactionModel) {
+	t.Helper()
+
+	for _, model := range transactions {
+		if err := repo.Save(model); err != nil {
+			t.Fatalf("Failed to save transaction %s: %v", model.ID, err)
+		}
+	}
+}
+
+func ids(transactions []*repo_model.TransactionModel) []string {
+	result := make([]string, len(transactions))
+	for i, model := range transactions {
+		result[i] = model.ID
+	}
+	return result
+}
+
+func expectIDs(t *testing.T, got []*repo_model.TransactionModel, err error, want ...*repo_model.TransactionModel) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(ids(got), ids(want)) {
+		t.Errorf("Expected transactions %v, got %v", ids(want), ids(got))
+	}
+}
+
+func testSaveAndGetByID(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	original := transaction(userID, "bet", 100, start.Add(-time.Minute))
+	save(t, repo, original)
+
+	originalID := original.ID
+	reportingAmount := int64(90)
+	model := transaction(userID, "refund", 100, start)
+	model.Currency = "USD"
+	model.RoundID = "round-1"
+	model.GameID = "game-1"
+	model.ProviderID = "provider-1"
+	model.OriginalTransactionID = &originalID
+	model.Reason = "malfunction"
+	model.ReportingAmount = &reportingAmount
+	save(t, repo, model)
+
+	saved, err := repo.GetByID(model.ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved == nil {
+		t.Fatal("Expected the transaction to be found")
+	}
+
+	if saved.UserID != model.UserID || saved.TransactionType != "refund" || saved.Amount != 100 || saved.Currency != "USD" ||
+		saved.RoundID != "round-1" || saved.GameID != "game-1" || saved.ProviderID != "provider-1" || saved.Reason != "malfunction" {
+		t.Errorf("Expected the saved fields back, got %+v", saved)
+	}
+	if !saved.Timestamp.Equal(start) {
+		t.Errorf("Expected timestamp %v, got %v", start, saved.Timestamp)
+	}
+	if saved.OriginalTransactionID == nil || *saved.OriginalTransactionID != originalID {
+		t.Errorf("Expected original transaction %s, got %v", originalID, saved.OriginalTransactionID)
+	}
+	if saved.ReportingAmount == nil || *saved.ReportingAmount != 90 {
+		t.Errorf("Expected reporting amount 90, got %v", saved.ReportingAmount)
+	}
+	if saved.ExchangeRate != nil {
+		t.Errorf("Expected no exchange rate, got %v", *saved.ExchangeRate)
+	}
+}
+
+func testGetByIDMissing(t *testing.T, repo repository.TransactionRepository) {
+	saved, err := repo.GetByID(utils.GenerateUUID())
+	if !errors.Is(err, repository.ErrNotFound) || saved != nil {
+		t.Errorf("Expected ErrNotFound for a missing transaction, got %v, %v", saved, err)
+	}
+}
+
+func testSaveNil(t *testing.T, repo repository.TransactionRepository) {
+	if err := repo.Save(nil); err == nil {
+		t.Error("Expected an error for a nil transaction")
+	}
+}
+
+func testSaveDefaultsCurrency(t *testing.T, repo repository.TransactionRepository) {
+	model := transaction(utils.GenerateUUID(), "bet", 100, start)
+	model.Currency = ""
+	save(t, repo, model)
+
+	saved, err := repo.GetByID(model.ID)
+	if err != nil || saved == nil || saved.Currency != "EUR" {
+		t.Errorf("Expected the currency to default to EUR, got %+v, %v", saved, err)
+	}
+}
+
+func testSaveDuplicateID(t *testing.T, repo repository.TransactionRepository) {
+	model := transaction(utils.GenerateUUID(), "bet", 100, start)
+	save(t, repo, model)
+
+	duplicate := transaction(model.UserID, "bet", 200, start)
+	duplicate.ID = model.ID
+	if err := repo.Save(duplicate); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("Expected a duplicate error, got %v", err)
+	}
+
+	saved, _ := repo.GetByID(model.ID)
+	if saved == nil || saved.Amount != 100 {
+		t.Errorf("Expected the first transaction to be kept, got %+v", saved)
+	}
+}
+
+func testSingleRollbackPerOriginal(t *testing.T, repo repository.TransactionRepository) {
+	bet := transaction(utils.GenerateUUID(), "bet", 100, start)
+	rollback := transaction(bet.UserID, "rollback", 100, start.Add(time.Minute))
+	rollback.OriginalTransactionID = &bet.ID
+	save(t, repo, bet, rollback)
+
+	second := transaction(bet.UserID, "rollback", 100, start.Add(2*time.Minute))
+	second.OriginalTransactionID = &bet.ID
+	if err := repo.Save(second); !errors.Is(err, repository.ErrDuplicate) {
+		t.Errorf("Expected a duplicate error for a second rollback of the same transaction, got %v", err)
+	}
+
+	refund := transaction(bet.UserID, "refund", 50, start.Add(3*time.Minute))
+	refund.OriginalTransactionID = &bet.ID
+	save(t, repo, refund)
+}
+
+func testSaveBatch(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	first := transaction(userID, "bet", 100, start)
+	second := transaction(userID, "win", 200, start.Add(time.Minute))
+
+	if err := repo.SaveBatch(nil); err != nil {
+		t.Errorf("Expected an empty batch to succeed, got %v", err)
+	}
+	if err := repo.SaveBatch([]*repo_model.TransactionModel{first, second}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	models, err := repo.GetByUserID(userID, nil, nil)
+	expectIDs(t, models, err, second, first)
+}
+
+func testSaveBatchIsAtomic(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	existing := transaction(userID, "bet", 100, start)
+	save(t, repo, existing)
+
+	fresh := transaction(userID, "bet", 200, start.Add(time.Minute))
+	duplicate := transaction(userID, "bet", 300, start.Add(2*time.Minute))
+	duplicate.ID = existing.ID
+	if err := repo.SaveBatch([]*repo_model.TransactionModel{fresh, duplicate}); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("Expected a duplicate error for a batch with a duplicate id, got %v", err)
+	}
+
+	models, err := repo.GetByUserID(userID, nil, nil)
+	expectIDs(t, models, err, existing)
+}
+
+func testSaveChecked(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	deposit := transaction(userID, "deposit", 100, start)
+	save(t, repo, deposit, transaction(utils.GenerateUUID(), "deposit", 500, start))
+
+	var seen []*repo_model.TransactionTotalModel
+	withdrawal := transaction(userID, "withdrawal", 60, start.Add(time.Minute))
+	err := repo.SaveChecked(withdrawal, func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		seen = totals
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(seen) != 1 || seen[0].TransactionType != "deposit" || seen[0].Total != 100 {
+		t.Errorf("Expected check to see the user's deposit only, got %+v", seen)
+	}
+
+	rejected := errors.New("insufficient balance")
+	second := transaction(userID, "withdrawal", 60, start.Add(2*time.Minute))
+	err = repo.SaveChecked(second, func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		seen = totals
+		return rejected
+	})
+	if err != rejected {
+		t.Fatalf("Expected the check error unchanged, got %v", err)
+	}
+	if len(seen) != 2 {
+		t.Errorf("Expected check to see the first withdrawal, got %+v", seen)
+	}
+
+	models, err := repo.GetByUserID(userID, nil, nil)
+	expectIDs(t, models, err, withdrawal, deposit)
+}
+
+func testSaveCheckedSeesLinked(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	bet := transaction(userID, "bet", 100, start)
+	refund := transaction(userID, "refund", 40, start.Add(time.Minute))
+	refund.OriginalTransactionID = &bet.ID
+	otherBet := transaction(userID, "bet", 100, start)
+	otherRefund := transaction(userID, "refund", 10, start.Add(time.Minute))
+	otherRefund.OriginalTransactionID = &otherBet.ID
+	save(t, repo, bet, refund, otherBet, otherRefund)
+
+	var seen []*repo_model.TransactionModel
+	second := transaction(userID, "refund", 30, start.Add(2*time.Minute))
+	second.OriginalTransactionID = &bet.ID
+	err := repo.SaveChecked(second, func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		seen = linked
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectIDs(t, seen, nil, refund)
+
+	seen = nil
+	deposit := transaction(userID, "deposit", 100, start.Add(3*time.Minute))
+	err = repo.SaveChecked(deposit, func(totals []*repo_model.TransactionTotalModel, linked []*repo_model.TransactionModel) error {
+		seen = linked
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(seen) != 0 {
+		t.Errorf("Expected no linked transactions without an original, got %+v", seen)
+	}
+}
+
+func testGetByUserID(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	oldest := transaction(userID, "bet", 100, start)
+	middle := transaction(userID, "win", 200, start.Add(time.Hour))
+	newest := transaction(userID, "bet", 300, start.Add(2*time.Hour))
+	newest.Currency = "USD"
+	other := transaction(utils.GenerateUUID(), "bet", 400, start)
+	save(t, repo, middle, newest, oldest, other)
+
+	models, err := repo.GetByUserID(userID, nil, nil)
+	expectIDs(t, models, err, newest, middle, oldest)
+
+	bet := "bet"
+	models, err = repo.GetByUserID(userID, &bet, nil)
+	expectIDs(t, models, err, newest, oldest)
+
+	eur := "EUR"
+	models, err = repo.GetByUserID(userID, &bet, &eur)
+	expectIDs(t, models, err, oldest)
+
+	models, err = repo.GetByUserID(utils.GenerateUUID(), nil, nil)
+	expectIDs(t, models, err)
+}
+
+func testGetAll(t *testing.T, repo repository.TransactionRepository) {
+	first := transaction(utils.GenerateUUID(), "bet", 100, start)
+	second := transaction(utils.GenerateUUID(), "deposit", 200, start.Add(time.Hour))
+	second.Currency = "USD"
+	third := transaction(utils.GenerateUUID(), "bet", 300, start.Add(2*time.Hour))
+	save(t, repo, second, first, third)
+
+	models, err := repo.GetAll(nil, nil)
+	expectIDs(t, models, err, third, second, first)
+
+	bet := "bet"
+	models, err = repo.GetAll(&bet, nil)
+	expectIDs(t, models, err, third, first)
+
+	usd := "USD"
+	models, err = repo.GetAll(nil, &usd)
+	expectIDs(t, models, err, second)
+}
+
+func testGetByRoundID(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	bet := transaction(userID, "bet", 100, start)
+	bet.RoundID = "round-1"
+	win := transaction(userID, "win", 200, start.Add(time.Minute))
+	win.RoundID = "round-1"
+	other := transaction(userID, "bet", 100, start)
+	other.RoundID = "round-2"
+	save(t, repo, win, other, bet)
+
+	models, err := repo.GetByRoundID("round-1")
+	expectIDs(t, models, err, bet, win)
+
+	models, err = repo.GetByRoundID("round-3")
+	expectIDs(t, models, err)
+}
+
+func testGetByOriginalID(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	bet := transaction(userID, "bet", 100, start)
+	firstRefund := transaction(userID, "refund", 30, start.Add(time.Minute))
+	firstRefund.OriginalTransactionID = &bet.ID
+	secondRefund := transaction(userID, "refund", 20, start.Add(2*time.Minute))
+	secondRefund.OriginalTransactionID = &bet.ID
+	save(t, repo, bet, secondRefund, firstRefund)
+
+	models, err := repo.GetByOriginalID(bet.ID)
+	expectIDs(t, models, err, firstRefund, secondRefund)
+}
+
+func testGetTotals(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	deposit := transaction(userID, "deposit", 1000, start)
+	bet := transaction(userID, "bet", 100, start.Add(time.Minute))
+	secondBet := transaction(userID, "bet", 50, start.Add(2*time.Minute))
+	rollback := transaction(userID, "rollback", 100, start.Add(3*time.Minute))
+	rollback.OriginalTransactionID = &bet.ID
+	usdDeposit := transaction(userID, "deposit", 500, start)
+	usdDeposit.Currency = "USD"
+	other := transaction(utils.GenerateUUID(), "deposit", 700, start)
+	save(t, repo, deposit, bet, secondBet, rollback, usdDeposit, other)
+
+	totals, err := repo.GetTotals(userID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	got := make(map[repo_model.TransactionTotalModel]bool)
+	for _, total := range totals {
+		got[*total] = true
+	}
+	want := map[repo_model.TransactionTotalModel]bool{
+		{Currency: "EUR", TransactionType: "deposit", Total: 1000, Count: 1}:                                 true,
+		{Currency: "EUR", TransactionType: "bet", Total: 150, Count: 2}:                                      true,
+		{Currency: "EUR", TransactionType: "rollback", OriginalTransactionType: "bet", Total: 100, Count: 1}: true,
+		{Currency: "USD", TransactionType: "deposit", Total: 500, Count: 1}:                                  true,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d totals, got %+v", len(want), got)
+	}
+	for total := range want {
+		if !got[total] {
+			t.Errorf("Expected total %+v, got %+v", total, got)
+		}
+	}
+}
+
+func testStream(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	first := transaction(userID, "bet", 100, start)
+	second := transaction(userID, "bet", 200, start.Add(time.Hour))
+	second.Currency = "USD"
+	third := transaction(userID, "win", 300, start.Add(2*time.Hour))
+	other := transaction(utils.GenerateUUID(), "bet", 400, start)
+	save(t, repo, third, other, second, first)
+
+	stream := func(userID, transactionType, currency *string) []*repo_model.TransactionModel {
+		var streamed []*repo_model.TransactionModel
+		err := repo.Stream(context.Background(), userID, transactionType, currency, func(model *repo_model.TransactionModel) error {
+			streamed = append(streamed, model)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		return streamed
+	}
+
+	expectIDs(t, stream(&userID, nil, nil), nil, first, second, third)
+
+	bet := "bet"
+	expectIDs(t, stream(&userID, &bet, nil), nil, first, second)
+
+	usd := "USD"
+	expectIDs(t, stream(nil, nil, &usd), nil, second)
+
+	if streamed := stream(nil, nil, nil); len(streamed) != 4 {
+		t.Errorf("Expected every transaction without filters, got %d", len(streamed))
+	}
+}
+
+func testStreamStops(t *testing.T, repo repository.TransactionRepository) {
+	userID := utils.GenerateUUID()
+	save(t, repo, transaction(userID, "bet", 100, start), transaction(userID, "bet", 200, start.Add(time.Hour)))
+
+	stop := errors.New("stop")
+	count := 0
+	err := repo.Stream(context.Background(), &userID, nil, nil, func(model *repo_model.TransactionModel) error {
+		count++
+		return stop
+	})
+	if !errors.Is(err, stop) || count != 1 {
+		t.Errorf("Expected the callback error to stop the stream, got %d calls, %v", count, err)
+	}
+}
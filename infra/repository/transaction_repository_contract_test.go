@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"os"
+	"testing"
+
+	"casino/boundary/repository"
+	"casino/infra/repository/repositorytest"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestPostgresTransactionRepository_Contract_SQLite(t *testing.T) {
+	repositorytest.TransactionRepository(t, func(t *testing.T) repository.TransactionRepository {
+		return NewPostgresTransactionRepository(setupTestDB(t))
+	})
+}
+
+// The Postgres run needs a migrated database, for example the one from
+// docker-compose, given as CASINO_TEST_POSTGRES_DSN. Each case runs in a
+// transaction that is rolled back afterwards.
+func TestPostgresTransactionRepository_Contract_Postgres(t *testing.T) {
+	dsn := os.Getenv("CASINO_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("Skipping test: CASINO_TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Skipf("Skipping test: failed to connect to database: %v", err)
+	}
+
+	repositorytest.TransactionRepository(t, func(t *testing.T) repository.TransactionRepository {
+		tx := db.Begin()
+		if tx.Error != nil {
+			t.Fatalf("Failed to begin transaction: %v", tx.Error)
+		}
+		t.Cleanup(func() { tx.Rollback() })
+
+		// GetAll and Stream see every row, so start from an empty table.
+		for _, table := range []string{"outbox_events", "daily_user_aggregates", "transactions"} {
+			if err := tx.Exec("DELETE FROM " + table).Error; err != nil {
+				t.Fatalf("Failed to clear %s: %v", table, err)
+			}
+		}
+		return NewPostgresTransactionRepository(tx)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"casino/boundary/logging"
 	"casino/infra/middleware"
 	"casino/infra/restserver"
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -19,22 +20,85 @@ type route struct {
 
 type NetHttpServer struct {
 	routes []route
+	// requestTimeout bounds regular routes. Streaming routes set their own.
+	requestTimeout time.Duration
+	server         *http.Server
 }
 
 func NewNetHttpServer() restserver.Server {
-	return &NetHttpServer{
-		routes: make([]route, 0),
+	s := &NetHttpServer{
+		routes:         make([]route, 0),
+		requestTimeout: 1 * time.Second,
 	}
+	s.server = &http.Server{Handler: http.HandlerFunc(s.handleAll)}
+	return s
 }
 
 func (s *NetHttpServer) RegisterPublicRoute(method, path string,
 	handler http.HandlerFunc, logger logging.Logger) {
 
 	wrappedHandler := middleware.LoggingMiddleware(handler, logger)
+	timeoutHandler := http.TimeoutHandler(wrappedHandler, s.requestTimeout, "Service is not available")
 	s.routes = append(s.routes, route{
 		method:  method,
 		path:    path,
-		handler: wrappedHandler,
+		handler: timeoutHandler.ServeHTTP,
+	})
+}
+
+// RegisterAdminRoute registers a route that needs a bearer token accepted by
+// authenticator. A timeout of zero uses the default request timeout.
+func (s *NetHttpServer) RegisterAdminRoute(method, path string,
+	handler http.HandlerFunc, timeout time.Duration, authenticator middleware.Authenticator, logger logging.Logger) {
+
+	if timeout <= 0 {
+		timeout = s.requestTimeout
+	}
+
+	wrappedHandler := middleware.LoggingMiddleware(middleware.AuthMiddleware(handler, authenticator, logger), logger)
+	timeoutHandler := http.TimeoutHandler(wrappedHandler, timeout, "Service is not available")
+	s.routes = append(s.routes, route{
+		method:  method,
+		path:    path,
+		handler: timeoutHandler.ServeHTTP,
+	})
+}
+
+// RegisterUserRoute registers a route on a user's own data, which needs a
+// user token accepted by authenticator for the user in the {id} path
+// parameter.
+func (s *NetHttpServer) RegisterUserRoute(method, path string,
+	handler http.HandlerFunc, authenticator middleware.Authenticator, logger logging.Logger) {
+
+	wrappedHandler := middleware.LoggingMiddleware(middleware.UserMiddleware(handler, authenticator, logger), logger)
+	timeoutHandler := http.TimeoutHandler(wrappedHandler, s.requestTimeout, "Service is not available")
+	s.routes = append(s.routes, route{
+		method:  method,
+		path:    path,
+		handler: timeoutHandler.ServeHTTP,
+	})
+}
+
+// RegisterStreamingRoute registers a route whose response is written
+// incrementally. http.TimeoutHandler buffers the whole response, so instead
+// the request context is cancelled once timeout has passed. A zero timeout
+// keeps the stream open until the client goes away.
+func (s *NetHttpServer) RegisterStreamingRoute(method, path string,
+	handler http.HandlerFunc, timeout time.Duration, logger logging.Logger) {
+
+	wrappedHandler := middleware.LoggingMiddleware(handler, logger)
+	s.routes = append(s.routes, route{
+		method: method,
+		path:   path,
+		handler: func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 {
+				wrappedHandler(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			wrappedHandler(w, r.WithContext(ctx))
+		},
 	})
 }
 
@@ -50,16 +114,14 @@ func (s *NetHttpServer) RegisterSwaggerRoutes() {
 }
 
 func (s *NetHttpServer) Start(address string) error {
-	var handler http.Handler = http.HandlerFunc(s.handleAll)
-	
-	timeoutHandler := http.TimeoutHandler(handler, 1*time.Second, "Service is not available")
-
-	server := http.Server{
-		Addr:    address,
-		Handler: timeoutHandler,
-	}
+	s.server.Addr = address
+	return s.server.ListenAndServe()
+}
 
-	return server.ListenAndServe()
+// Shutdown stops accepting requests and waits for running ones until ctx is
+// done. Start then returns http.ErrServerClosed.
+func (s *NetHttpServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
 }
 
 func (s *NetHttpServer) handleAll(w http.ResponseWriter, r *http.Request) {
@@ -70,13 +132,46 @@ func (s *NetHttpServer) handleAll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, route := range s.routes {
-		if route.method == r.Method && route.path == r.URL.Path {
-			route.handler(w, r)
-			return
+		if route.method != r.Method {
+			continue
+		}
+		params, ok := matchPath(route.path, r.URL.Path)
+		if !ok {
+			continue
+		}
+		for name, value := range params {
+			r.SetPathValue(name, value)
 		}
+		route.handler(w, r)
+		return
 	}
 	w.WriteHeader(http.StatusBadRequest)
 	w.Header().Set("Content-Type", "application/json")
 	w.Write([]byte(`{"error": "no such path"}`))
 
 }
+
+// matchPath matches a request path against a route pattern in which segments
+// written as {name} capture the corresponding path segment.
+func matchPath(pattern, path string) (map[string]string, bool) {
+	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathParts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternParts) != len(pathParts) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, part := range patternParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if pathParts[i] == "" {
+				return nil, false
+			}
+			params[part[1:len(part)-1]] = pathParts[i]
+			continue
+		}
+		if part != pathParts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
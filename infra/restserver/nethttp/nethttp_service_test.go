@@ -0,0 +1,232 @@
+package nethttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"casino/utils"
+)
+
+type MockLogger struct{}
+
+func (m *MockLogger) Error(ctx context.Context, errs ...error)     {}
+func (m *MockLogger) Info(ctx context.Context, messages ...string) {}
+
+func TestMatchPath(t *testing.T) {
+	testCases := []struct {
+		name     string
+		pattern  string
+		path     string
+		matches  bool
+		expected map[string]string
+	}{
+		{"Exact", "/transactions", "/transactions", true, map[string]string{}},
+		{"Exact Mismatch", "/transactions", "/transactions/user", false, nil},
+		{"Param", "/rounds/{id}", "/rounds/round-1", true, map[string]string{"id": "round-1"}},
+		{"Param Missing", "/rounds/{id}", "/rounds/", false, nil},
+		{"Param Trailing Segment", "/rounds/{id}", "/rounds/round-1/extra", false, nil},
+		{"Param In Middle", "/users/{id}/stats", "/users/user-1/stats", true, map[string]string{"id": "user-1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			params, ok := matchPath(tc.pattern, tc.path)
+			if ok != tc.matches {
+				t.Fatalf("Expected match %v, got %v", tc.matches, ok)
+			}
+			for name, value := range tc.expected {
+				if params[name] != value {
+					t.Errorf("Expected param %s=%s, got %s", name, value, params[name])
+				}
+			}
+		})
+	}
+}
+
+func TestNetHttpServer_HandleAll_PathValue(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+
+	var captured string
+	server.RegisterPublicRoute("GET", "/rounds/{id}", func(w http.ResponseWriter, r *http.Request) {
+		captured = r.PathValue("id")
+		w.WriteHeader(http.StatusOK)
+	}, &MockLogger{})
+
+	req := httptest.NewRequest("GET", "/rounds/round-42", nil)
+	rr := httptest.NewRecorder()
+	server.handleAll(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	if captured != "round-42" {
+		t.Errorf("Expected path value 'round-42', got %s", captured)
+	}
+}
+
+func TestNetHttpServer_HandleAll_NoRoute(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+
+	req := httptest.NewRequest("POST", "/rounds/round-42", nil)
+	rr := httptest.NewRecorder()
+	server.handleAll(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, rr.Code)
+	}
+}
+
+func TestNetHttpServer_PublicRouteTimeout(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+	server.requestTimeout = 10 * time.Millisecond
+
+	server.RegisterPublicRoute("GET", "/slow", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}, &MockLogger{})
+
+	req := httptest.NewRequest("GET", "/slow", nil)
+	rr := httptest.NewRecorder()
+	server.handleAll(rr, req)
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, rr.Code)
+	}
+}
+
+type MockAuthenticator struct{}
+
+func (m *MockAuthenticator) AuthenticateUser(token string) (string, error) {
+	if token != "admin-token" {
+		return "", errors.New("invalid token")
+	}
+	return "ops@casino", nil
+}
+
+func TestNetHttpServer_AdminRoute(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+
+	var actor any
+	var deadline time.Time
+	server.RegisterAdminRoute("POST", "/admin/kafka/pause", func(w http.ResponseWriter, r *http.Request) {
+		actor = r.Context().Value(utils.CtxKeyActor)
+		deadline, _ = r.Context().Deadline()
+		w.WriteHeader(http.StatusOK)
+	}, time.Minute, &MockAuthenticator{}, &MockLogger{})
+
+	rr := httptest.NewRecorder()
+	server.handleAll(rr, httptest.NewRequest("POST", "/admin/kafka/pause", nil))
+	if rr.Code != http.StatusUnauthorized || actor != nil {
+		t.Fatalf("Expected an unauthenticated request to be rejected, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest("POST", "/admin/kafka/pause", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rr = httptest.NewRecorder()
+	server.handleAll(rr, req)
+	if rr.Code != http.StatusOK || actor != "ops@casino" {
+		t.Errorf("Expected the request to run as ops@casino, got %d %v", rr.Code, actor)
+	}
+	if time.Until(deadline) < 50*time.Second {
+		t.Errorf("Expected the admin route timeout, got deadline %s", deadline)
+	}
+}
+
+func TestNetHttpServer_UserRoute(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+
+	var captured string
+	server.RegisterUserRoute("GET", "/users/{id}/balances", func(w http.ResponseWriter, r *http.Request) {
+		captured = r.PathValue("id")
+		w.WriteHeader(http.StatusOK)
+	}, &MockAuthenticator{}, &MockLogger{})
+
+	req := httptest.NewRequest("GET", "/users/someone-else/balances", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rr := httptest.NewRecorder()
+	server.handleAll(rr, req)
+	if rr.Code != http.StatusForbidden || captured != "" {
+		t.Fatalf("Expected another user's balances to be refused, got %d", rr.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/users/ops@casino/balances", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rr = httptest.NewRecorder()
+	server.handleAll(rr, req)
+	if rr.Code != http.StatusOK || captured != "ops@casino" {
+		t.Errorf("Expected the token's own balances to be served, got %d %q", rr.Code, captured)
+	}
+}
+
+func TestNetHttpServer_StreamingRoute(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+	server.requestTimeout = 10 * time.Millisecond
+
+	var deadline time.Time
+	var flushed bool
+	server.RegisterStreamingRoute("GET", "/export", func(w http.ResponseWriter, r *http.Request) {
+		deadline, _ = r.Context().Deadline()
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("row\n"))
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+			flushed = true
+		}
+	}, time.Minute, &MockLogger{})
+
+	req := httptest.NewRequest("GET", "/export", nil)
+	rr := httptest.NewRecorder()
+	server.handleAll(rr, req)
+
+	if rr.Code != http.StatusOK || rr.Body.String() != "row\n" {
+		t.Errorf("Expected the streamed row past the regular timeout, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	if !flushed {
+		t.Error("Expected the response writer to support flushing")
+	}
+
+	if time.Until(deadline) < 50*time.Second {
+		t.Errorf("Expected the streaming timeout on the request context, got deadline %s", deadline)
+	}
+}
+
+func TestNetHttpServer_StreamingRoute_NoTimeout(t *testing.T) {
+	server := NewNetHttpServer().(*NetHttpServer)
+
+	hasDeadline := true
+	server.RegisterStreamingRoute("GET", "/stream", func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline = r.Context().Deadline()
+	}, 0, &MockLogger{})
+
+	server.handleAll(httptest.NewRecorder(), httptest.NewRequest("GET", "/stream", nil))
+
+	if hasDeadline {
+		t.Error("Expected no deadline on a streaming route without a timeout")
+	}
+}
+
+func TestNetHttpServer_Shutdown(t *testing.T) {
+	server := NewNetHttpServer()
+
+	result := make(chan error, 1)
+	go func() { result <- server.Start("127.0.0.1:0") }()
+	time.Sleep(20 * time.Millisecond)
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case err := <-result:
+		if err != http.ErrServerClosed {
+			t.Errorf("Expected http.ErrServerClosed, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected Start to return after Shutdown")
+	}
+}
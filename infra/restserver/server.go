@@ -2,11 +2,18 @@ package restserver
 
 import (
 	"casino/boundary/logging"
+	"casino/infra/middleware"
+	"context"
 	"net/http"
+	"time"
 )
 
 type Server interface {
 	RegisterPublicRoute(method, path string, handler http.HandlerFunc, logger logging.Logger)
+	RegisterStreamingRoute(method, path string, handler http.HandlerFunc, timeout time.Duration, logger logging.Logger)
+	RegisterAdminRoute(method, path string, handler http.HandlerFunc, timeout time.Duration, authenticator middleware.Authenticator, logger logging.Logger)
+	RegisterUserRoute(method, path string, handler http.HandlerFunc, authenticator middleware.Authenticator, logger logging.Logger)
 	RegisterSwaggerRoutes()
 	Start(address string) error
+	Shutdown(ctx context.Context) error
 }
@@ -5,12 +5,19 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"casino/adapter/handler"
+	"casino/boundary/usecase"
 	domainusecases "casino/domain/usecase"
+	"casino/infra/cli"
+	"casino/infra/grpcserver"
 	"casino/infra/kafka"
 	infralogging "casino/infra/logging"
+	"casino/infra/migrations"
+	"casino/infra/pubsub"
 	"casino/infra/repository"
 	"casino/infra/restserver/nethttp"
 
@@ -24,10 +31,36 @@ import (
 // @version 1.0
 // @description A clean architecture implementation of a casino transaction management system
 // @host localhost:8080
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Operator token as "Bearer <token>", signed with CASINO_ADMIN_SECRET
+// @securityDefinitions.apikey UserToken
+// @in header
+// @name Authorization
+// @description User token as "Bearer <token>", signed with CASINO_AUTH_SECRET
 func main() {
 	asyncLogger := infralogging.NewAsyncLogger("casino")
 	simpleLogger := &infralogging.SimpleLogger{}
 	asyncLogger.Register(simpleLogger)
+
+	if logPath := os.Getenv("CASINO_LOG_FILE"); logPath != "" {
+		fileLogger, err := infralogging.NewRotatingFileLogger(infralogging.RotatingFileLoggerConfig{
+			Path:         logPath,
+			MaxSize:      100 * 1024 * 1024,
+			MaxAge:       24 * time.Hour,
+			MaxBackups:   90,
+			MaxBackupAge: 90 * 24 * time.Hour,
+			Compress:     true,
+		})
+		if err != nil {
+			log.Fatal("Failed to open log file:", err)
+		}
+		defer fileLogger.Close()
+		asyncLogger.Register(fileLogger)
+	}
+	// Deferred after the file logger so that it runs first and drains the
+	// queue while the file is still open.
 	defer asyncLogger.Close()
 
 	dsn := "host=localhost user=login password=password dbname=casino_db port=5432 sslmode=disable"
@@ -37,40 +70,197 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		asyncLogger.Error(context.Background(), err)
+		log.Fatal("Failed to connect to database:", err)
+	}
+	embeddedMigrations, err := migrations.Load(migrations.FS())
+	if err != nil {
+		asyncLogger.Error(context.Background(), err)
+		log.Fatal("Invalid migrations:", err)
+	}
+	migrator := migrations.NewMigrator(sqlDB, embeddedMigrations)
+
+	exchangeRateRepo := repository.NewPostgresExchangeRateRepository(db)
+	exchangeRateUseCase := domainusecases.NewExchangeRateUseCaseImpl(exchangeRateRepo)
+
 	transactionRepo := repository.NewPostgresTransactionRepository(db)
-	transactionUseCase := domainusecases.NewTransactionUseCaseImpl(transactionRepo)
+	transactionHub := pubsub.NewTransactionHub(1000, 256)
+	transactionUseCase := domainusecases.NewTransactionUseCaseImpl(transactionRepo, exchangeRateUseCase, transactionHub)
 	transactionHandler := handler.NewTransactionHandler(transactionUseCase, asyncLogger)
+	transactionStreamHandler := handler.NewTransactionStreamHandler(transactionHub, asyncLogger)
+
+	auditRepo := repository.NewPostgresAuditRepository(db)
+	auditUseCase := domainusecases.NewAuditUseCaseImpl(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditUseCase, asyncLogger)
+	adminHandler := handler.NewAdminHandler(transactionUseCase, exchangeRateUseCase, auditUseCase, asyncLogger)
+
+	var authUseCase usecase.AuthUseCase
+	var balanceSocketHandler *handler.BalanceSocketHandler
+	if secret := os.Getenv("CASINO_AUTH_SECRET"); secret != "" {
+		authUseCase = domainusecases.NewAuthUseCaseImpl([]byte(secret))
+		var allowedOrigins []string
+		if origins := os.Getenv("CASINO_WS_ALLOWED_ORIGINS"); origins != "" {
+			allowedOrigins = strings.Split(origins, ",")
+		}
+		balanceSocketHandler = handler.NewBalanceSocketHandler(transactionUseCase, transactionHub, authUseCase, allowedOrigins, asyncLogger)
+	}
+
+	reportingRepo := repository.NewPostgresReportingRepository(db)
+	reportUseCase := domainusecases.NewReportUseCaseImpl(reportingRepo)
+	reportHandler := handler.NewReportHandler(reportUseCase, asyncLogger)
+
+	kafkaConfig := kafka.DefaultConsumerConfig()
+	if configPath := os.Getenv("CASINO_KAFKA_CONFIG"); configPath != "" {
+		kafkaConfig, err = kafka.LoadConsumerConfig(configPath)
+		if err != nil {
+			asyncLogger.Error(context.Background(), err)
+			log.Fatal(err)
+		}
+	}
+	if avroSchemaDir := os.Getenv("CASINO_AVRO_SCHEMA_DIR"); avroSchemaDir != "" {
+		kafkaConfig.AvroSchemaDir = avroSchemaDir
+	}
+	kafkaHandlers := kafka.NewHandlers(transactionUseCase)
+
+	if len(os.Args) > 1 {
+		importUseCase := domainusecases.NewImportUseCaseImpl(transactionRepo, exchangeRateUseCase, auditUseCase)
+		replayer, err := kafka.NewReplayer(kafkaConfig, kafkaHandlers, transactionUseCase, auditUseCase, asyncLogger)
+		if err != nil {
+			asyncLogger.Error(context.Background(), err)
+			log.Fatal("Invalid Kafka consumer config:", err)
+		}
+		if err := cli.NewCLI(reportUseCase, importUseCase, replayer, migrator, os.Stdout).Run(os.Args[1:]); err != nil {
+			asyncLogger.Error(context.Background(), err)
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if os.Getenv("CASINO_REQUIRE_CURRENT_SCHEMA") != "" {
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			asyncLogger.Error(context.Background(), err)
+			log.Fatal("Failed to check the database schema:", err)
+		}
+		if pending > 0 {
+			log.Fatalf("Database schema is behind by %d migrations, run `casino migrate up`", pending)
+		}
+	}
+
+	var kafkaTransport kafka.Transport = kafka.NewBrokerTransport(kafkaConfig.Brokers)
+	if os.Getenv("CASINO_KAFKA_IN_MEMORY") != "" {
+		asyncLogger.Info(context.Background(), "CASINO_KAFKA_IN_MEMORY is set, using the in-process Kafka broker")
+		kafkaTransport = kafka.NewMemoryBroker(1)
+	}
+
+	kafkaConsumer, err := kafka.NewMultiTopicConsumer(kafkaConfig, kafkaTransport, kafkaHandlers, auditUseCase, asyncLogger)
+	if err != nil {
+		asyncLogger.Error(context.Background(), err)
+		log.Fatal("Invalid Kafka consumer config:", err)
+	}
+	kafkaAdminHandler := handler.NewKafkaAdminHandler(kafkaConsumer, auditUseCase, asyncLogger)
 
-	kafkaConsumer := kafka.NewKafkaConsumer(
-		[]string{"localhost:9092"},
-		"casino-transactions-stream",
-		transactionUseCase,
-		asyncLogger,
-	)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 	go kafkaConsumer.Start(ctx)
 
+	outboxRelay := kafka.NewOutboxRelay(
+		kafkaTransport.Writer("casino-transactions-processed"),
+		repository.NewPostgresOutboxRepository(db),
+		asyncLogger,
+	)
+	go outboxRelay.Start(ctx)
+
 	server := nethttp.NewNetHttpServer()
 
 	server.RegisterPublicRoute("GET", "/transactions", transactionHandler.GetAllTransactions, asyncLogger)
 	server.RegisterPublicRoute("GET", "/transactions/user", transactionHandler.GetUserTransactions, asyncLogger)
+	server.RegisterStreamingRoute("GET", "/transactions/export", transactionHandler.ExportTransactions, 30*time.Minute, asyncLogger)
+	server.RegisterStreamingRoute("GET", "/transactions/stream", transactionStreamHandler.StreamTransactions, 0, asyncLogger)
+	server.RegisterPublicRoute("GET", "/rounds/{id}", transactionHandler.GetRound, asyncLogger)
+	if authUseCase != nil {
+		server.RegisterUserRoute("GET", "/users/{id}/balances", transactionHandler.GetUserBalances, authUseCase, asyncLogger)
+		server.RegisterStreamingRoute("GET", "/users/{id}/balance/ws", balanceSocketHandler.StreamBalances, 0, asyncLogger)
+	} else {
+		asyncLogger.Info(context.Background(), "CASINO_AUTH_SECRET is not set, balance routes disabled")
+	}
+	server.RegisterPublicRoute("GET", "/users/{id}/stats", reportHandler.GetUserStats, asyncLogger)
+	server.RegisterPublicRoute("GET", "/reports/ggr", reportHandler.GetGGR, asyncLogger)
+	server.RegisterPublicRoute("GET", "/audit/verify", auditHandler.VerifyChain, asyncLogger)
+	// Operator tokens are signed with their own secret so that a player's
+	// token is never accepted on the admin routes.
+	if secret := os.Getenv("CASINO_ADMIN_SECRET"); secret != "" {
+		adminAuth := domainusecases.NewAuthUseCaseImpl([]byte(secret))
+		server.RegisterAdminRoute("POST", "/admin/transactions/{id}/cancel", adminHandler.CancelTransaction, 0, adminAuth, asyncLogger)
+		server.RegisterAdminRoute("GET", "/admin/exchange-rates", adminHandler.GetExchangeRates, 0, adminAuth, asyncLogger)
+		server.RegisterAdminRoute("POST", "/admin/exchange-rates", adminHandler.CreateExchangeRate, 0, adminAuth, asyncLogger)
+		server.RegisterAdminRoute("POST", "/admin/exchange-rates/import", adminHandler.ImportExchangeRates, 0, adminAuth, asyncLogger)
+		server.RegisterAdminRoute("GET", "/admin/kafka", kafkaAdminHandler.GetStatus, 0, adminAuth, asyncLogger)
+		server.RegisterAdminRoute("POST", "/admin/kafka/pause", kafkaAdminHandler.Pause, 35*time.Second, adminAuth, asyncLogger)
+		server.RegisterAdminRoute("POST", "/admin/kafka/resume", kafkaAdminHandler.Resume, 0, adminAuth, asyncLogger)
+	} else {
+		asyncLogger.Info(context.Background(), "CASINO_ADMIN_SECRET is not set, admin routes disabled")
+	}
 	server.RegisterSwaggerRoutes()
 
-	asyncLogger.Info(context.Background(), "Server starting on port 8080")
-	if err := server.Start(":8080"); err != nil {
-		asyncLogger.Error(context.Background(), err)
-		log.Fatal("Server error:", err)
+	var grpcServer *grpcserver.GRPCServer
+	if tokens := os.Getenv("CASINO_GRPC_TOKENS"); tokens != "" {
+		serviceTokens, err := grpcserver.ParseServiceTokens(tokens)
+		if err != nil {
+			asyncLogger.Error(context.Background(), err)
+			log.Fatal("Invalid CASINO_GRPC_TOKENS:", err)
+		}
+		grpcServer = grpcserver.NewGRPCServer(transactionUseCase, auditUseCase, serviceTokens, asyncLogger)
+	} else {
+		asyncLogger.Info(context.Background(), "CASINO_GRPC_TOKENS is not set, gRPC server disabled")
+	}
+
+	serveErrs := make(chan error, 2)
+	go func() {
+		asyncLogger.Info(context.Background(), "Server starting on port 8080")
+		serveErrs <- server.Start(":8080")
+	}()
+	if grpcServer != nil {
+		go func() {
+			asyncLogger.Info(context.Background(), "gRPC server starting on port 9090")
+			serveErrs <- grpcServer.Start(":9090")
+		}()
 	}
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+
+	var serveErr error
+	select {
+	case <-sigChan:
+	case serveErr = <-serveErrs:
+		asyncLogger.Error(context.Background(), serveErr)
+	}
 
 	asyncLogger.Info(context.Background(), "Shutting down server")
 
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		asyncLogger.Error(context.Background(), err)
+	}
+	if grpcServer != nil {
+		grpcServer.Stop(shutdownCtx)
+	}
+
 	if err := kafkaConsumer.Close(); err != nil {
 		asyncLogger.Error(context.Background(), err)
 		log.Fatal("Kafka consumer close error:", err)
 	}
+
+	cancel()
+	if err := outboxRelay.Close(); err != nil {
+		asyncLogger.Error(context.Background(), err)
+	}
+
+	if serveErr != nil {
+		log.Fatal("Server error:", serveErr)
+	}
 }
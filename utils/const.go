@@ -1,4 +1,6 @@
 package utils
 
 type CtxKey string
-const CtxKeyRequestID CtxKey = "requestID"
\ No newline at end of file
+const CtxKeyRequestID CtxKey = "requestID"
+const CtxKeyLogFields CtxKey = "logFields"
+const CtxKeyActor CtxKey = "actor"
\ No newline at end of file
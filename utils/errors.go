@@ -2,7 +2,7 @@ package utils
 
 import (
 	"fmt"
-	"strings"
+	"time"
 )
 
 type TransactionAlreadyExistsError struct {
@@ -18,13 +18,112 @@ func IsTransactionAlreadyExists(err error) bool {
 	return ok
 }
 
-func IsDatabaseConnectionError(err error) bool {
-	if err == nil {
-		return false
+type TransactionValidationError struct {
+	TransactionID string
+	Reason        string
+}
+
+func (e *TransactionValidationError) Error() string {
+	return fmt.Sprintf("transaction %s is invalid: %s", e.TransactionID, e.Reason)
+}
+
+func IsTransactionValidation(err error) bool {
+	_, ok := err.(*TransactionValidationError)
+	return ok
+}
+
+type RoundNotFoundError struct {
+	RoundID string
+}
+
+func (e *RoundNotFoundError) Error() string {
+	return fmt.Sprintf("round with id %s not found", e.RoundID)
+}
+
+func IsRoundNotFound(err error) bool {
+	_, ok := err.(*RoundNotFoundError)
+	return ok
+}
+
+type TransactionNotFoundError struct {
+	TransactionID string
+}
+
+func (e *TransactionNotFoundError) Error() string {
+	return fmt.Sprintf("transaction with id %s not found", e.TransactionID)
+}
+
+func IsTransactionNotFound(err error) bool {
+	_, ok := err.(*TransactionNotFoundError)
+	return ok
+}
+
+type TransactionAlreadyCancelledError struct {
+	TransactionID string
+}
+
+func (e *TransactionAlreadyCancelledError) Error() string {
+	return fmt.Sprintf("transaction with id %s is already cancelled", e.TransactionID)
+}
+
+func IsTransactionAlreadyCancelled(err error) bool {
+	_, ok := err.(*TransactionAlreadyCancelledError)
+	return ok
+}
+
+type ExchangeRateValidationError struct {
+	Currency string
+	Reason   string
+}
+
+func (e *ExchangeRateValidationError) Error() string {
+	if e.Currency == "" {
+		return fmt.Sprintf("invalid exchange rate: %s", e.Reason)
 	}
-	errStr := err.Error()
-	return strings.Contains(errStr, "failed to connect") ||
-		strings.Contains(errStr, "dial error") ||
-		strings.Contains(errStr, "connection refused") ||
-		strings.Contains(errStr, "No connection could be made")
+	return fmt.Sprintf("invalid exchange rate for %s: %s", e.Currency, e.Reason)
+}
+
+func IsExchangeRateValidation(err error) bool {
+	_, ok := err.(*ExchangeRateValidationError)
+	return ok
+}
+
+type ExchangeRateNotFoundError struct {
+	Currency string
+	At       time.Time
+}
+
+func (e *ExchangeRateNotFoundError) Error() string {
+	return fmt.Sprintf("no exchange rate for %s effective at %s", e.Currency, e.At.Format(time.RFC3339))
+}
+
+func IsExchangeRateNotFound(err error) bool {
+	_, ok := err.(*ExchangeRateNotFoundError)
+	return ok
+}
+
+type ReportValidationError struct {
+	Reason string
+}
+
+func (e *ReportValidationError) Error() string {
+	return fmt.Sprintf("invalid report request: %s", e.Reason)
+}
+
+func IsReportValidation(err error) bool {
+	_, ok := err.(*ReportValidationError)
+	return ok
+}
+
+type UnauthorizedError struct {
+	Reason string
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("unauthorized: %s", e.Reason)
+}
+
+func IsUnauthorized(err error) bool {
+	_, ok := err.(*UnauthorizedError)
+	return ok
 }
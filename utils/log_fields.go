@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"context"
+	"strings"
+)
+
+// LogField is a key/value pair loggers append to every line written with
+// the context it was attached to.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+func WithLogFields(ctx context.Context, fields ...LogField) context.Context {
+	existing := LogFieldsFromCtx(ctx)
+	merged := make([]LogField, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, CtxKeyLogFields, merged)
+}
+
+func LogFieldsFromCtx(ctx context.Context) []LogField {
+	fields, _ := ctx.Value(CtxKeyLogFields).([]LogField)
+	return fields
+}
+
+// FormatLogFields renders fields as space separated key=value pairs.
+func FormatLogFields(fields []LogField) string {
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field.Key + "=" + field.Value
+	}
+	return strings.Join(parts, " ")
+}
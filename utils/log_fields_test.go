@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithLogFields(t *testing.T) {
+	ctx := WithLogFields(context.Background(), LogField{Key: "topic", Value: "transactions"})
+	ctx = WithLogFields(ctx, LogField{Key: "partition", Value: "3"})
+
+	fields := LogFieldsFromCtx(ctx)
+	if len(fields) != 2 {
+		t.Fatalf("Expected 2 fields, got %d", len(fields))
+	}
+
+	if got := FormatLogFields(fields); got != "topic=transactions partition=3" {
+		t.Errorf("Expected formatted fields, got %q", got)
+	}
+}
+
+func TestLogFieldsFromCtx_Empty(t *testing.T) {
+	if fields := LogFieldsFromCtx(context.Background()); fields != nil {
+		t.Errorf("Expected no fields, got %v", fields)
+	}
+}